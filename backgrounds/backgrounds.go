@@ -0,0 +1,81 @@
+// Package backgrounds colors the rays that leave the scene without hitting
+// anything, by the direction they travel in.
+package backgrounds
+
+import (
+	"math"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+)
+
+// Background gives the color seen looking along direction from anywhere in
+// the scene, as if it were infinitely far away.
+type Background interface {
+	ColorAt(direction tuples.Tuple) tuples.Color
+}
+
+// Solid is the same color in every direction.
+type Solid struct {
+	Color tuples.Color
+}
+
+func NewSolid(color tuples.Color) Solid {
+	return Solid{Color: color}
+}
+
+func (b Solid) ColorAt(tuples.Tuple) tuples.Color {
+	return b.Color
+}
+
+// Gradient blends from Bottom, looking straight down, to Top, looking
+// straight up, like a simple sky.
+type Gradient struct {
+	Bottom tuples.Color
+	Top    tuples.Color
+}
+
+func NewGradient(bottom, top tuples.Color) Gradient {
+	return Gradient{Bottom: bottom, Top: top}
+}
+
+func (b Gradient) ColorAt(direction tuples.Tuple) tuples.Color {
+	const half = 0.5
+	t := (tuples.Normalize(direction).Y + 1) * half
+	return b.Bottom.Multiply(1 - t).Add(b.Top.Multiply(t))
+}
+
+// CubeMap is a skybox: a cube textured on the inside, around the scene.
+type CubeMap struct {
+	Texture *patterns.CubeTexture
+}
+
+// NewCubeMap returns a skybox with the faces ordered as for
+// patterns.NewCubeTexture.
+func NewCubeMap(left, front, right, back, up, down patterns.UVPattern) CubeMap {
+	return CubeMap{Texture: patterns.NewCubeTexture(left, front, right, back, up, down)}
+}
+
+// ColorAt projects direction onto the cube from -1 to 1 on every axis and
+// looks up the face it lands on.
+func (b CubeMap) ColorAt(direction tuples.Tuple) tuples.Color {
+	largest := max(math.Abs(direction.X), math.Abs(direction.Y), math.Abs(direction.Z))
+	return b.Texture.PatternAt(tuples.Point(direction.X/largest, direction.Y/largest, direction.Z/largest))
+}
+
+// Equirectangular wraps a UV pattern, usually a latitude-longitude image,
+// around the inside of a sphere enclosing the scene. Looking along +z sees
+// the middle of the image, with u growing towards +x and v towards +y.
+type Equirectangular struct {
+	Texture patterns.UVPattern
+}
+
+func NewEquirectangular(texture patterns.UVPattern) Equirectangular {
+	return Equirectangular{Texture: texture}
+}
+
+func (b Equirectangular) ColorAt(direction tuples.Tuple) tuples.Color {
+	u, v := patterns.SphericalMap(tuples.Point(direction.X, direction.Y, direction.Z))
+	// The spherical map is seen from outside the sphere, so flip it to see
+	// the image the right way round from inside.
+	return b.Texture.UVPatternAt(1-u, v)
+}
@@ -0,0 +1,99 @@
+package backgrounds_test
+
+import (
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uvReporter reports the texture coordinates it is sampled at as a color.
+type uvReporter struct{}
+
+func (uvReporter) UVPatternAt(u, v float64) tuples.Color {
+	return tuples.NewColor(u, v, 0)
+}
+
+func TestSolidBackground(t *testing.T) {
+	// Scenario: A solid background is the same in every direction
+	b := backgrounds.NewSolid(tuples.NewColor(0.1, 0.2, 0.3))
+	assert.Equal(t, tuples.NewColor(0.1, 0.2, 0.3), b.ColorAt(tuples.Vector(0, 1, 0)))
+	assert.Equal(t, tuples.NewColor(0.1, 0.2, 0.3), b.ColorAt(tuples.Vector(-1, 0, 2)))
+}
+
+func TestGradientBackground(t *testing.T) {
+	// Scenario Outline: A gradient background blends from bottom to top
+	// Given b ← gradient(color(0, 0, 0), color(1, 0.5, 1))
+	// Then background_at(b, <direction>) = <color>
+	b := backgrounds.NewGradient(tuples.NewColor(0, 0, 0), tuples.NewColor(1, 0.5, 1))
+	tests := []struct {
+		direction tuples.Tuple
+		expected  tuples.Color
+	}{
+		{tuples.Vector(0, 1, 0), tuples.NewColor(1, 0.5, 1)},
+		{tuples.Vector(0, -2, 0), tuples.NewColor(0, 0, 0)},
+		{tuples.Vector(3, 0, 4), tuples.NewColor(0.5, 0.25, 0.5)},
+		{tuples.Vector(0, 1, -1), tuples.NewColor(0.85355, 0.42678, 0.85355)},
+	}
+	for _, tt := range tests {
+		assert.True(t, tt.expected.Equals(b.ColorAt(tt.direction).Tuple), "looking along %v", tt.direction)
+	}
+}
+
+func TestCubeMapBackground(t *testing.T) {
+	// Scenario Outline: A skybox shows the face each direction points at
+	// Given b ← cube_map(left, front, right, back, up, down), each face a solid align check
+	// Then background_at(b, <direction>) = <face color>
+	solid := func(r, g, b float64) patterns.UVPattern {
+		c := tuples.NewColor(r, g, b)
+		return patterns.NewUVAlignCheck(c, c, c, c, c)
+	}
+	b := backgrounds.NewCubeMap(
+		solid(1, 0, 0), solid(0, 1, 0), solid(0, 0, 1), solid(1, 1, 0), solid(0, 1, 1), solid(1, 0, 1))
+	tests := []struct {
+		direction tuples.Tuple
+		expected  tuples.Color
+	}{
+		{tuples.Vector(-5, 1, 2), tuples.NewColor(1, 0, 0)},
+		{tuples.Vector(0.1, -0.2, 0.3), tuples.NewColor(0, 1, 0)},
+		{tuples.Vector(10, 0, 0), tuples.NewColor(0, 0, 1)},
+		{tuples.Vector(0, 0, -1), tuples.NewColor(1, 1, 0)},
+		{tuples.Vector(1, 2, 1), tuples.NewColor(0, 1, 1)},
+		{tuples.Vector(0, -1, 0), tuples.NewColor(1, 0, 1)},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, b.ColorAt(tt.direction), "looking along %v", tt.direction)
+	}
+}
+
+func TestCubeMapBackgroundCoordinates(t *testing.T) {
+	// Scenario: Directions are projected onto the cube before mapping
+	// Given b ← cube_map(uv_reporter, ...)
+	// Then looking along vector(1, 1, 2) lands at (0.75, 0.75) on the front face
+	b := backgrounds.NewCubeMap(
+		uvReporter{}, uvReporter{}, uvReporter{}, uvReporter{}, uvReporter{}, uvReporter{})
+	assert.True(t, tuples.NewColor(0.75, 0.75, 0).Equals(b.ColorAt(tuples.Vector(1, 1, 2)).Tuple))
+}
+
+func TestEquirectangularBackground(t *testing.T) {
+	// Scenario Outline: An equirectangular background is seen from inside
+	// Given b ← equirectangular(uv_reporter)
+	// Then background_at(b, <direction>) = color(<u>, <v>, 0)
+	b := backgrounds.NewEquirectangular(uvReporter{})
+	tests := []struct {
+		direction tuples.Tuple
+		u, v      float64
+	}{
+		{tuples.Vector(0, 0, 1), 0.5, 0.5},
+		{tuples.Vector(2, 0, 0), 0.75, 0.5},
+		{tuples.Vector(-1, 0, 0), 0.25, 0.5},
+		{tuples.Vector(0, 1, 0), 0.5, 1},
+		{tuples.Vector(0, -1, 1), 0.5, 0.25},
+	}
+	for _, tt := range tests {
+		color := b.ColorAt(tt.direction)
+		assert.True(t, tuples.NewColor(tt.u, tt.v, 0).Equals(color.Tuple), "looking along %v, got %v", tt.direction, color)
+	}
+}
@@ -0,0 +1,134 @@
+// Package bsdfs describes how surfaces scatter light, for materials that are
+// physically based rather than shaded with the Phong model. Unlike Phong's
+// ambient, diffuse and specular knobs, a BSDF never reflects more light than
+// it receives.
+package bsdfs
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+)
+
+// BSDF is a bidirectional scattering distribution function: how much of the
+// light arriving at a surface from one direction leaves it in another, either
+// reflected or transmitted through the surface. Directions are unit vectors
+// pointing away from the surface.
+type BSDF interface {
+	// Evaluate returns the fraction of the light arriving from direction
+	// wi that is scattered towards the surface's Out direction, per unit
+	// of solid angle, multiplied by the cosine of wi to the normal.
+	Evaluate(s Surface, wi tuples.Tuple) tuples.Color
+	// Sample picks a direction for light to arrive from at random, in
+	// proportion to how much the surface scatters from it. It reports
+	// false when the chosen direction carries no light.
+	Sample(s Surface, rng *rand.Rand) (Sample, bool)
+	// PDF returns the probability density, per unit of solid angle, of
+	// Sample choosing wi.
+	PDF(s Surface, wi tuples.Tuple) float64
+	// Specular returns the directions of perfect mirror reflection and
+	// refraction, weighted by the light scattered along each, for
+	// integrators that trace single rays rather than random paths.
+	Specular(s Surface) []Sample
+}
+
+// Surface is the state of the surface at a point being shaded.
+type Surface struct {
+	// Normal is the unit normal on the side of the surface that Out
+	// leaves from.
+	Normal tuples.Tuple
+	// Out is the unit direction towards the eye.
+	Out tuples.Tuple
+	// Color is the color of the material at the point, taken from its
+	// pattern if it has one.
+	Color tuples.Color
+	// Entering reports whether Out is outside the object, so that light
+	// transmitted towards it from within comes out of the object.
+	Entering bool
+}
+
+// Sample is a direction from which light arrives, with the fraction of that
+// light scattered towards the eye divided by the chance of choosing the
+// direction.
+type Sample struct {
+	Direction tuples.Tuple
+	Weight    tuples.Color
+	// PDF is the probability density of choosing Direction, or 0 for the
+	// perfect mirror and refraction directions of Specular.
+	PDF float64
+}
+
+// Frame is an orthonormal basis around a normal, for working with
+// directions in the space where the normal is the z axis.
+type Frame struct {
+	U, V, N tuples.Tuple
+}
+
+// NewFrame returns a frame around the unit normal n. Its other axes are any
+// two unit vectors at right angles to n and to each other.
+func NewFrame(n tuples.Tuple) Frame {
+	helper := tuples.Vector(1, 0, 0)
+	if math.Abs(n.X) > math.Abs(n.Y) {
+		helper = tuples.Vector(0, 1, 0)
+	}
+	u := tuples.Normalize(tuples.Cross(helper, n))
+	return Frame{U: u, V: tuples.Cross(n, u), N: n}
+}
+
+// ToLocal returns world space vector v in the frame's space.
+func (f Frame) ToLocal(v tuples.Tuple) tuples.Tuple {
+	return tuples.Vector(v.Dot(f.U), v.Dot(f.V), v.Dot(f.N))
+}
+
+// ToWorld returns v, given in the frame's space, in world space.
+func (f Frame) ToWorld(v tuples.Tuple) tuples.Tuple {
+	return f.U.Multiply(v.X).Add(f.V.Multiply(v.Y)).Add(f.N.Multiply(v.Z))
+}
+
+// Lambertian is a perfectly matte surface, such as chalk or unfinished
+// wood, that scatters light equally in every direction above it, tinted by
+// its color.
+type Lambertian struct{}
+
+func (Lambertian) Evaluate(s Surface, wi tuples.Tuple) tuples.Color {
+	cosine := wi.Dot(s.Normal)
+	if cosine <= 0 || s.Out.Dot(s.Normal) <= 0 {
+		return black()
+	}
+	return s.Color.Multiply(cosine / math.Pi)
+}
+
+// Sample picks directions in proportion to their cosine to the normal, which
+// cancels the cosine in Evaluate and leaves the color as the weight.
+func (Lambertian) Sample(s Surface, rng *rand.Rand) (Sample, bool) {
+	if s.Out.Dot(s.Normal) <= 0 {
+		return Sample{}, false
+	}
+	local := tuples.Vector(sampling.CosineHemisphere(sampling.Point{X: rng.Float64(), Y: rng.Float64()}))
+	return Sample{Direction: NewFrame(s.Normal).ToWorld(local), Weight: s.Color, PDF: local.Z / math.Pi}, true
+}
+
+func (Lambertian) PDF(s Surface, wi tuples.Tuple) float64 {
+	if s.Out.Dot(s.Normal) <= 0 {
+		return 0
+	}
+	return max(0, wi.Dot(s.Normal)) / math.Pi
+}
+
+func (Lambertian) Specular(Surface) []Sample {
+	return nil
+}
+
+func black() tuples.Color {
+	return tuples.NewColor(0, 0, 0)
+}
+
+func white() tuples.Color {
+	return tuples.NewColor(1, 1, 1)
+}
+
+// reflect returns the direction wo mirrored about the unit vector m.
+func reflect(wo, m tuples.Tuple) tuples.Tuple {
+	return m.Multiply(2 * wo.Dot(m)).Subtract(wo)
+}
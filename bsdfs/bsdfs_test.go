@@ -0,0 +1,152 @@
+package bsdfs_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// surface returns a white surface facing up, seen from the direction at
+// angle theta from the normal.
+func surface(theta float64) bsdfs.Surface {
+	return bsdfs.Surface{
+		Normal:   tuples.Vector(0, 1, 0),
+		Out:      tuples.Vector(math.Sin(theta), math.Cos(theta), 0),
+		Color:    tuples.NewColor(1, 1, 1),
+		Entering: true,
+	}
+}
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2)) // #nosec G404 -- a fixed seed for repeatable tests
+}
+
+// sampled returns the average weight of n samples of b: the fraction of
+// light arriving from all around that it scatters towards the eye.
+func sampled(b bsdfs.BSDF, s bsdfs.Surface, n int) tuples.Color {
+	rng := newRand()
+	sum := tuples.NewColor(0, 0, 0)
+	for range n {
+		if sample, ok := b.Sample(s, rng); ok {
+			sum = sum.Add(sample.Weight)
+		}
+	}
+	return sum.Multiply(1 / float64(n))
+}
+
+// integrated returns the same fraction as sampled, but found by evaluating
+// b in n directions spread evenly over the sphere.
+func integrated(b bsdfs.BSDF, s bsdfs.Surface, n int) tuples.Color {
+	rng := rand.New(rand.NewPCG(3, 4)) // #nosec G404 -- a fixed seed for repeatable tests
+	sum := tuples.NewColor(0, 0, 0)
+	for range n {
+		z := 2*rng.Float64() - 1
+		phi := 2 * math.Pi * rng.Float64()
+		r := math.Sqrt(1 - z*z)
+		sum = sum.Add(b.Evaluate(s, tuples.Vector(r*math.Cos(phi), r*math.Sin(phi), z)))
+	}
+	return sum.Multiply(4 * math.Pi / float64(n))
+}
+
+func TestFrame(t *testing.T) {
+	// Scenario: A frame maps its normal onto the z axis and back
+	// Given f ← frame(normalize(vector(1, 2, 3)))
+	// Then to_local(f, f.n) = vector(0, 0, 1)
+	// And to_world(f, to_local(f, v)) = v for any vector v
+	// And the axes of f are unit vectors at right angles
+	n := tuples.Normalize(tuples.Vector(1, 2, 3))
+	f := bsdfs.NewFrame(n)
+	assert.True(t, tuples.Vector(0, 0, 1).Equals(f.ToLocal(n)))
+	v := tuples.Vector(-2, 0.5, 4)
+	assert.True(t, v.Equals(f.ToWorld(f.ToLocal(v))))
+	assert.InDelta(t, 1, tuples.Magnitude(f.U), 1e-9)
+	assert.InDelta(t, 1, tuples.Magnitude(f.V), 1e-9)
+	assert.InDelta(t, 0, f.U.Dot(f.V), 1e-9)
+	assert.InDelta(t, 0, f.U.Dot(n), 1e-9)
+}
+
+func TestLambertian(t *testing.T) {
+	// Scenario: A Lambertian surface scatters its color equally in every direction
+	// Given s ← a surface of color(0.5, 0.25, 1) facing up
+	// Then evaluate(lambertian, s, up) = color(0.5, 0.25, 1) / π
+	// And light from below the surface is not scattered
+	// And every sample is above the surface, weighted by color(0.5, 0.25, 1)
+	s := surface(0.3)
+	s.Color = tuples.NewColor(0.5, 0.25, 1)
+	b := bsdfs.Lambertian{}
+	assert.True(t, s.Color.Multiply(1/math.Pi).Equals(b.Evaluate(s, tuples.Vector(0, 1, 0)).Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(b.Evaluate(s, tuples.Vector(0, -1, 0)).Tuple))
+	assert.Empty(t, b.Specular(s))
+	rng := newRand()
+	for range 100 {
+		sample, ok := b.Sample(s, rng)
+		assert.True(t, ok)
+		assert.Positive(t, sample.Direction.Y)
+		assert.True(t, s.Color.Equals(sample.Weight.Tuple))
+	}
+}
+
+func TestSamplingMatchesEvaluation(t *testing.T) {
+	// Scenario Outline: Sampling and evaluating a BSDF agree on how much light it scatters
+	// Given s ← a white surface seen at <angle> from its normal
+	// Then the average weight of samples of <bsdf> equals the integral of evaluate(<bsdf>, s, wi) over the sphere
+	// And neither is more than 1
+	bsdfList := []struct {
+		name string
+		bsdf bsdfs.BSDF
+	}{
+		{"lambertian", bsdfs.Lambertian{}},
+		{"rough gold", bsdfs.NewConductor(0.5, bsdfs.Gold().Eta, bsdfs.Gold().K)},
+		{"glossy aluminium", bsdfs.NewConductor(0.3, bsdfs.Aluminium().Eta, bsdfs.Aluminium().K)},
+		{"frosted glass", bsdfs.NewDielectric(0.5, 1.5)},
+		{"rough plastic", bsdfs.NewPrincipled(0, 0.6)},
+		{"rough metal", bsdfs.NewPrincipled(1, 0.4)},
+	}
+	for _, tc := range bsdfList {
+		for _, angle := range []float64{0.2, 1.2} {
+			s := surface(angle)
+			want := integrated(tc.bsdf, s, 400000)
+			got := sampled(tc.bsdf, s, 100000)
+			for _, channel := range []func(tuples.Color) float64{tuples.Color.Red, tuples.Color.Blue} {
+				assert.InDelta(t, channel(want), channel(got), 0.03, "%s at %v", tc.name, angle)
+				assert.LessOrEqual(t, channel(got), 1.01, "%s at %v", tc.name, angle)
+			}
+		}
+	}
+}
+
+func TestSampleWeightsMatchPDF(t *testing.T) {
+	// Scenario Outline: A sample's weight is the BSDF divided by the density of choosing it
+	// Given s ← a white surface seen at 0.7 radians from its normal
+	// When sample ← sample(<bsdf>, s)
+	// Then sample.pdf = pdf(<bsdf>, s, sample.direction)
+	// And sample.weight = evaluate(<bsdf>, s, sample.direction) / sample.pdf
+	bsdfList := []bsdfs.BSDF{
+		bsdfs.Lambertian{},
+		bsdfs.NewConductor(0.4, bsdfs.Copper().Eta, bsdfs.Copper().K),
+		bsdfs.NewDielectric(0.4, 1.5),
+		bsdfs.NewPrincipled(0.5, 0.4),
+	}
+	s := surface(0.7)
+	s.Entering = false
+	rng := newRand()
+	for _, b := range bsdfList {
+		for range 50 {
+			sample, ok := b.Sample(s, rng)
+			if !ok {
+				continue
+			}
+			pdf := b.PDF(s, sample.Direction)
+			require.Positive(t, pdf, "%v", b)
+			assert.InEpsilon(t, pdf, sample.PDF, 1e-6, "%v", b)
+			want := b.Evaluate(s, sample.Direction).Multiply(1 / pdf)
+			assert.InDelta(t, want.Red(), sample.Weight.Red(), 1e-6, "%v", b)
+			assert.InDelta(t, want.Blue(), sample.Weight.Blue(), 1e-6, "%v", b)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package bsdfs
+
+import (
+	"math/rand/v2"
+	"raytracer-vibe/tuples"
+)
+
+// Conductor is a metal, which reflects light off tiny mirror facets tilted
+// at random by its roughness, tinted by the Fresnel reflectance of its
+// complex index of refraction. The color of its material is ignored.
+type Conductor struct {
+	// Roughness runs from 0 for polished metal to 1 for brushed or matte.
+	Roughness float64
+	// Eta and K are the real and imaginary parts of the index of
+	// refraction, for red, green and blue light.
+	Eta, K tuples.Color
+}
+
+func NewConductor(roughness float64, eta, k tuples.Color) Conductor {
+	return Conductor{Roughness: roughness, Eta: eta, K: k}
+}
+
+// Gold returns a polished gold conductor.
+func Gold() Conductor { // nolint: mnd // measured indices of refraction
+	return NewConductor(0, tuples.NewColor(0.143, 0.374, 1.442), tuples.NewColor(3.983, 2.385, 1.603))
+}
+
+// Silver returns a polished silver conductor.
+func Silver() Conductor { // nolint: mnd // measured indices of refraction
+	return NewConductor(0, tuples.NewColor(0.155, 0.117, 0.138), tuples.NewColor(4.828, 3.122, 2.147))
+}
+
+// Copper returns a polished copper conductor.
+func Copper() Conductor { // nolint: mnd // measured indices of refraction
+	return NewConductor(0, tuples.NewColor(0.200, 0.924, 1.102), tuples.NewColor(3.912, 2.452, 2.142))
+}
+
+// Aluminium returns a polished aluminium conductor.
+func Aluminium() Conductor { // nolint: mnd // measured indices of refraction
+	return NewConductor(0, tuples.NewColor(1.657, 0.880, 0.521), tuples.NewColor(9.224, 6.270, 4.837))
+}
+
+// fresnel returns the reflectance of the metal for light arriving at cosine
+// cosI to a facet.
+func (c Conductor) fresnel(cosI float64) tuples.Color {
+	return tuples.NewColor(
+		fresnelConductor(cosI, c.Eta.Red(), c.K.Red()),
+		fresnelConductor(cosI, c.Eta.Green(), c.K.Green()),
+		fresnelConductor(cosI, c.Eta.Blue(), c.K.Blue()))
+}
+
+func (c Conductor) Evaluate(s Surface, wi tuples.Tuple) tuples.Color {
+	frame := NewFrame(s.Normal)
+	wo, wi := frame.ToLocal(s.Out), frame.ToLocal(wi)
+	if wo.Z <= 0 || wi.Z <= 0 {
+		return black()
+	}
+	m := tuples.Normalize(wo.Add(wi))
+	return newGGX(c.Roughness).reflection(wo, wi, c.fresnel(wo.Dot(m)))
+}
+
+// Sample picks a visible facet and mirrors the eye's direction off it.
+func (c Conductor) Sample(s Surface, rng *rand.Rand) (Sample, bool) {
+	frame := NewFrame(s.Normal)
+	wo := frame.ToLocal(s.Out)
+	if wo.Z <= 0 {
+		return Sample{}, false
+	}
+	g := newGGX(c.Roughness)
+	m := g.sampleVisible(wo, rng)
+	wi := reflect(wo, m)
+	if wi.Z <= 0 {
+		return Sample{}, false
+	}
+	weight := c.fresnel(wo.Dot(m)).Multiply(g.g2(wo, wi) / g.g1(wo))
+	return Sample{Direction: frame.ToWorld(wi), Weight: weight, PDF: g.reflectionPDF(wo, wi)}, true
+}
+
+func (c Conductor) PDF(s Surface, wi tuples.Tuple) float64 {
+	frame := NewFrame(s.Normal)
+	wo, wi := frame.ToLocal(s.Out), frame.ToLocal(wi)
+	if wo.Z <= 0 || wi.Z <= 0 {
+		return 0
+	}
+	return newGGX(c.Roughness).reflectionPDF(wo, wi)
+}
+
+func (c Conductor) Specular(s Surface) []Sample {
+	cosine := s.Out.Dot(s.Normal)
+	if cosine <= 0 {
+		return nil
+	}
+	return []Sample{{Direction: reflect(s.Out, s.Normal), Weight: c.fresnel(cosine)}}
+}
@@ -0,0 +1,55 @@
+package bsdfs_test
+
+import (
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConductorReflectsHeadOn(t *testing.T) {
+	// Scenario: A smooth metal mirrors its head on reflectance
+	// Given c ← conductor(0, eta, k) with eta = (0.2, 1, 2) and k = (3, 2, 1)
+	// And s ← a surface seen head on
+	// When samples ← specular(c, s)
+	// Then samples holds one mirror direction straight back at the eye
+	// And its weight is ((eta-1)² + k²) / ((eta+1)² + k²) for each channel
+	c := bsdfs.NewConductor(0, tuples.NewColor(0.2, 1, 2), tuples.NewColor(3, 2, 1))
+	s := surface(0)
+	samples := c.Specular(s)
+	require.Len(t, samples, 1)
+	assert.True(t, s.Out.Equals(samples[0].Direction))
+	want := tuples.NewColor((0.64+9)/(1.44+9), 4.0/8, 2.0/10)
+	assert.True(t, want.Equals(samples[0].Weight.Tuple), "%v", samples[0].Weight)
+}
+
+func TestConductorReflectsMoreAtGrazingAngles(t *testing.T) {
+	// Scenario: Metals reflect almost all the light arriving at a grazing angle
+	// Given c ← gold()
+	// Then specular(c, s) weighs more for s seen at 1.5 radians than head on
+	// And nearly 1 at 1.5 radians
+	c := bsdfs.Gold()
+	headOn := c.Specular(surface(0))[0].Weight
+	grazing := c.Specular(surface(1.5))[0].Weight
+	assert.Greater(t, grazing.Blue(), headOn.Blue())
+	assert.Greater(t, grazing.Red(), 0.95)
+}
+
+func TestConductorIgnoresLightFromBelow(t *testing.T) {
+	// Scenario: Metals are opaque
+	// Given c ← conductor with roughness 0.5
+	// Then evaluate(c, s, wi) is black for wi below the surface
+	// And every sample lies above the surface
+	c := bsdfs.Silver()
+	c.Roughness = 0.5
+	s := surface(0.8)
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(c.Evaluate(s, tuples.Vector(0.6, -0.8, 0)).Tuple))
+	rng := newRand()
+	for range 200 {
+		if sample, ok := c.Sample(s, rng); ok {
+			assert.Positive(t, sample.Direction.Y)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package bsdfs
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/tuples"
+)
+
+// Dielectric is a transparent material such as glass or water, whose tiny
+// facets, tilted at random by its roughness, reflect some light and refract
+// the rest through the surface. Frosted glass is a rough dielectric.
+//
+// Light passing through is tinted by the color of the material. The change
+// in brightness as light is squeezed into a denser medium is left out; it
+// cancels out when the light leaves the object again.
+type Dielectric struct {
+	// Roughness runs from 0 for clear glass to 1 for heavily frosted glass.
+	Roughness float64
+	// IOR is the index of refraction inside the object, relative to the
+	// outside: about 1.33 for water and 1.5 for glass.
+	IOR float64
+}
+
+func NewDielectric(roughness, ior float64) Dielectric {
+	return Dielectric{Roughness: roughness, IOR: ior}
+}
+
+// eta returns the index of refraction beyond the surface relative to that
+// on the eye's side of it.
+func (d Dielectric) eta(s Surface) float64 {
+	if s.Entering {
+		return d.IOR
+	}
+	return 1 / d.IOR
+}
+
+func (d Dielectric) Evaluate(s Surface, wi tuples.Tuple) tuples.Color {
+	frame := NewFrame(s.Normal)
+	wo, wi := frame.ToLocal(s.Out), frame.ToLocal(wi)
+	if wo.Z <= 0 || wi.Z == 0 {
+		return black()
+	}
+	g, eta := newGGX(d.Roughness), d.eta(s)
+	if wi.Z > 0 {
+		m := tuples.Normalize(wo.Add(wi))
+		f := fresnelDielectric(wo.Dot(m), eta)
+		return g.reflection(wo, wi, white().Multiply(f))
+	}
+	m, jacobian, ok := refractingFacet(wo, wi, eta)
+	if !ok {
+		return black()
+	}
+	f := fresnelDielectric(wo.Dot(m), eta)
+	transmitted := (1 - f) * g.d(m) * g.g2(wo, wi) * wo.Dot(m) * jacobian / wo.Z
+	return s.Color.Multiply(transmitted)
+}
+
+// PDF returns the density of choosing wi, from the chance of choosing the
+// facet and of then reflecting off it or refracting through it.
+func (d Dielectric) PDF(s Surface, wi tuples.Tuple) float64 {
+	frame := NewFrame(s.Normal)
+	wo, wi := frame.ToLocal(s.Out), frame.ToLocal(wi)
+	if wo.Z <= 0 || wi.Z == 0 {
+		return 0
+	}
+	g, eta := newGGX(d.Roughness), d.eta(s)
+	if wi.Z > 0 {
+		m := tuples.Normalize(wo.Add(wi))
+		return fresnelDielectric(wo.Dot(m), eta) * g.reflectionPDF(wo, wi)
+	}
+	m, jacobian, ok := refractingFacet(wo, wi, eta)
+	if !ok {
+		return 0
+	}
+	visible := g.g1(wo) * g.d(m) * wo.Dot(m) / wo.Z
+	return (1 - fresnelDielectric(wo.Dot(m), eta)) * visible * jacobian
+}
+
+// refractingFacet returns the normal of the facet that refracts wi, beneath
+// the surface, towards wo, and the density of refracted directions around wi
+// per unit of solid angle around the facet normal. The facet lies halfway
+// between wo and wi once wi is weighted by the index of refraction. It
+// reports false when no facet facing wo refracts between them.
+func refractingFacet(wo, wi tuples.Tuple, eta float64) (tuples.Tuple, float64, bool) {
+	h := wo.Add(wi.Multiply(eta))
+	m := tuples.Normalize(h)
+	if m.Z < 0 {
+		m = tuples.Negate(m)
+	}
+	cosO, cosI := wo.Dot(m), wi.Dot(m)
+	if cosO <= 0 || cosI >= 0 {
+		return m, 0, false
+	}
+	return m, -cosI * eta * eta / h.Dot(h), true
+}
+
+// Sample picks a visible facet, then either mirrors the eye's direction off
+// it or refracts it through, choosing in proportion to how much light each
+// carries.
+func (d Dielectric) Sample(s Surface, rng *rand.Rand) (Sample, bool) {
+	frame := NewFrame(s.Normal)
+	wo := frame.ToLocal(s.Out)
+	if wo.Z <= 0 {
+		return Sample{}, false
+	}
+	g, eta := newGGX(d.Roughness), d.eta(s)
+	m := g.sampleVisible(wo, rng)
+	if rng.Float64() < fresnelDielectric(wo.Dot(m), eta) {
+		wi := reflect(wo, m)
+		if wi.Z <= 0 {
+			return Sample{}, false
+		}
+		direction := frame.ToWorld(wi)
+		weight := white().Multiply(g.g2(wo, wi) / g.g1(wo))
+		return Sample{Direction: direction, Weight: weight, PDF: d.PDF(s, direction)}, true
+	}
+	wi := refract(wo, m, eta)
+	if wi.Z >= 0 {
+		return Sample{}, false
+	}
+	direction := frame.ToWorld(wi)
+	weight := s.Color.Multiply(g.g2(wo, wi) / g.g1(wo))
+	return Sample{Direction: direction, Weight: weight, PDF: d.PDF(s, direction)}, true
+}
+
+func (d Dielectric) Specular(s Surface) []Sample {
+	cosine := s.Out.Dot(s.Normal)
+	if cosine <= 0 {
+		return nil
+	}
+	eta := d.eta(s)
+	f := fresnelDielectric(cosine, eta)
+	samples := []Sample{{Direction: reflect(s.Out, s.Normal), Weight: white().Multiply(f)}}
+	if f < 1 {
+		samples = append(samples, Sample{Direction: refract(s.Out, s.Normal, eta), Weight: s.Color.Multiply(1 - f)})
+	}
+	return samples
+}
+
+// refract returns the direction wo is bent into as it passes through a
+// facet with unit normal m, on wo's side, into a medium with relative index
+// of refraction eta. There must be no total internal reflection.
+func refract(wo, m tuples.Tuple, eta float64) tuples.Tuple {
+	cosI := wo.Dot(m)
+	sin2T := (1 - cosI*cosI) / (eta * eta)
+	cosT := math.Sqrt(max(0, 1-sin2T))
+	return m.Multiply(cosI/eta - cosT).Subtract(wo.Multiply(1 / eta))
+}
@@ -0,0 +1,84 @@
+package bsdfs_test
+
+import (
+	"math"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDielectricHeadOn(t *testing.T) {
+	// Scenario: Clear glass reflects 4% of the light arriving head on and lets the rest through
+	// Given d ← dielectric(0, 1.5)
+	// And s ← a surface of color(1, 0.5, 0.5) seen head on
+	// When samples ← specular(d, s)
+	// Then samples[0] reflects color(0.04, 0.04, 0.04) straight back
+	// And samples[1] passes color(0.96, 0.48, 0.48) straight through
+	d := bsdfs.NewDielectric(0, 1.5)
+	s := surface(0)
+	s.Color = tuples.NewColor(1, 0.5, 0.5)
+	samples := d.Specular(s)
+	require.Len(t, samples, 2)
+	assert.True(t, tuples.Vector(0, 1, 0).Equals(samples[0].Direction))
+	assert.True(t, tuples.NewColor(0.04, 0.04, 0.04).Equals(samples[0].Weight.Tuple))
+	assert.True(t, tuples.Vector(0, -1, 0).Equals(samples[1].Direction))
+	assert.True(t, tuples.NewColor(0.96, 0.48, 0.48).Equals(samples[1].Weight.Tuple))
+}
+
+func TestDielectricBendsLight(t *testing.T) {
+	// Scenario: Light entering glass bends towards the normal, as Snell's law says
+	// Given d ← dielectric(0, 1.5)
+	// And s ← a surface seen at π/4 from outside the glass
+	// When samples ← specular(d, s)
+	// Then the sine of the refracted direction's angle to the normal is sin(π/4) / 1.5
+	d := bsdfs.NewDielectric(0, 1.5)
+	samples := d.Specular(surface(math.Pi / 4))
+	require.Len(t, samples, 2)
+	refracted := samples[1].Direction
+	assert.InDelta(t, 1, tuples.Magnitude(refracted), 1e-9)
+	assert.InDelta(t, -math.Sin(math.Pi/4)/1.5, refracted.X, 1e-9)
+	assert.Negative(t, refracted.Y)
+}
+
+func TestDielectricTotalInternalReflection(t *testing.T) {
+	// Scenario: Light inside glass meeting its surface at a grazing angle is all reflected
+	// Given d ← dielectric(0, 1.5)
+	// And s ← a surface seen at 1 radian from inside the glass
+	// When samples ← specular(d, s)
+	// Then samples holds only the reflection, with weight color(1, 1, 1)
+	d := bsdfs.NewDielectric(0, 1.5)
+	s := surface(1)
+	s.Entering = false
+	samples := d.Specular(s)
+	require.Len(t, samples, 1)
+	assert.True(t, tuples.NewColor(1, 1, 1).Equals(samples[0].Weight.Tuple))
+}
+
+func TestFrostedGlassScattersBothWays(t *testing.T) {
+	// Scenario: Frosted glass lets light through as well as reflecting it
+	// Given d ← dielectric(0.5, 1.5)
+	// And s ← a surface seen head on
+	// When many directions are sampled
+	// Then some lie above the surface and most below it
+	// And light from below is scattered towards the eye
+	d := bsdfs.NewDielectric(0.5, 1.5)
+	s := surface(0)
+	rng := newRand()
+	above, below := 0, 0
+	for range 1000 {
+		sample, ok := d.Sample(s, rng)
+		switch {
+		case !ok:
+		case sample.Direction.Y > 0:
+			above++
+		default:
+			below++
+		}
+	}
+	assert.Positive(t, above)
+	assert.Greater(t, below, 5*above)
+	assert.Positive(t, d.Evaluate(s, tuples.Vector(0, -1, 0)).Red())
+}
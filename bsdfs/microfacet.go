@@ -0,0 +1,132 @@
+package bsdfs
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/tuples"
+)
+
+// minAlpha keeps perfectly smooth surfaces from dividing by zero. Surfaces
+// this smooth are indistinguishable from mirrors.
+const minAlpha = 0.0001
+
+// ggx is the GGX, or Trowbridge-Reitz, distribution of the orientations of
+// the tiny facets that make up a rough surface. Directions are in the local
+// space of the surface, where its normal is the z axis.
+type ggx struct {
+	alpha float64
+}
+
+// newGGX returns the distribution for a surface with roughness from 0,
+// smooth, to 1, as rough as a matte surface. Squaring it gives roughness
+// that looks evenly spaced.
+func newGGX(roughness float64) ggx {
+	return ggx{alpha: max(roughness*roughness, minAlpha)}
+}
+
+// d returns the density of facets facing the unit vector m.
+func (g ggx) d(m tuples.Tuple) float64 {
+	if m.Z <= 0 {
+		return 0
+	}
+	a2 := g.alpha * g.alpha
+	t := m.Z*m.Z*(a2-1) + 1
+	return a2 / (math.Pi * t * t)
+}
+
+// lambda is Smith's auxiliary function, from which the fraction of facets
+// hidden by others when seen from w follows.
+func (g ggx) lambda(w tuples.Tuple) float64 {
+	cos2 := w.Z * w.Z
+	if cos2 == 0 {
+		return math.Inf(1)
+	}
+	tan2 := (1 - cos2) / cos2
+	return (math.Sqrt(1+g.alpha*g.alpha*tan2) - 1) / 2 // nolint: mnd // Smith's closed form for GGX
+}
+
+// g1 returns the fraction of facets visible from w.
+func (g ggx) g1(w tuples.Tuple) float64 {
+	return 1 / (1 + g.lambda(w))
+}
+
+// g2 returns the fraction of facets visible from both wo and wi, allowing
+// for facets that are high enough to be seen from both being more common
+// than chance.
+func (g ggx) g2(wo, wi tuples.Tuple) float64 {
+	return 1 / (1 + g.lambda(wo) + g.lambda(wi))
+}
+
+// sampleVisible picks the normal of a facet seen from wo, in proportion to
+// how much of it is visible, using Heitz's method of sampling the projection
+// of a hemisphere stretched by the roughness.
+func (g ggx) sampleVisible(wo tuples.Tuple, rng *rand.Rand) tuples.Tuple {
+	v := tuples.Normalize(tuples.Vector(g.alpha*wo.X, g.alpha*wo.Y, wo.Z))
+	t1 := tuples.Vector(1, 0, 0)
+	if lengthSquared := v.X*v.X + v.Y*v.Y; lengthSquared > 0 {
+		t1 = tuples.Vector(-v.Y, v.X, 0).Multiply(1 / math.Sqrt(lengthSquared))
+	}
+	t2 := tuples.Cross(v, t1)
+
+	r, phi := math.Sqrt(rng.Float64()), 2*math.Pi*rng.Float64()
+	p1, p2 := r*math.Cos(phi), r*math.Sin(phi)
+	s := (1 + v.Z) / 2 // nolint: mnd // half the disk is hidden at grazing angles
+	p2 = (1-s)*math.Sqrt(1-p1*p1) + s*p2
+	n := t1.Multiply(p1).Add(t2.Multiply(p2)).Add(v.Multiply(math.Sqrt(max(0, 1-p1*p1-p2*p2))))
+	return tuples.Normalize(tuples.Vector(g.alpha*n.X, g.alpha*n.Y, max(0, n.Z)))
+}
+
+// reflectionPDF returns the probability density of sampleVisible choosing
+// the facet that mirrors wo into wi, per unit of solid angle around wi.
+func (g ggx) reflectionPDF(wo, wi tuples.Tuple) float64 {
+	m := tuples.Normalize(wo.Add(wi))
+	return g.g1(wo) * g.d(m) / (4 * wo.Z) // nolint: mnd // the Jacobian of reflection
+}
+
+// reflection returns the light reflected by facets with Fresnel reflectance
+// f from wi towards wo, multiplied by the cosine of wi. Both are above the
+// surface.
+func (g ggx) reflection(wo, wi tuples.Tuple, f tuples.Color) tuples.Color {
+	m := tuples.Normalize(wo.Add(wi))
+	return f.Multiply(g.d(m) * g.g2(wo, wi) / (4 * wo.Z)) // nolint: mnd // the Jacobian of reflection
+}
+
+// fresnelDielectric returns the fraction of unpolarized light reflected off
+// a boundary into a medium with relative index of refraction eta, arriving at
+// cosine cosI to the normal. The rest is transmitted.
+func fresnelDielectric(cosI, eta float64) float64 {
+	sin2T := (1 - cosI*cosI) / (eta * eta)
+	if sin2T >= 1 {
+		return 1
+	}
+	cosT := math.Sqrt(1 - sin2T)
+	rs := (cosI - eta*cosT) / (cosI + eta*cosT)
+	rp := (eta*cosI - cosT) / (eta*cosI + cosT)
+	return (rs*rs + rp*rp) / 2 // nolint: mnd // the average of both polarizations
+}
+
+// fresnelConductor returns the fraction of unpolarized light reflected off
+// a metal with complex index of refraction eta + ik, arriving at cosine cosI
+// to the normal.
+func fresnelConductor(cosI, eta, k float64) float64 {
+	cos2 := cosI * cosI
+	sin2 := 1 - cos2
+	eta2, k2 := eta*eta, k*k
+	t0 := eta2 - k2 - sin2
+	a2b2 := math.Sqrt(t0*t0 + 4*eta2*k2) // nolint: mnd // |n²|, with n = eta + ik
+	t1 := a2b2 + cos2
+	a := math.Sqrt(max(0, (a2b2+t0)/2)) // nolint: mnd // the real part of the complex cosine
+	t2 := 2 * cosI * a
+	rs := (t1 - t2) / (t1 + t2)
+	t3 := cos2*a2b2 + sin2*sin2
+	t4 := t2 * sin2
+	rp := rs * (t3 - t4) / (t3 + t4)
+	return (rs + rp) / 2 // nolint: mnd // the average of both polarizations
+}
+
+// schlick approximates the Fresnel reflectance of a surface reflecting f0
+// of the light arriving head on, for light arriving at cosine cosI.
+func schlick(f0 tuples.Color, cosI float64) tuples.Color {
+	const power = 5
+	return f0.Add(white().Subtract(f0).Multiply(math.Pow(1-cosI, power)))
+}
@@ -0,0 +1,117 @@
+package bsdfs
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+)
+
+// dielectricReflectance is the fraction of light reflected head on by most
+// non-metals, such as plastic, paint or stone.
+const dielectricReflectance = 0.04
+
+// Principled is the metallic/roughness material used by most modelling
+// tools and game engines, covering everything from plastic to metal with
+// two numbers. The material's color is the diffuse color of non-metals and
+// the reflected color of metals.
+type Principled struct {
+	// Metallic blends from 0 for a non-metal, which scatters its color
+	// diffusely under a thin clear coat, to 1 for a metal, which reflects
+	// its color off its facets.
+	Metallic float64
+	// Roughness runs from 0 for polished to 1 for matte.
+	Roughness float64
+}
+
+func NewPrincipled(metallic, roughness float64) Principled {
+	return Principled{Metallic: metallic, Roughness: roughness}
+}
+
+// lobes returns the reflectance of the facets head on and the fraction of
+// the light arriving at cosine cosO that is left for the diffuse base
+// beneath them.
+func (p Principled) lobes(s Surface, cosO float64) (tuples.Color, tuples.Color) {
+	f0 := white().Multiply(dielectricReflectance * (1 - p.Metallic)).Add(s.Color.Multiply(p.Metallic))
+	coated := white().Subtract(schlick(white().Multiply(dielectricReflectance), cosO))
+	return f0, s.Color.Multiply(1 - p.Metallic).Hadamard(coated)
+}
+
+// specularChance returns the chance of Sample choosing a direction from the
+// facets rather than from the diffuse base, in proportion to how much light
+// each scatters.
+func (p Principled) specularChance(s Surface, cosO float64) float64 {
+	f0, diffuse := p.lobes(s, cosO)
+	specular := brightness(schlick(f0, cosO))
+	if specular == 0 {
+		// A black metal seen head on scatters nothing at all.
+		return 1
+	}
+	return specular / (specular + brightness(diffuse))
+}
+
+func (p Principled) Evaluate(s Surface, wi tuples.Tuple) tuples.Color {
+	frame := NewFrame(s.Normal)
+	wo, wi := frame.ToLocal(s.Out), frame.ToLocal(wi)
+	if wo.Z <= 0 || wi.Z <= 0 {
+		return black()
+	}
+	f0, diffuse := p.lobes(s, wo.Z)
+	m := tuples.Normalize(wo.Add(wi))
+	specular := newGGX(p.Roughness).reflection(wo, wi, schlick(f0, wo.Dot(m)))
+	return specular.Add(diffuse.Multiply(wi.Z / math.Pi))
+}
+
+// Sample picks either a visible facet to mirror the eye's direction off or
+// a direction scattered by the diffuse base, and weights it by the chance of
+// either picking it.
+func (p Principled) Sample(s Surface, rng *rand.Rand) (Sample, bool) {
+	frame := NewFrame(s.Normal)
+	wo := frame.ToLocal(s.Out)
+	if wo.Z <= 0 {
+		return Sample{}, false
+	}
+	g := newGGX(p.Roughness)
+	chance := p.specularChance(s, wo.Z)
+	var wi tuples.Tuple
+	if rng.Float64() < chance {
+		wi = reflect(wo, g.sampleVisible(wo, rng))
+	} else {
+		wi = tuples.Vector(sampling.CosineHemisphere(sampling.Point{X: rng.Float64(), Y: rng.Float64()}))
+	}
+	if wi.Z <= 0 {
+		return Sample{}, false
+	}
+	direction := frame.ToWorld(wi)
+	pdf := p.PDF(s, direction)
+	if pdf <= 0 {
+		return Sample{}, false
+	}
+	return Sample{Direction: direction, Weight: p.Evaluate(s, direction).Multiply(1 / pdf), PDF: pdf}, true
+}
+
+// PDF returns the density of choosing wi from either the facets or the
+// diffuse base.
+func (p Principled) PDF(s Surface, wi tuples.Tuple) float64 {
+	frame := NewFrame(s.Normal)
+	wo, wi := frame.ToLocal(s.Out), frame.ToLocal(wi)
+	if wo.Z <= 0 || wi.Z <= 0 {
+		return 0
+	}
+	chance := p.specularChance(s, wo.Z)
+	return chance*newGGX(p.Roughness).reflectionPDF(wo, wi) + (1-chance)*wi.Z/math.Pi
+}
+
+func (p Principled) Specular(s Surface) []Sample {
+	cosine := s.Out.Dot(s.Normal)
+	if cosine <= 0 {
+		return nil
+	}
+	f0, _ := p.lobes(s, cosine)
+	return []Sample{{Direction: reflect(s.Out, s.Normal), Weight: schlick(f0, cosine)}}
+}
+
+func brightness(c tuples.Color) float64 {
+	const channels = 3
+	return (c.Red() + c.Green() + c.Blue()) / channels
+}
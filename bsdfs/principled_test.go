@@ -0,0 +1,49 @@
+package bsdfs_test
+
+import (
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipledReflectance(t *testing.T) {
+	// Scenario Outline: Metals reflect their color and non-metals 4% of the light head on
+	// Given p ← principled(<metallic>, 0)
+	// And s ← a surface of color(1, 0.5, 0) seen head on
+	// When samples ← specular(p, s)
+	// Then samples[0].weight = <reflected>
+	tests := []struct {
+		metallic  float64
+		reflected tuples.Color
+	}{
+		{0, tuples.NewColor(0.04, 0.04, 0.04)},
+		{1, tuples.NewColor(1, 0.5, 0)},
+		{0.5, tuples.NewColor(0.52, 0.27, 0.02)},
+	}
+	for _, tc := range tests {
+		s := surface(0)
+		s.Color = tuples.NewColor(1, 0.5, 0)
+		samples := bsdfs.NewPrincipled(tc.metallic, 0).Specular(s)
+		require.Len(t, samples, 1)
+		assert.True(t, tc.reflected.Equals(samples[0].Weight.Tuple), "metallic %v: %v", tc.metallic, samples[0].Weight)
+	}
+}
+
+func TestPrincipledDiffuseColor(t *testing.T) {
+	// Scenario: Non-metals scatter their color and metals do not
+	// Given s ← a surface of color(1, 0.5, 0) seen head on
+	// And wi ← a direction 1 radian from the normal, well away from the mirror direction
+	// Then evaluate(principled(0, 1), s, wi) is orange, like a Lambertian surface under a clear coat
+	// And evaluate(principled(1, 0), s, wi) is black
+	s := surface(0)
+	s.Color = tuples.NewColor(1, 0.5, 0)
+	wi := surface(1).Out
+	plastic := bsdfs.NewPrincipled(0, 1).Evaluate(s, wi)
+	lambertian := bsdfs.Lambertian{}.Evaluate(s, wi)
+	assert.InDelta(t, lambertian.Red(), plastic.Red(), 0.05)
+	assert.Greater(t, plastic.Green(), plastic.Blue())
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(bsdfs.NewPrincipled(1, 0).Evaluate(s, wi).Tuple))
+}
@@ -0,0 +1,141 @@
+// Package bumps tilts the shading normals of surfaces to give them detail,
+// such as ripples or mortar lines, without extra geometry.
+package bumps
+
+import (
+	"math"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+)
+
+// delta is the distance, in object space, between the points compared to
+// find how a bump changes across the surface.
+const delta = 0.0001
+
+// Bump perturbs the normal of a surface. Both the point and the unit normal
+// are in the object space of the surface, and so is the unit normal returned.
+type Bump interface {
+	Perturb(point, normal tuples.Tuple) tuples.Tuple
+}
+
+// Noise raises the surface by noise, for irregular bumps such as water
+// ripples or orange peel.
+type Noise struct {
+	Noise noise.Source
+	// Frequency is the number of noise cells per unit; higher is finer.
+	Frequency float64
+	// Strength scales how steeply the normal tilts.
+	Strength float64
+}
+
+func NewNoise(source noise.Source, frequency, strength float64) Noise {
+	return Noise{Noise: source, Frequency: frequency, Strength: strength}
+}
+
+func (b Noise) Perturb(point, normal tuples.Tuple) tuples.Tuple {
+	height := func(p tuples.Tuple) float64 {
+		return b.Noise.At(p.Multiply(b.Frequency))
+	}
+	return perturbByHeight(height, b.Strength, point, normal)
+}
+
+// HeightMap raises the surface by the brightness of a pattern, usually a
+// grayscale image wrapped around the object, where white is highest.
+type HeightMap struct {
+	Height   patterns.Pattern
+	Strength float64
+}
+
+func NewHeightMap(height patterns.Pattern, strength float64) HeightMap {
+	return HeightMap{Height: height, Strength: strength}
+}
+
+func (b HeightMap) Perturb(point, normal tuples.Tuple) tuples.Tuple {
+	height := func(p tuples.Tuple) float64 {
+		return brightness(patterns.AtObject(b.Height, p))
+	}
+	return perturbByHeight(height, b.Strength, point, normal)
+}
+
+// NormalMap replaces the normal with one stored in an image, whose red, green
+// and blue channels give the normal's components along the texture's u and v
+// directions and the surface normal, mapped from [-1, 1] to [0, 1].
+type NormalMap struct {
+	Texture *patterns.TextureMap
+}
+
+func NewNormalMap(texture *patterns.TextureMap) NormalMap {
+	return NormalMap{Texture: texture}
+}
+
+func (b NormalMap) Perturb(point, normal tuples.Tuple) tuples.Tuple {
+	const half = 0.5
+	inverse := b.Texture.Transform.Inverse()
+	uv := func(p tuples.Tuple) (float64, float64) {
+		return b.Texture.Mapping(inverse.MultiplyTuple(p))
+	}
+	tangent, bitangent := uvDirections(uv, point, normal)
+	color := patterns.AtObject(b.Texture, point)
+	x := (color.Red() - half) / half
+	y := (color.Green() - half) / half
+	z := (color.Blue() - half) / half
+	return tuples.Normalize(tangent.Multiply(x).Add(bitangent.Multiply(y)).Add(normal.Multiply(z)))
+}
+
+// perturbByHeight tilts normal away from the direction in which height
+// rises along the surface.
+func perturbByHeight(height func(tuples.Tuple) float64, strength float64, point, normal tuples.Tuple) tuples.Tuple {
+	s, t := tangents(normal)
+	ds := (height(point.Add(s.Multiply(delta))) - height(point.Subtract(s.Multiply(delta)))) / (2 * delta)
+	dt := (height(point.Add(t.Multiply(delta))) - height(point.Subtract(t.Multiply(delta)))) / (2 * delta)
+	gradient := s.Multiply(ds).Add(t.Multiply(dt))
+	return tuples.Normalize(normal.Subtract(gradient.Multiply(strength)))
+}
+
+// uvDirections returns unit vectors along the surface in the directions in
+// which the texture coordinates u and v grow, with the v direction made
+// perpendicular to the u direction.
+func uvDirections(
+	uv func(tuples.Tuple) (float64, float64), point, normal tuples.Tuple,
+) (tuples.Tuple, tuples.Tuple) {
+	s, t := tangents(normal)
+	u0, v0 := uv(point)
+	us, vs := uv(point.Add(s.Multiply(delta)))
+	ut, vt := uv(point.Add(t.Multiply(delta)))
+	// The changes in (u, v) along s and t form a 2x2 matrix; inverting it
+	// gives how far to move along s and t to change only u or only v.
+	dus, dvs := seam(us-u0), seam(vs-v0)
+	dut, dvt := seam(ut-u0), seam(vt-v0)
+	det := dus*dvt - dut*dvs
+	if det == 0 {
+		return s, t
+	}
+	tangent := tuples.Normalize(s.Multiply(dvt).Subtract(t.Multiply(dvs)))
+	bitangent := t.Multiply(dus).Subtract(s.Multiply(dut)).Multiply(1 / det)
+	bitangent = tuples.Normalize(bitangent.Subtract(tangent.Multiply(bitangent.Dot(tangent))))
+	return tangent.Multiply(math.Copysign(1, det)), bitangent
+}
+
+// seam returns a change in a texture coordinate measured the short way
+// around, across the seam where coordinates wrap from 1 back to 0.
+func seam(d float64) float64 {
+	return d - math.Round(d)
+}
+
+// tangents returns two unit vectors perpendicular to each other and to the
+// unit vector normal.
+func tangents(normal tuples.Tuple) (tuples.Tuple, tuples.Tuple) {
+	const nearlyParallel = 0.9
+	axis := tuples.Vector(1, 0, 0)
+	if math.Abs(normal.X) > nearlyParallel {
+		axis = tuples.Vector(0, 1, 0)
+	}
+	s := tuples.Normalize(tuples.Cross(normal, axis))
+	return s, tuples.Cross(s, normal)
+}
+
+func brightness(c tuples.Color) float64 {
+	const channels = 3
+	return (c.Red() + c.Green() + c.Blue()) / channels
+}
@@ -0,0 +1,115 @@
+package bumps_test
+
+import (
+	"math"
+	"raytracer-vibe/bumps"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ramp is a pattern whose brightness equals the x coordinate.
+type ramp struct{}
+
+func (ramp) PatternAt(point tuples.Tuple) tuples.Color {
+	return tuples.NewColor(point.X, point.X, point.X)
+}
+
+func (ramp) GetTransform() matrices.Matrix {
+	return matrices.Identity(matrices.DefaultMatrixSize)
+}
+
+func (ramp) SetTransform(matrices.Matrix) {}
+
+// solid is a UV pattern of a single color.
+type solid struct {
+	color tuples.Color
+}
+
+func (p solid) UVPatternAt(_, _ float64) tuples.Color {
+	return p.color
+}
+
+func TestHeightMapTiltsNormalDownhill(t *testing.T) {
+	// Scenario Outline: A height map tilts the normal away from rising ground
+	// Given bump ← height_map(ramp, <strength>), rising by 1 per unit of x
+	// When n ← perturb(bump, point(0.5, 0, 0), vector(0, 1, 0))
+	// Then n = <normal>
+	tests := []struct {
+		strength float64
+		expected tuples.Tuple
+	}{
+		{0, tuples.Vector(0, 1, 0)},
+		{1, tuples.Vector(-math.Sqrt2/2, math.Sqrt2/2, 0)},
+		{-1, tuples.Vector(math.Sqrt2/2, math.Sqrt2/2, 0)},
+	}
+	for _, tt := range tests {
+		bump := bumps.NewHeightMap(ramp{}, tt.strength)
+		n := bump.Perturb(tuples.Point(0.5, 0, 0), tuples.Vector(0, 1, 0))
+		assert.True(t, tt.expected.Equals(n), "strength %v gave %v", tt.strength, n)
+	}
+}
+
+func TestHeightMapIgnoresSlopeAcrossSurface(t *testing.T) {
+	// Scenario: Heights changing along the normal do not tilt it
+	// Given bump ← height_map(ramp, 1)
+	// When n ← perturb(bump, point(0.5, 0, 0), vector(1, 0, 0))
+	// Then n = vector(1, 0, 0)
+	bump := bumps.NewHeightMap(ramp{}, 1)
+	n := bump.Perturb(tuples.Point(0.5, 0, 0), tuples.Vector(1, 0, 0))
+	assert.True(t, tuples.Vector(1, 0, 0).Equals(n))
+}
+
+func TestNoiseBump(t *testing.T) {
+	// Scenario: Noise bumps tilt normals by varying amounts
+	// Given bump ← noise_bump(perlin(1), frequency 4, strength 0.2)
+	// When normals are perturbed at many points of a plane facing up
+	// Then each normal is a unit vector still facing up
+	// And the normals are not all the same
+	bump := bumps.NewNoise(noise.NewPerlin(1), 4, 0.2)
+	normals := map[tuples.Tuple]bool{}
+	for i := range 20 {
+		n := bump.Perturb(tuples.Point(float64(i)*0.13, 0, float64(i)*0.07), tuples.Vector(0, 1, 0))
+		assert.InDelta(t, 1, tuples.Magnitude(n), 0.00001)
+		assert.Positive(t, n.Y)
+		normals[n] = true
+	}
+	assert.Greater(t, len(normals), 10)
+	flat := bumps.NewNoise(noise.NewPerlin(1), 4, 0)
+	assert.True(t, tuples.Vector(0, 1, 0).Equals(flat.Perturb(tuples.Point(0.3, 0, 0.2), tuples.Vector(0, 1, 0))))
+}
+
+func TestNormalMap(t *testing.T) {
+	// Scenario Outline: A normal map points the normal along the texture's directions
+	// Given bump ← normal_map(texture_map(<color>, planar_map)), where u follows x and v follows z
+	// When n ← perturb(bump, point(0.25, 0, 0.5), vector(0, 1, 0))
+	// Then n = <normal>
+	tests := []struct {
+		color    tuples.Color
+		expected tuples.Tuple
+	}{
+		{tuples.NewColor(0.5, 0.5, 1), tuples.Vector(0, 1, 0)},
+		{tuples.NewColor(1, 0.5, 0.5), tuples.Vector(1, 0, 0)},
+		{tuples.NewColor(0.5, 1, 0.5), tuples.Vector(0, 0, 1)},
+		{tuples.NewColor(0, 0.5, 1), tuples.Vector(-math.Sqrt2/2, math.Sqrt2/2, 0)},
+	}
+	for _, tt := range tests {
+		bump := bumps.NewNormalMap(patterns.NewTextureMap(solid{tt.color}, patterns.PlanarMap))
+		n := bump.Perturb(tuples.Point(0.25, 0, 0.5), tuples.Vector(0, 1, 0))
+		assert.True(t, tt.expected.Equals(n), "color %v gave %v", tt.color, n)
+	}
+}
+
+func TestNormalMapFollowsTextureAcrossSeam(t *testing.T) {
+	// Scenario: A normal map finds the u direction across the texture's seam
+	// Given bump ← normal_map(texture_map(color(1, 0.5, 0.5), planar_map))
+	// When n ← perturb(bump, point(0.99995, 0, 0.5), vector(0, 1, 0))
+	// Then n = vector(1, 0, 0)
+	bump := bumps.NewNormalMap(patterns.NewTextureMap(solid{tuples.NewColor(1, 0.5, 0.5)}, patterns.PlanarMap))
+	n := bump.Perturb(tuples.Point(0.99995, 0, 0.5), tuples.Vector(0, 1, 0))
+	assert.True(t, tuples.Vector(1, 0, 0).Equals(n), "got %v", n)
+}
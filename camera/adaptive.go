@@ -0,0 +1,137 @@
+package camera
+
+import (
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+)
+
+// Adaptive configures adaptive supersampling, which only takes extra samples
+// in pixels whose color differs noticeably from their neighbors.
+type Adaptive struct {
+	// Threshold is the largest difference in any color channel that is left
+	// unrefined, both between neighboring pixels and between samples inside
+	// a pixel.
+	Threshold float64
+	// MaxDepth is how many times a pixel may be split into quarters.
+	MaxDepth int
+}
+
+// DefaultAdaptive refines edges of moderate contrast, splitting pixels into
+// at most 16 subpixels.
+func DefaultAdaptive() Adaptive {
+	const threshold, depth = 0.1, 2
+	return Adaptive{Threshold: threshold, MaxDepth: depth}
+}
+
+// MaxSamples returns the number of samples taken in a pixel that is split
+// all the way down: the corners of every subpixel plus their centers.
+func (a Adaptive) MaxSamples() int {
+	side := 1 << a.MaxDepth
+	return (side+1)*(side+1) + side*side
+}
+
+// RenderAdaptive renders the world with one ray through the center of each
+// pixel, then refines the pixels whose contrast with a neighbor exceeds the
+// threshold. Refining a pixel samples its corners and center, and keeps
+// splitting it into quarters while those samples differ by more than the
+// threshold. The camera's Samples, Sampler and Filter are not used. The
+// first ray of each pixel passes through the center of the lens when the
+// shutter opens, and the refining rays spread over the lens and the time
+// the shutter is open. A refined pixel averages its refining rays alone, as
+// its first ray would bias it towards the lens center and the shutter open.
+//
+// The second canvas shows the number of samples taken in each pixel, from
+// black for none to white for a.MaxSamples().
+func (c *Camera) RenderAdaptive(w *world.World, a Adaptive) (*canvas.Canvas, *canvas.Canvas) {
+	coarse := canvas.NewCanvas(c.HSize, c.VSize)
+	c.forEachRow(func(y int) {
+		for x := range c.HSize {
+			coarse.WritePixel(x, y, c.colorAt(w, c.RayForPixel(x, y), x, y, 0))
+		}
+	})
+
+	image := canvas.NewCanvas(c.HSize, c.VSize)
+	density := canvas.NewCanvas(c.HSize, c.VSize)
+	maxSamples := float64(a.MaxSamples())
+	c.forEachRow(func(y int) {
+		for x := range c.HSize {
+			color, samples := coarse.PixelAt(x, y), 1
+			if coarse.Contrast(x, y) > a.Threshold {
+				color, samples = c.refine(w, a, x, y)
+			}
+			level := float64(samples) / maxSamples
+			image.WritePixel(x, y, color)
+			density.WritePixel(x, y, tuples.NewColor(level, level, level))
+		}
+	})
+	return image, density
+}
+
+// pixelSampler caches the samples taken in one pixel, since neighboring
+// subpixels share their corners.
+type pixelSampler struct {
+	camera  *Camera
+	world   *world.World
+	px, py  int
+	samples map[[2]float64]tuples.Color
+}
+
+func (s *pixelSampler) at(u, v float64) tuples.Color {
+	key := [2]float64{u, v}
+	if color, ok := s.samples[key]; ok {
+		return color
+	}
+	// Each new sample takes the next point of the Halton sequence on the
+	// lens and over the shutter interval, which spreads the samples of a
+	// pixel evenly over both. They count from 1, as the first pass's ray
+	// used the path random numbers of sample 0.
+	const baseX, baseY, baseTime = 2, 3, 5
+	i := len(s.samples) + 1
+	lens := sampling.Point{X: sampling.RadicalInverse(baseX, i), Y: sampling.RadicalInverse(baseY, i)}
+	r := s.camera.RayForLensSample(s.px, s.py, u, v, lens)
+	r.Time = s.camera.ShutterTime(sampling.RadicalInverse(baseTime, i))
+	color := s.camera.colorAt(s.world, r, s.px, s.py, i)
+	s.samples[key] = color
+	return color
+}
+
+// refine returns the color of the pixel and the number of samples it averages.
+func (c *Camera) refine(w *world.World, a Adaptive, px, py int) (tuples.Color, int) {
+	s := &pixelSampler{
+		camera:  c,
+		world:   w,
+		px:      px,
+		py:      py,
+		samples: map[[2]float64]tuples.Color{},
+	}
+	color := s.subdivide(a, 0, 0, 1, 0)
+	return color, len(s.samples)
+}
+
+// subdivide returns the average color of the square subpixel with top left
+// corner (u, v), splitting it while its samples disagree.
+func (s *pixelSampler) subdivide(a Adaptive, u, v, size float64, depth int) tuples.Color {
+	const half, quarter = 0.5, 0.25
+	h := size * half
+	corners := []tuples.Color{s.at(u, v), s.at(u+size, v), s.at(u, v+size), s.at(u+size, v+size)}
+	center := s.at(u+h, v+h)
+
+	contrast := 0.0
+	for _, corner := range corners {
+		contrast = max(contrast, corner.Difference(center))
+	}
+	if depth >= a.MaxDepth || contrast <= a.Threshold {
+		sum := center
+		for _, corner := range corners {
+			sum = sum.Add(corner)
+		}
+		return sum.Multiply(1 / float64(len(corners)+1))
+	}
+	return s.subdivide(a, u, v, h, depth+1).
+		Add(s.subdivide(a, u+h, v, h, depth+1)).
+		Add(s.subdivide(a, u, v+h, h, depth+1)).
+		Add(s.subdivide(a, u+h, v+h, h, depth+1)).
+		Multiply(quarter)
+}
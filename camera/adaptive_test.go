@@ -0,0 +1,125 @@
+package camera_test
+
+import (
+	"math"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultWorldCamera() *camera.Camera {
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	return c
+}
+
+func TestMaxSamplesOfAdaptive(t *testing.T) {
+	// Scenario: A fully refined pixel samples the corners and centers of all its subpixels
+	assert.Equal(t, 5, camera.Adaptive{MaxDepth: 0}.MaxSamples())
+	assert.Equal(t, 13, camera.Adaptive{MaxDepth: 1}.MaxSamples())
+	assert.Equal(t, 41, camera.Adaptive{MaxDepth: 2}.MaxSamples())
+}
+
+func TestAdaptiveRenderingLeavesFlatPixelsAlone(t *testing.T) {
+	// Scenario: Pixels in flat regions keep their single center sample
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) looking at the sphere
+	// When image, density ← render_adaptive(c, w)
+	// Then the pixels far from the sphere are black
+	// And they took a single sample
+	w := world.Default()
+	c := defaultWorldCamera()
+	a := camera.DefaultAdaptive()
+	image, density := c.RenderAdaptive(w, a)
+	level := 1 / float64(a.MaxSamples())
+	for _, p := range [][2]int{{0, 0}, {10, 0}, {2, 8}} {
+		assert.True(t, tuples.NewColor(0, 0, 0).Equals(image.PixelAt(p[0], p[1]).Tuple))
+		assert.True(t, tuples.NewColor(level, level, level).Equals(density.PixelAt(p[0], p[1]).Tuple))
+	}
+}
+
+func TestAdaptiveRenderingRefinesEdges(t *testing.T) {
+	// Scenario: Pixels where the sphere's shading changes quickly take more samples
+	w := world.Default()
+	c := defaultWorldCamera()
+	a := camera.DefaultAdaptive()
+	image, density := c.RenderAdaptive(w, a)
+	edge := density.PixelAt(4, 4).Red()
+	assert.Greater(t, edge, 1/float64(a.MaxSamples()))
+	assert.LessOrEqual(t, edge, 1.0)
+	center := w.ColorAt(c.RayForPixel(4, 4))
+	assert.False(t, center.Equals(image.PixelAt(4, 4).Tuple))
+}
+
+func TestAdaptiveRenderingWithoutRefinement(t *testing.T) {
+	// Scenario: With a threshold no contrast exceeds, adaptive rendering casts one ray per pixel
+	w := world.Default()
+	c := defaultWorldCamera()
+	image, _ := c.RenderAdaptive(w, camera.Adaptive{Threshold: 10, MaxDepth: 2})
+	assert.Equal(t, c.Render(w).Pixels, image.Pixels)
+}
+
+func TestAdaptiveRenderingWithSeveralWorkers(t *testing.T) {
+	// Scenario: Adaptive rendering gives the same images whatever the number of workers
+	w := world.Default()
+	c := defaultWorldCamera()
+	c.Workers = 1
+	serial, serialDensity := c.RenderAdaptive(w, camera.DefaultAdaptive())
+	c.Workers = 4
+	concurrent, concurrentDensity := c.RenderAdaptive(w, camera.DefaultAdaptive())
+	assert.Equal(t, serial.Pixels, concurrent.Pixels)
+	assert.Equal(t, serialDensity.Pixels, concurrentDensity.Pixels)
+}
+
+func TestAdaptiveRenderingSpreadsRefiningRaysOverLens(t *testing.T) {
+	// Scenario: Refined pixels of an adaptive render with an aperture sample the lens
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) looking at the sphere, with aperture 1 and focal distance 20
+	// When image ← render_adaptive(c, w) and pinhole ← render_adaptive of the same camera without aperture
+	// Then the refined edge pixel differs between the two
+	// And pixels far from the sphere are the same
+	// And rendering again gives the same image
+	w := world.Default()
+	c := defaultWorldCamera()
+	a := camera.DefaultAdaptive()
+	pinhole, _ := c.RenderAdaptive(w, a)
+	c.Aperture = 1
+	c.FocalDistance = 20
+	image, _ := c.RenderAdaptive(w, a)
+	assert.False(t, pinhole.PixelAt(4, 4).Equals(image.PixelAt(4, 4).Tuple))
+	assert.Equal(t, pinhole.PixelAt(0, 0), image.PixelAt(0, 0))
+	again, _ := c.RenderAdaptive(w, a)
+	assert.Equal(t, image.Pixels, again.Pixels)
+}
+
+func TestAdaptiveRefinementLeavesOutTheFirstRay(t *testing.T) {
+	// Scenario: A refined pixel averages rays spread over the lens and shutter only
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) looking at the sphere, with an aperture and a shutter open from 0 to 1
+	// When image ← render_adaptive(c, w) refining pixels without splitting them
+	// Then the refined center pixel is the average of its corners and center, sampled with
+	// Halton points 1 to 5 on the lens and over the shutter interval
+	w := world.Default()
+	c := defaultWorldCamera()
+	c.Aperture = 1
+	c.FocalDistance = 20
+	c.ShutterClose = 1
+	image, density := c.RenderAdaptive(w, camera.Adaptive{Threshold: 0.1, MaxDepth: 0})
+	offsets := [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {0.5, 0.5}}
+	sum := tuples.NewColor(0, 0, 0)
+	for n, offset := range offsets {
+		i := n + 1
+		lens := sampling.Point{X: sampling.RadicalInverse(2, i), Y: sampling.RadicalInverse(3, i)}
+		r := c.RayForLensSample(5, 5, offset[0], offset[1], lens)
+		r.Time = c.ShutterTime(sampling.RadicalInverse(5, i))
+		sum = sum.Add(w.ColorAt(r))
+	}
+	expected := sum.Multiply(1 / float64(len(offsets)))
+	assert.True(t, expected.Equals(image.PixelAt(5, 5).Tuple), "got %v", image.PixelAt(5, 5))
+	assert.InDelta(t, 1, density.PixelAt(5, 5).Red(), 1e-9)
+}
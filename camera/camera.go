@@ -0,0 +1,353 @@
+package camera
+
+import (
+	"math"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/denoise"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"runtime"
+	"sync"
+)
+
+// Camera maps the pixels of a canvas onto rays cast into the world. For a
+// perspective view the canvas is always one unit in front of the camera.
+type Camera struct {
+	HSize       int
+	VSize       int
+	FieldOfView float64
+	Transform   matrices.Matrix
+	// Projection maps the image onto the scene. The zero value is a
+	// perspective view.
+	Projection Projection
+	// Aperture is the diameter of the camera's lens. Zero makes a pinhole
+	// camera, which keeps everything in focus; wider apertures blur objects
+	// further from the focal plane. Only the perspective projection has a
+	// lens.
+	Aperture float64
+	// FocalDistance is how far in front of the camera the plane in sharp
+	// focus lies.
+	FocalDistance float64
+	// EyeOffset moves the eye this far to the camera's left (or right, when
+	// negative) for one view of a stereo pair. Panoramas move it around a
+	// circle instead, so that the eyes stay apart whichever way they look.
+	EyeOffset float64
+	// Convergence is the distance at which an offset eye's rays cross those
+	// of the camera's center, so that objects there appear at the depth of
+	// the screen. Zero keeps the eyes' views parallel.
+	Convergence float64
+	// ShutterOpen and ShutterClose are the times between which rays are
+	// cast. Shapes that move over that interval are blurred along their
+	// path. Both zero, the default, takes every ray at time zero.
+	ShutterOpen  float64
+	ShutterClose float64
+	// Integrator computes the color seen along each ray. Nil shades with the
+	// Whitted integrator.
+	Integrator world.Integrator
+	// Samples is the number of rays cast per pixel. Zero or one casts a
+	// single ray; grid samplers may round it up to fill their grid.
+	Samples int
+	// Sampler places the samples inside each pixel. Nil uses a regular grid.
+	Sampler sampling.Sampler
+	// Filter combines the samples into pixels. Nil uses a box filter, which
+	// averages the samples inside each pixel.
+	Filter sampling.Filter
+	// Workers is the number of rows rendered concurrently. Zero uses one
+	// worker per CPU.
+	Workers    int
+	halfWidth  float64
+	halfHeight float64
+	pixelSize  float64
+}
+
+func New(hsize, vsize int, fieldOfView float64) *Camera {
+	c := &Camera{
+		HSize:         hsize,
+		VSize:         vsize,
+		FieldOfView:   fieldOfView,
+		Transform:     matrices.Identity(matrices.DefaultMatrixSize),
+		FocalDistance: 1,
+	}
+	c.computePixelSize()
+	return c
+}
+
+func (c *Camera) SetTransform(m matrices.Matrix) {
+	c.Transform = m
+}
+
+// SetSize changes the size of the canvas, keeping the field of view.
+func (c *Camera) SetSize(hsize, vsize int) {
+	c.HSize = hsize
+	c.VSize = vsize
+	c.computePixelSize()
+}
+
+// PixelSize returns the size of a single pixel on the canvas, in world units.
+func (c *Camera) PixelSize() float64 {
+	return c.pixelSize
+}
+
+// RayForPixel returns the ray from the camera through the center of the pixel.
+func (c *Camera) RayForPixel(px, py int) rays.Ray {
+	const half = 0.5
+	return c.RayForSample(px, py, half, half)
+}
+
+// RayForSample returns the ray from the center of the lens through the point
+// of the pixel at offset (u, v), where (0, 0) is the pixel's top left corner
+// and (1, 1) its bottom right corner, cast when the shutter opens.
+func (c *Camera) RayForSample(px, py int, u, v float64) rays.Ray {
+	const half = 0.5
+	return c.RayForLensSample(px, py, u, v, sampling.Point{X: half, Y: half})
+}
+
+// RayForLensSample returns the ray through the point of the pixel at offset
+// (u, v) that leaves the lens at lens, a point of the unit square mapped onto
+// the lens disk. All rays through the same point of the pixel meet on the
+// focal plane. The ray spreads by as much as a pixel covers, so a
+// perspective ray grows by a pixel of the canvas one unit away for every
+// unit it travels, and is cast when the shutter opens.
+func (c *Camera) RayForLensSample(px, py int, u, v float64, lens sampling.Point) rays.Ray {
+	x, y := float64(px)+u, float64(py)+v
+	worldX := c.halfWidth - x*c.pixelSize
+	worldY := c.halfHeight - y*c.pixelSize
+
+	var origin, direction tuples.Tuple
+	spread := c.pixelSize
+	eye := tuples.Vector(c.EyeOffset, 0, 0)
+	switch c.Projection {
+	case Orthographic:
+		origin, direction = c.orthographic(worldX, worldY)
+		spread = 0
+	case Fisheye:
+		origin, direction = tuples.Point(0, 0, 0), c.fisheye(x, y)
+		spread = c.pixelAngle()
+	case Equirectangular:
+		origin, direction = tuples.Point(0, 0, 0), c.equirectangular(x, y)
+		spread = c.pixelAngle()
+		eye = c.panoramaEye(x)
+	case Perspective:
+		if c.Convergence > 0 {
+			// Shifting the eye's canvas lines it up with the center's on
+			// the plane at the convergence distance.
+			worldX -= c.EyeOffset / c.Convergence
+		}
+		origin, direction = c.perspective(worldX, worldY, lens)
+		origin = origin.Add(eye)
+	}
+	if c.EyeOffset != 0 && c.Projection != Perspective {
+		origin, direction = c.offsetEye(origin, direction, eye)
+	}
+
+	inverse := c.Transform.Inverse()
+	r := rays.New(inverse.MultiplyTuple(origin), tuples.Normalize(inverse.MultiplyTuple(direction)))
+	r.Spread = spread
+	r.Time = c.ShutterOpen
+	return r
+}
+
+// perspective returns the origin and direction of the ray in camera space
+// through the point (x, y) of the canvas one unit in front of the camera that
+// leaves the lens at lens.
+func (c *Camera) perspective(x, y float64, lens sampling.Point) (tuples.Tuple, tuples.Tuple) {
+	const half = 0.5
+	// The canvas is one unit in front of the camera, so scaling the point by
+	// the focal distance gives the point it sees on the focal plane.
+	focus := tuples.Point(x*c.FocalDistance, y*c.FocalDistance, -c.FocalDistance)
+	lensX, lensY := sampling.ConcentricDisk(lens)
+	radius := c.Aperture * half
+	origin := tuples.Point(lensX*radius, lensY*radius, 0)
+	return origin, focus.Subtract(origin)
+}
+
+// offsetEye moves the ray from origin along direction to the eye at offset
+// from it, turning it to cross the original ray at the convergence distance.
+func (c *Camera) offsetEye(origin, direction, offset tuples.Tuple) (tuples.Tuple, tuples.Tuple) {
+	eye := origin.Add(offset)
+	if c.Convergence <= 0 {
+		return eye, direction
+	}
+	target := origin.Add(tuples.Normalize(direction).Multiply(c.Convergence))
+	return eye, target.Subtract(eye)
+}
+
+// ShutterTime returns the time a fraction f of the way through the interval
+// the shutter is open.
+func (c *Camera) ShutterTime(f float64) float64 {
+	return c.ShutterOpen + f*(c.ShutterClose-c.ShutterOpen)
+}
+
+// Render casts rays through every pixel and records the color seen in the
+// world, rendering rows concurrently. The image only depends on the camera's
+// settings, not on the number of workers.
+func (c *Camera) Render(w *world.World) *canvas.Canvas {
+	filter := c.Filter
+	if filter == nil {
+		filter = sampling.NewBox()
+	}
+	return c.renderPasses(filter, func(r rays.Ray, x, y, i int) []tuples.Color {
+		return []tuples.Color{c.colorAt(w, r, x, y, i)}
+	})[0]
+}
+
+// RenderFeatures casts the same rays as Render, but records the features of
+// what they first see instead of its color, each averaged over the samples
+// of a pixel, for guiding a denoiser.
+func (c *Camera) RenderFeatures(w *world.World) denoise.Features {
+	passes := c.renderPasses(sampling.NewBox(), func(r rays.Ray, _, _, _ int) []tuples.Color {
+		f := w.FeaturesAt(r)
+		return []tuples.Color{
+			f.Albedo,
+			tuples.NewColor(f.Normal.X, f.Normal.Y, f.Normal.Z),
+			tuples.NewColor(f.Depth, f.Depth, f.Depth),
+		}
+	})
+	return denoise.Features{Albedo: passes[0], Normal: passes[1], Depth: passes[2]}
+}
+
+// RenderAOVs casts the same rays as Render, but records the AOVs of what
+// they first see instead of its color, each averaged over the samples of a
+// pixel, in a layer named after each of w.AOVNames.
+func (c *Camera) RenderAOVs(w *world.World, aovs []world.AOV) []canvas.Layer {
+	passes := c.renderPasses(sampling.NewBox(), func(r rays.Ray, _, _, _ int) []tuples.Color {
+		return w.AOVsAt(r, aovs)
+	})
+	names := w.AOVNames(aovs)
+	layers := make([]canvas.Layer, len(names))
+	for i, name := range names {
+		layers[i] = canvas.Layer{Name: name, Canvas: passes[i]}
+	}
+	return layers
+}
+
+// shader returns the colors of each pass seen along r, cast as sample i of
+// pixel (x, y).
+type shader func(r rays.Ray, x, y, i int) []tuples.Color
+
+// renderPasses casts the rays for every pixel, rendering rows concurrently,
+// and reconstructs a canvas with filter from each of the colors shade
+// returns for the rays.
+func (c *Camera) renderPasses(filter sampling.Filter, shade shader) []*canvas.Canvas {
+	sampler := c.Sampler
+	if sampler == nil {
+		sampler = sampling.NewRegular()
+	}
+
+	type row struct {
+		y       int
+		samples [][]sampling.Sample
+	}
+	done := make(chan row)
+	go func() {
+		c.forEachRow(func(y int) {
+			done <- row{y: y, samples: c.sampleRow(sampler, y, shade)}
+		})
+		close(done)
+	}()
+
+	// Rows finish in any order, but are added to the films in order so that
+	// floating point sums come out the same on every render.
+	var films []*sampling.Film
+	pending := map[int][][]sampling.Sample{}
+	next := 0
+	for r := range done {
+		pending[r.y] = r.samples
+		for passes, ok := pending[next]; ok; passes, ok = pending[next] {
+			for len(films) < len(passes) {
+				films = append(films, sampling.NewFilm(c.HSize, c.VSize, filter))
+			}
+			for pass, samples := range passes {
+				for _, s := range samples {
+					films[pass].AddSample(s)
+				}
+			}
+			delete(pending, next)
+			next++
+		}
+	}
+	canvases := make([]*canvas.Canvas, len(films))
+	for i, film := range films {
+		canvases[i] = film.Canvas()
+	}
+	return canvases
+}
+
+// sampleRow casts the rays for every pixel in row y, returning the samples
+// of each pass that shade colors them for.
+func (c *Camera) sampleRow(sampler sampling.Sampler, y int, shade shader) [][]sampling.Sample {
+	var passes [][]sampling.Sample
+	for x := range c.HSize {
+		points := sampler.Samples(x, y, max(1, c.Samples))
+		// The lens reuses the pixel's samples in another order, so that
+		// antialiasing and depth of field share the same even spread.
+		lens := sampling.Shuffled(points, x, y)
+		times := sampling.Times(x, y, len(points))
+		for i, p := range points {
+			r := c.RayForLensSample(x, y, p.X, p.Y, lens[i])
+			r.Time = c.ShutterTime(times[i])
+			for pass, color := range shade(r, x, y, i) {
+				if pass == len(passes) {
+					passes = append(passes, make([]sampling.Sample, 0, c.HSize*len(points)))
+				}
+				passes[pass] = append(passes[pass], sampling.Sample{
+					X:     float64(x) + p.X,
+					Y:     float64(y) + p.Y,
+					Color: color,
+				})
+			}
+		}
+	}
+	return passes
+}
+
+// colorAt returns the color the camera's integrator sees along r, cast as
+// sample i of pixel (px, py).
+func (c *Camera) colorAt(w *world.World, r rays.Ray, px, py, i int) tuples.Color {
+	if c.Integrator == nil {
+		return w.ColorAt(r)
+	}
+	return c.Integrator.ColorAt(w, r, sampling.PathRand(px, py, i))
+}
+
+// forEachRow calls fn for every row of the image, spread over the camera's
+// workers, and waits for all rows to finish.
+func (c *Camera) forEachRow(fn func(y int)) {
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, c.VSize) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for y := range rows {
+				fn(y)
+			}
+		}()
+	}
+	for y := range c.VSize {
+		rows <- y
+	}
+	close(rows)
+	wg.Wait()
+}
+
+func (c *Camera) computePixelSize() {
+	const two = 2
+	halfView := math.Tan(c.FieldOfView / two)
+	aspect := float64(c.HSize) / float64(c.VSize)
+	if aspect >= 1 {
+		c.halfWidth = halfView
+		c.halfHeight = halfView / aspect
+	} else {
+		c.halfWidth = halfView * aspect
+		c.halfHeight = halfView
+	}
+	c.pixelSize = (c.halfWidth * two) / float64(c.HSize)
+}
@@ -0,0 +1,371 @@
+package camera_test
+
+import (
+	"math"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructingCamera(t *testing.T) {
+	// Scenario: Constructing a camera
+	// Given hsize ← 160
+	// And vsize ← 120
+	// And field_of_view ← π/2
+	// When c ← camera(hsize, vsize, field_of_view)
+	// Then c.hsize = 160
+	// And c.vsize = 120
+	// And c.field_of_view = π/2
+	// And c.transform = identity_matrix
+	c := camera.New(160, 120, math.Pi/2)
+	assert.Equal(t, 160, c.HSize)
+	assert.Equal(t, 120, c.VSize)
+	assert.InEpsilon(t, math.Pi/2, c.FieldOfView, 0.00001)
+	assert.True(t, c.Transform.Equals(matrices.Identity(4)))
+}
+
+func TestPixelSizeForHorizontalCanvas(t *testing.T) {
+	// Scenario: The pixel size for a horizontal canvas
+	// Given c ← camera(200, 125, π/2)
+	// Then c.pixel_size = 0.01
+	c := camera.New(200, 125, math.Pi/2)
+	assert.InEpsilon(t, 0.01, c.PixelSize(), 0.00001)
+}
+
+func TestPixelSizeForVerticalCanvas(t *testing.T) {
+	// Scenario: The pixel size for a vertical canvas
+	// Given c ← camera(125, 200, π/2)
+	// Then c.pixel_size = 0.01
+	c := camera.New(125, 200, math.Pi/2)
+	assert.InEpsilon(t, 0.01, c.PixelSize(), 0.00001)
+}
+
+func TestRayThroughCenterOfCanvas(t *testing.T) {
+	// Scenario: Constructing a ray through the center of the canvas
+	// Given c ← camera(201, 101, π/2)
+	// When r ← ray_for_pixel(c, 100, 50)
+	// Then r.origin = point(0, 0, 0)
+	// And r.direction = vector(0, 0, -1)
+	c := camera.New(201, 101, math.Pi/2)
+	r := c.RayForPixel(100, 50)
+	assert.True(t, tuples.Point(0, 0, 0).Equals(r.Origin))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(r.Direction))
+}
+
+func TestRayThroughCornerOfCanvas(t *testing.T) {
+	// Scenario: Constructing a ray through a corner of the canvas
+	// Given c ← camera(201, 101, π/2)
+	// When r ← ray_for_pixel(c, 0, 0)
+	// Then r.origin = point(0, 0, 0)
+	// And r.direction = vector(0.66519, 0.33259, -0.66851)
+	c := camera.New(201, 101, math.Pi/2)
+	r := c.RayForPixel(0, 0)
+	assert.True(t, tuples.Point(0, 0, 0).Equals(r.Origin))
+	assert.True(t, tuples.Vector(0.66519, 0.33259, -0.66851).Equals(r.Direction))
+}
+
+func TestRayWhenCameraIsTransformed(t *testing.T) {
+	// Scenario: Constructing a ray when the camera is transformed
+	// Given c ← camera(201, 101, π/2)
+	// When c.transform ← rotation_y(π/4) * translation(0, -2, 5)
+	// And r ← ray_for_pixel(c, 100, 50)
+	// Then r.origin = point(0, 2, -5)
+	// And r.direction = vector(√2/2, 0, -√2/2)
+	c := camera.New(201, 101, math.Pi/2)
+	c.SetTransform(matrices.RotationY(math.Pi / 4).Multiply(matrices.Translation(0, -2, 5)))
+	r := c.RayForPixel(100, 50)
+	val := math.Sqrt(2) / 2
+	assert.True(t, tuples.Point(0, 2, -5).Equals(r.Origin))
+	assert.True(t, tuples.Vector(val, 0, -val).Equals(r.Direction))
+}
+
+func TestRenderingWorldWithCamera(t *testing.T) {
+	// Scenario: Rendering a world with a camera
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2)
+	// And from ← point(0, 0, -5)
+	// And to ← point(0, 0, 0)
+	// And up ← vector(0, 1, 0)
+	// And c.transform ← view_transform(from, to, up)
+	// When image ← render(c, w)
+	// Then pixel_at(image, 5, 5) = color(0.38066, 0.47583, 0.2855)
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	image := c.Render(w)
+	assert.True(t, tuples.NewColor(0.38066, 0.47583, 0.2855).Equals(image.PixelAt(5, 5).Tuple))
+}
+
+func TestRenderingFeatures(t *testing.T) {
+	// Scenario: Rendering the features of what each pixel sees
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) at point(0, 0, -5), looking at the origin
+	// When features ← render_features(c, w)
+	// Then the center pixel sees albedo color(0.8, 1.0, 0.6), normal (0, 0, -1) and depth 4
+	// And the corner pixel sees nothing
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	f := c.RenderFeatures(w)
+	assert.True(t, tuples.NewColor(0.8, 1.0, 0.6).Equals(f.Albedo.PixelAt(5, 5).Tuple))
+	assert.True(t, tuples.NewColor(0, 0, -1).Equals(f.Normal.PixelAt(5, 5).Tuple))
+	assert.True(t, tuples.NewColor(4, 4, 4).Equals(f.Depth.PixelAt(5, 5).Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(f.Depth.PixelAt(0, 0).Tuple))
+}
+
+func TestRenderingAOVs(t *testing.T) {
+	// Scenario: Rendering AOVs into named layers
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) at point(0, 0, -5), looking at the origin
+	// When layers ← render_aovs(c, w, [depth, lights])
+	// Then the layers are named "depth" and "light-1"
+	// And the center pixel has depth 4 and is lit by color(0.38066, 0.47583, 0.2855)
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	layers := c.RenderAOVs(w, []world.AOV{world.DepthAOV, world.LightsAOV})
+	require.Len(t, layers, 2)
+	assert.Equal(t, "depth", layers[0].Name)
+	assert.Equal(t, "light-1", layers[1].Name)
+	assert.True(t, tuples.NewColor(4, 4, 4).Equals(layers[0].Canvas.PixelAt(5, 5).Tuple))
+	assert.True(t, tuples.NewColor(0.38066, 0.47583, 0.2855).Equals(layers[1].Canvas.PixelAt(5, 5).Tuple))
+}
+
+func TestRayForSampleAtPixelCorner(t *testing.T) {
+	// Scenario: A sample at the top left corner of the center pixel
+	// Given c ← camera(201, 101, π/2)
+	// When r ← ray_for_sample(c, 100, 50, 0, 0)
+	// Then r.direction points half a pixel up and left of the center
+	c := camera.New(201, 101, math.Pi/2)
+	r := c.RayForSample(100, 50, 0, 0)
+	half := c.PixelSize() / 2
+	expected := tuples.Normalize(tuples.Vector(half, half, -1))
+	assert.True(t, expected.Equals(r.Direction))
+}
+
+func TestRaySpreadsByPixelSize(t *testing.T) {
+	// Scenario: Camera rays spread by the size of a pixel per unit travelled
+	// Given c ← camera(201, 101, π/2)
+	// When r ← ray_for_pixel(c, 100, 50)
+	// Then r.spread = c.pixel_size
+	c := camera.New(201, 101, math.Pi/2)
+	r := c.RayForPixel(100, 50)
+	assert.InEpsilon(t, c.PixelSize(), r.Spread, 0.00001)
+}
+
+func TestSetSizeKeepsFieldOfView(t *testing.T) {
+	// Scenario: Resizing a camera recomputes the pixel size
+	// Given c ← camera(160, 120, π/2)
+	// When c is resized to 200x125
+	// Then c.pixel_size = 0.01
+	c := camera.New(160, 120, math.Pi/2)
+	c.SetSize(200, 125)
+	assert.Equal(t, 200, c.HSize)
+	assert.Equal(t, 125, c.VSize)
+	assert.InEpsilon(t, 0.01, c.PixelSize(), 0.00001)
+}
+
+func TestRenderingWithSeveralWorkers(t *testing.T) {
+	// Scenario: Rendering concurrently gives the same image as rendering serially
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	c.Workers = 1
+	serial := c.Render(w)
+	c.Workers = 4
+	concurrent := c.Render(w)
+	assert.Equal(t, serial.Pixels, concurrent.Pixels)
+}
+
+func TestPathTracedRenderIsRepeatable(t *testing.T) {
+	// Scenario: Path traced renders do not depend on the number of workers
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) with the path tracer and 4 samples per pixel
+	// When the image is rendered with 1 worker, and again with 4 workers
+	// Then the images are the same
+	// And they differ from the Whitted image
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	c.Samples = 4
+	whitted := c.Render(w)
+	c.Integrator = world.NewPathTracer()
+	c.Workers = 1
+	serial := c.Render(w)
+	c.Workers = 4
+	concurrent := c.Render(w)
+	assert.Equal(t, serial.Pixels, concurrent.Pixels)
+	assert.NotEqual(t, whitted.Pixels, serial.Pixels)
+}
+
+func TestRenderingWithSeveralSamples(t *testing.T) {
+	// Scenario: Rendering several samples per pixel averages a regular grid of rays
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) with 4 samples per pixel
+	// When image ← render(c, w)
+	// Then the pixel on the sphere's silhouette is the average of the rays
+	//   through (0.25, 0.25), (0.75, 0.25), (0.25, 0.75) and (0.75, 0.75)
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	c.Samples = 4
+	image := c.Render(w)
+	expected := tuples.NewColor(0, 0, 0)
+	for _, uv := range [][2]float64{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}} {
+		expected = expected.Add(w.ColorAt(c.RayForSample(4, 5, uv[0], uv[1])))
+	}
+	expected = expected.Multiply(0.25)
+	assert.True(t, expected.Equals(image.PixelAt(4, 5).Tuple))
+	assert.Greater(t, image.PixelAt(4, 5).Red(), 0.0)
+	assert.Less(t, image.PixelAt(4, 5).Red(), w.ColorAt(c.RayForSample(4, 5, 0.75, 0.5)).Red())
+}
+
+func TestRenderingWithSamplerAndFilterIsDeterministic(t *testing.T) {
+	// Scenario: Rendering with a seeded sampler and a wide filter gives the same image every time
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	c.Samples = 4
+	c.Sampler = sampling.NewJittered(42)
+	c.Filter = sampling.NewMitchell()
+	c.Workers = 1
+	serial := c.Render(w)
+	c.Workers = 4
+	concurrent := c.Render(w)
+	assert.Equal(t, serial.Pixels, concurrent.Pixels)
+	c.Sampler = sampling.NewJittered(43)
+	assert.NotEqual(t, serial.Pixels, c.Render(w).Pixels)
+}
+
+func TestLensRaysMeetOnFocalPlane(t *testing.T) {
+	// Scenario: Rays through the same point of a pixel meet on the focal plane
+	// Given c ← camera(201, 101, π/2) with aperture 0.5 and focal distance 4
+	// When rays through pixel (30, 20) leave different points of the lens
+	// Then they all start within the aperture on the plane of the lens
+	// And they all reach the same point four units in front of the camera
+	// And that point lies on the ray through the center of the lens
+	c := camera.New(201, 101, math.Pi/2)
+	c.Aperture = 0.5
+	c.FocalDistance = 4
+	pinhole := c.RayForSample(30, 20, 0.5, 0.5)
+	focus := pinhole.Position(4 / -pinhole.Direction.Z)
+	for _, lens := range []sampling.Point{{X: 0, Y: 0}, {X: 1, Y: 0.5}, {X: 0.2, Y: 0.9}, {X: 0.5, Y: 0.5}} {
+		r := c.RayForLensSample(30, 20, 0.5, 0.5, lens)
+		assert.InDelta(t, 0, r.Origin.Z, 1e-9)
+		assert.LessOrEqual(t, math.Hypot(r.Origin.X, r.Origin.Y), 0.25+1e-9)
+		assert.True(t, focus.Equals(r.Position(4/-r.Direction.Z)), "through lens point %v", lens)
+	}
+	assert.NotEqual(t, c.RayForLensSample(30, 20, 0.5, 0.5, sampling.Point{X: 0, Y: 0}).Origin, pinhole.Origin)
+}
+
+func TestLensRaysWhenCameraIsTransformed(t *testing.T) {
+	// Scenario: The lens moves and turns with the camera
+	// Given c ← camera(201, 101, π/2) with aperture 2 and focal distance 3
+	// And c.transform ← rotation_y(π/4) * translation(0, -2, 5)
+	// When r ← ray_for_lens_sample(c, 100, 50, 0.5, 0.5, point(1, 0.5))
+	// Then r.origin is one unit along the camera's x axis from the camera
+	// And r passes through the center of the view three units away
+	c := camera.New(201, 101, math.Pi/2)
+	c.Aperture = 2
+	c.FocalDistance = 3
+	c.SetTransform(matrices.RotationY(math.Pi / 4).Multiply(matrices.Translation(0, -2, 5)))
+	r := c.RayForLensSample(100, 50, 0.5, 0.5, sampling.Point{X: 1, Y: 0.5})
+	inverse := c.Transform.Inverse()
+	assert.True(t, inverse.MultiplyTuple(tuples.Point(1, 0, 0)).Equals(r.Origin))
+	target := inverse.MultiplyTuple(tuples.Point(0, 0, -3))
+	assert.True(t, tuples.Normalize(target.Subtract(r.Origin)).Equals(r.Direction))
+}
+
+func TestZeroApertureIsPinhole(t *testing.T) {
+	// Scenario: Without an aperture the lens position makes no difference
+	// Given c ← camera(201, 101, π/2) with focal distance 10
+	// Then every ray_for_lens_sample through a pixel equals ray_for_sample
+	c := camera.New(201, 101, math.Pi/2)
+	c.FocalDistance = 10
+	expected := c.RayForSample(12, 34, 0.3, 0.6)
+	for _, lens := range []sampling.Point{{X: 0, Y: 0}, {X: 0.9, Y: 0.1}} {
+		r := c.RayForLensSample(12, 34, 0.3, 0.6, lens)
+		assert.True(t, expected.Origin.Equals(r.Origin))
+		assert.True(t, expected.Direction.Equals(r.Direction))
+	}
+}
+
+func TestDepthOfFieldBlursObjectsOutOfFocus(t *testing.T) {
+	// Scenario: Only objects near the focal plane stay sharp
+	// Given w ← default_world()
+	// And c ← camera(21, 21, π/3) five units from the sphere, with 16 samples per pixel
+	// When sharp ← render(c, w) with aperture 2 and focal distance 4.9, the sphere's silhouette
+	// And blurred ← render(c, w) with aperture 2 and focal distance 20
+	// Then the sharpest edge of sharp has more contrast than that of blurred
+	// And rendering again gives the same image
+	w := world.Default()
+	c := camera.New(21, 21, math.Pi/3)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	c.Samples = 16
+	c.Aperture = 2
+	c.FocalDistance = 4.9
+	sharp := c.Render(w)
+	c.FocalDistance = 20
+	blurred := c.Render(w)
+	contrast := func(image *canvas.Canvas) float64 {
+		highest := 0.0
+		for y := range image.Height {
+			for x := range image.Width {
+				highest = max(highest, image.Contrast(x, y))
+			}
+		}
+		return highest
+	}
+	assert.Greater(t, contrast(sharp), 2*contrast(blurred))
+	assert.Equal(t, blurred.Pixels, c.Render(w).Pixels)
+}
+
+func TestRaysAreCastWhenShutterOpens(t *testing.T) {
+	// Scenario: Single rays are cast when the shutter opens
+	// Given c ← camera(11, 11, π/2) with its shutter open from time 0.25 to 0.75
+	// Then ray_for_pixel(c, 5, 5).time = 0.25
+	// And shutter_time(c, 0.5) = 0.5
+	c := camera.New(11, 11, math.Pi/2)
+	c.ShutterOpen = 0.25
+	c.ShutterClose = 0.75
+	assert.InDelta(t, 0.25, c.RayForPixel(5, 5).Time, 0)
+	assert.InDelta(t, 0.5, c.ShutterTime(0.5), 0)
+}
+
+func TestMotionBlur(t *testing.T) {
+	// Scenario: An object moving while the shutter is open blurs along its path
+	// Given w ← world with a white sphere moving from translation(-2, 0, 0) to translation(2, 0, 0)
+	// And c ← camera(21, 11, π/2) five units away, with 16 samples per pixel
+	// When still ← render(c, w) with the shutter closed
+	// And blurred ← render(c, w) with the shutter open from time 0 to 1
+	// Then the pixel at the sphere's start is lit in still
+	// And the pixel between its start and end is dark in still but partly lit in blurred
+	// And rendering again gives the same image
+	w := world.New()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	s := spheres.NewSphere()
+	s.SetMotion(matrices.NewMotion(matrices.Translation(-2, 0, 0), matrices.Translation(2, 0, 0)))
+	w.Objects = []shapes.Shape{s}
+	c := camera.New(21, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	c.Samples = 16
+	still := c.Render(w)
+	c.ShutterClose = 1
+	blurred := c.Render(w)
+	start, middle := still.PixelAt(6, 5), still.PixelAt(10, 5)
+	assert.Greater(t, start.Red(), 0.5)
+	assert.InDelta(t, 0, middle.Red(), 0)
+	assert.Greater(t, blurred.PixelAt(10, 5).Red(), 0.1)
+	assert.Less(t, blurred.PixelAt(10, 5).Red(), start.Red())
+	assert.Equal(t, blurred.Pixels, c.Render(w).Pixels)
+}
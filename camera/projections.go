@@ -0,0 +1,89 @@
+package camera
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// Projection chooses how the camera maps the image onto the scene.
+type Projection int
+
+const (
+	// Perspective casts every ray from the camera through a flat canvas, so
+	// that distant objects look smaller, spanning FieldOfView across the
+	// longer side of the image.
+	Perspective Projection = iota
+	// Orthographic casts parallel rays straight ahead, so that objects keep
+	// their size at any distance, as in technical drawings. The image shows
+	// the area the perspective view would show at the focal distance.
+	Orthographic
+	// Fisheye spaces rays by equal angles from the view direction, so that
+	// FieldOfView can reach π (180°) and beyond where a flat canvas could
+	// not.
+	Fisheye
+	// Equirectangular captures a full panorama, 360° across and 180° from
+	// top to bottom, with the view direction in the middle. It ignores
+	// FieldOfView, and images twice as wide as they are high keep its
+	// pixels square.
+	Equirectangular
+)
+
+// orthographic returns the origin and direction of the ray in camera space
+// through the point (x, y) of the canvas one unit in front of the camera.
+func (c *Camera) orthographic(x, y float64) (tuples.Tuple, tuples.Tuple) {
+	return tuples.Point(x*c.FocalDistance, y*c.FocalDistance, 0), tuples.Vector(0, 0, -1)
+}
+
+// fisheye returns the direction in camera space of the pixel point (px, py),
+// which lies at an angle from the view direction proportional to its
+// distance from the center of the image.
+func (c *Camera) fisheye(px, py float64) tuples.Tuple {
+	const half = 0.5
+	angle := c.pixelAngle()
+	ax := (float64(c.HSize)*half - px) * angle
+	ay := (float64(c.VSize)*half - py) * angle
+	theta := math.Hypot(ax, ay)
+	if theta == 0 {
+		return tuples.Vector(0, 0, -1)
+	}
+	sin := math.Sin(theta) / theta
+	return tuples.Vector(ax*sin, ay*sin, -math.Cos(theta))
+}
+
+// equirectangular returns the direction in camera space of the pixel point
+// (px, py), with longitude running across the image and latitude down it.
+func (c *Camera) equirectangular(px, py float64) tuples.Tuple {
+	const half = 0.5
+	longitude := c.longitude(px)
+	latitude := (half - py/float64(c.VSize)) * math.Pi
+	return tuples.Vector(
+		math.Sin(longitude)*math.Cos(latitude),
+		math.Sin(latitude),
+		-math.Cos(longitude)*math.Cos(latitude))
+}
+
+// panoramaEye returns the offset of the eye for the column px of a
+// panorama. The eyes turn with the view, staying at right angles to it on a
+// circle around the camera, which gives omni-directional stereo.
+func (c *Camera) panoramaEye(px float64) tuples.Tuple {
+	longitude := c.longitude(px)
+	return tuples.Vector(math.Cos(longitude), 0, math.Sin(longitude)).Multiply(c.EyeOffset)
+}
+
+// longitude returns the angle of the column px of a panorama from the view
+// direction.
+func (c *Camera) longitude(px float64) float64 {
+	const half = 0.5
+	return (half - px/float64(c.HSize)) * 2 * math.Pi
+}
+
+// pixelAngle returns the angle a pixel covers, which is how much its ray
+// spreads per unit for projections that space rays by angle.
+func (c *Camera) pixelAngle() float64 {
+	switch c.Projection {
+	case Equirectangular:
+		return 2 * math.Pi / float64(c.HSize)
+	case Perspective, Orthographic, Fisheye:
+	}
+	return c.FieldOfView / float64(max(c.HSize, c.VSize))
+}
@@ -0,0 +1,102 @@
+package camera_test
+
+import (
+	"math"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrthographicRays(t *testing.T) {
+	// Scenario: An orthographic camera casts parallel rays
+	// Given c ← camera(201, 101, π/2) with an orthographic projection
+	// And c.focal_distance ← 2
+	// When r1 ← ray_for_pixel(c, 100, 50)
+	// And r2 ← ray_for_pixel(c, 0, 0)
+	// Then r1 starts at the camera and r2 where the perspective corner ray
+	// meets the focal plane, moved back onto the camera's plane
+	// And both point straight ahead and do not spread
+	c := camera.New(201, 101, math.Pi/2)
+	c.Projection = camera.Orthographic
+	c.FocalDistance = 2
+	r1 := c.RayForPixel(100, 50)
+	r2 := c.RayForPixel(0, 0)
+	assert.True(t, tuples.Point(0, 0, 0).Equals(r1.Origin))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(r1.Direction))
+	assert.True(t, tuples.Point(1.99005, 0.99502, 0).Equals(r2.Origin))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(r2.Direction))
+	assert.Zero(t, r1.Spread)
+}
+
+func TestOrthographicRaysWhenCameraIsTransformed(t *testing.T) {
+	// Scenario: An orthographic camera looks along its view direction
+	// Given c ← camera(201, 101, π/2) with an orthographic projection
+	// When c.transform ← rotation_y(π/4) * translation(0, -2, 5)
+	// And r ← ray_for_pixel(c, 100, 50)
+	// Then r.origin = point(0, 2, -5)
+	// And r.direction = vector(√2/2, 0, -√2/2)
+	c := camera.New(201, 101, math.Pi/2)
+	c.Projection = camera.Orthographic
+	c.SetTransform(matrices.RotationY(math.Pi / 4).Multiply(matrices.Translation(0, -2, 5)))
+	r := c.RayForPixel(100, 50)
+	val := math.Sqrt(2) / 2
+	assert.True(t, tuples.Point(0, 2, -5).Equals(r.Origin))
+	assert.True(t, tuples.Vector(val, 0, -val).Equals(r.Direction))
+}
+
+func TestFisheyeRays(t *testing.T) {
+	// Scenario: A fisheye camera spaces rays by equal angles
+	// Given c ← camera(201, 101, π) with a fisheye projection
+	// Then the ray through the center of the image looks straight ahead
+	// And the ray a quarter of the way across looks π/4 to the side
+	// And the ray at the left edge looks π/2 to the side
+	// And rays spread by the angle of a pixel
+	c := camera.New(201, 101, math.Pi)
+	c.Projection = camera.Fisheye
+	val := math.Sqrt(2) / 2
+	center := c.RayForPixel(100, 50)
+	assert.True(t, tuples.Point(0, 0, 0).Equals(center.Origin))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(center.Direction))
+	assert.True(t, tuples.Vector(val, 0, -val).Equals(c.RayForSample(50, 50, 0.25, 0.5).Direction))
+	assert.True(t, tuples.Vector(1, 0, 0).Equals(c.RayForSample(0, 50, 0, 0.5).Direction))
+	assert.True(t, tuples.Vector(0, val, -val).Equals(c.RayForSample(100, 0, 0.5, 0.25).Direction))
+	assert.InEpsilon(t, math.Pi/201, center.Spread, 0.00001)
+}
+
+func TestEquirectangularRays(t *testing.T) {
+	// Scenario: An equirectangular camera sees all around
+	// Given c ← camera(200, 100, π/2) with an equirectangular projection
+	// Then the center of the image looks straight ahead
+	// And a quarter of the way across looks to the side
+	// And the left edge looks straight behind
+	// And the top edge looks straight up
+	// And rays spread by the angle of a pixel
+	c := camera.New(200, 100, math.Pi/2)
+	c.Projection = camera.Equirectangular
+	center := c.RayForSample(100, 50, 0, 0)
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(center.Direction))
+	assert.True(t, tuples.Vector(1, 0, 0).Equals(c.RayForSample(50, 50, 0, 0).Direction))
+	assert.True(t, tuples.Vector(0, 0, 1).Equals(c.RayForSample(0, 50, 0, 0).Direction))
+	assert.True(t, tuples.Vector(0, 1, 0).Equals(c.RayForSample(100, 0, 0, 0).Direction))
+	assert.InEpsilon(t, 2*math.Pi/200, center.Spread, 0.00001)
+}
+
+func TestOnlyPerspectiveHasLens(t *testing.T) {
+	// Scenario: Other projections ignore the aperture
+	// Given cameras with an aperture of 1 and each projection but perspective
+	// When a ray leaves the edge of the lens
+	// Then it starts where a ray from the center of the lens would
+	for _, projection := range []camera.Projection{camera.Orthographic, camera.Fisheye, camera.Equirectangular} {
+		c := camera.New(20, 10, math.Pi/2)
+		c.Projection = projection
+		c.Aperture = 1
+		edge := c.RayForLensSample(3, 4, 0.5, 0.5, sampling.Point{X: 0, Y: 0.5})
+		center := c.RayForPixel(3, 4)
+		assert.True(t, center.Origin.Equals(edge.Origin), "projection %d", projection)
+		assert.True(t, center.Direction.Equals(edge.Direction), "projection %d", projection)
+	}
+}
@@ -0,0 +1,80 @@
+package camera
+
+import (
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/world"
+)
+
+// Layout arranges the two views of a stereo image on one canvas.
+type Layout int
+
+const (
+	// SideBySide puts the left eye's view on the left and the right eye's
+	// on the right.
+	SideBySide Layout = iota
+	// TopBottom puts the left eye's view above the right eye's.
+	TopBottom
+)
+
+// Stereo is a rig that renders a camera's view once for each eye, for
+// viewing in a stereoscope or VR headset.
+type Stereo struct {
+	Camera *Camera
+	// InterpupillaryDistance is how far apart the eyes are, in world units.
+	InterpupillaryDistance float64
+	// Convergence is the distance at which the eyes' views line up, so that
+	// objects there appear at the depth of the screen and nearer objects in
+	// front of it. Zero keeps the eyes parallel, converging at infinity.
+	Convergence float64
+	Layout      Layout
+}
+
+func NewStereo(c *Camera, interpupillaryDistance, convergence float64, layout Layout) *Stereo {
+	return &Stereo{
+		Camera:                 c,
+		InterpupillaryDistance: interpupillaryDistance,
+		Convergence:            convergence,
+		Layout:                 layout,
+	}
+}
+
+// Eyes returns copies of the camera moved to the left and right eyes, left
+// first.
+func (s *Stereo) Eyes() (*Camera, *Camera) {
+	const half = 0.5
+	return s.eye(s.InterpupillaryDistance * half), s.eye(-s.InterpupillaryDistance * half)
+}
+
+func (s *Stereo) eye(offset float64) *Camera {
+	c := *s.Camera
+	c.EyeOffset = offset
+	c.Convergence = s.Convergence
+	return &c
+}
+
+// Render renders the view of each eye and combines them.
+func (s *Stereo) Render(w *world.World) *canvas.Canvas {
+	left, right := s.Eyes()
+	return s.Combine(left.Render(w), right.Render(w))
+}
+
+// Combine lays the images of the left and right eyes out on one canvas,
+// twice as wide or twice as high as each of them.
+func (s *Stereo) Combine(left, right *canvas.Canvas) *canvas.Canvas {
+	dx, dy := left.Width, 0
+	width, height := 2*left.Width, left.Height
+	switch s.Layout {
+	case TopBottom:
+		dx, dy = 0, left.Height
+		width, height = left.Width, 2*left.Height
+	case SideBySide:
+	}
+	combined := canvas.NewCanvas(width, height)
+	for y := range left.Height {
+		for x := range left.Width {
+			combined.WritePixel(x, y, left.PixelAt(x, y))
+			combined.WritePixel(x+dx, y+dy, right.PixelAt(x, y))
+		}
+	}
+	return combined
+}
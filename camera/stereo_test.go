@@ -0,0 +1,145 @@
+package camera_test
+
+import (
+	"math"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStereoEyesAreApart(t *testing.T) {
+	// Scenario: A stereo rig moves the camera to each eye
+	// Given c ← camera(201, 101, π/2)
+	// And s ← stereo(c, 2, 0, side-by-side)
+	// When left, right ← eyes(s)
+	// Then the left eye's rays start 1 unit to the camera's left
+	// And the right eye's rays 1 unit to its right
+	// And both look straight ahead
+	// And the rig's camera is unchanged
+	c := camera.New(201, 101, math.Pi/2)
+	left, right := camera.NewStereo(c, 2, 0, camera.SideBySide).Eyes()
+	l, r := left.RayForPixel(100, 50), right.RayForPixel(100, 50)
+	assert.True(t, tuples.Point(1, 0, 0).Equals(l.Origin))
+	assert.True(t, tuples.Point(-1, 0, 0).Equals(r.Origin))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(l.Direction))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(r.Direction))
+	assert.True(t, tuples.Point(0, 0, 0).Equals(c.RayForPixel(100, 50).Origin))
+}
+
+func TestStereoEyesConverge(t *testing.T) {
+	// Scenario: The eyes' rays through a pixel cross at the convergence distance
+	// Given c ← camera(201, 101, π/2)
+	// And c.transform ← view_transform(point(0, 0, -5), point(0, 0, 0), vector(0, 1, 0))
+	// And s ← stereo(c, 0.5, 5, side-by-side)
+	// When left, right ← eyes(s)
+	// Then the rays of both eyes through any pixel meet c's ray 5 units in front of the camera
+	c := camera.New(201, 101, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	left, right := camera.NewStereo(c, 0.5, 5, camera.SideBySide).Eyes()
+	for _, pixel := range [][2]int{{100, 50}, {0, 0}, {150, 20}} {
+		center := c.RayForPixel(pixel[0], pixel[1])
+		t0 := -center.Origin.Z / center.Direction.Z
+		target := center.Position(t0)
+		for _, eye := range []*camera.Camera{left, right} {
+			r := eye.RayForPixel(pixel[0], pixel[1])
+			assert.InDelta(t, 0.25, tuples.Magnitude(r.Origin.Subtract(center.Origin)), 1e-9)
+			t1 := (target.Z - r.Origin.Z) / r.Direction.Z
+			assert.True(t, target.Equals(r.Position(t1)), "pixel %v", pixel)
+		}
+	}
+}
+
+func TestOmnidirectionalStereo(t *testing.T) {
+	// Scenario: Panorama eyes stay at right angles to the view
+	// Given c ← camera(200, 100, π/2) with an equirectangular projection
+	// And s ← stereo(c, 2, 0, top-bottom)
+	// When left ← the left eye of s
+	// Then its ray looking straight ahead starts to the camera's left
+	// And its ray looking to the left starts behind the camera
+	// And its ray looking behind starts to the camera's right
+	c := camera.New(200, 100, math.Pi/2)
+	c.Projection = camera.Equirectangular
+	left, _ := camera.NewStereo(c, 2, 0, camera.TopBottom).Eyes()
+	ahead := left.RayForSample(100, 50, 0, 0)
+	assert.True(t, tuples.Point(1, 0, 0).Equals(ahead.Origin))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(ahead.Direction))
+	side := left.RayForSample(50, 50, 0, 0)
+	assert.True(t, tuples.Point(0, 0, 1).Equals(side.Origin))
+	assert.True(t, tuples.Vector(1, 0, 0).Equals(side.Direction))
+	behind := left.RayForSample(0, 50, 0, 0)
+	assert.True(t, tuples.Point(-1, 0, 0).Equals(behind.Origin))
+}
+
+func TestFisheyeStereoConverges(t *testing.T) {
+	// Scenario: The eyes of other projections turn to meet at the convergence distance
+	// Given c ← camera(201, 101, π) with a fisheye projection
+	// And s ← stereo(c, 2, 5, side-by-side)
+	// When left ← the left eye of s
+	// And r ← ray_for_pixel(left, 100, 50)
+	// Then r starts 1 unit to the camera's left and passes through point(0, 0, -5)
+	c := camera.New(201, 101, math.Pi)
+	c.Projection = camera.Fisheye
+	left, _ := camera.NewStereo(c, 2, 5, camera.SideBySide).Eyes()
+	r := left.RayForPixel(100, 50)
+	assert.True(t, tuples.Point(1, 0, 0).Equals(r.Origin))
+	assert.True(t, tuples.Point(0, 0, -5).Equals(r.Position(math.Sqrt(26))))
+}
+
+func TestCombiningStereoImages(t *testing.T) {
+	// Scenario: The eyes' images are laid out side by side or top and bottom
+	// Given a 2x1 image for the left eye and another for the right eye
+	// When they are combined side by side and top and bottom
+	// Then the side by side image is 4x1 with the left image first
+	// And the top and bottom image is 2x2 with the left image on top
+	left, right := canvas.NewCanvas(2, 1), canvas.NewCanvas(2, 1)
+	red, blue := tuples.NewColor(1, 0, 0), tuples.NewColor(0, 0, 1)
+	left.WritePixel(1, 0, red)
+	right.WritePixel(1, 0, blue)
+
+	sideBySide := camera.NewStereo(nil, 0, 0, camera.SideBySide).Combine(left, right)
+	require.Equal(t, 4, sideBySide.Width)
+	require.Equal(t, 1, sideBySide.Height)
+	assert.True(t, red.Equals(sideBySide.PixelAt(1, 0).Tuple))
+	assert.True(t, blue.Equals(sideBySide.PixelAt(3, 0).Tuple))
+
+	topBottom := camera.NewStereo(nil, 0, 0, camera.TopBottom).Combine(left, right)
+	require.Equal(t, 2, topBottom.Width)
+	require.Equal(t, 2, topBottom.Height)
+	assert.True(t, red.Equals(topBottom.PixelAt(1, 0).Tuple))
+	assert.True(t, blue.Equals(topBottom.PixelAt(1, 1).Tuple))
+}
+
+func TestRenderingStereo(t *testing.T) {
+	// Scenario: Rendering a stereo pair of the default world
+	// Given w ← default_world()
+	// And c ← camera(11, 11, π/2) looking at the origin from point(0, 0, -5)
+	// And s ← stereo(c, 1, 5, side-by-side)
+	// When image ← render(s, w)
+	// Then image is 22x11
+	// And each half matches its eye rendered alone
+	// And the halves differ
+	w := world.Default()
+	c := camera.New(11, 11, math.Pi/2)
+	c.SetTransform(matrices.ViewTransform(tuples.Point(0, 0, -5), tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0)))
+	s := camera.NewStereo(c, 1, 5, camera.SideBySide)
+	image := s.Render(w)
+	require.Equal(t, 22, image.Width)
+	require.Equal(t, 11, image.Height)
+	left, right := s.Eyes()
+	leftImage, rightImage := left.Render(w), right.Render(w)
+	differ := false
+	for y := range 11 {
+		for x := range 11 {
+			assert.True(t, leftImage.PixelAt(x, y).Equals(image.PixelAt(x, y).Tuple))
+			assert.True(t, rightImage.PixelAt(x, y).Equals(image.PixelAt(x+11, y).Tuple))
+			differ = differ || !leftImage.PixelAt(x, y).Equals(rightImage.PixelAt(x, y).Tuple)
+		}
+	}
+	assert.True(t, differ)
+}
@@ -1,6 +1,11 @@
 package canvas
 
 import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
 	"math"
 	"raytracer-vibe/tuples"
 	"strconv"
@@ -16,6 +21,13 @@ type Canvas struct {
 	Pixels        []tuples.Color
 }
 
+// Layer is a named canvas, one of several images of the same view, such as
+// the depth or normals rendered alongside the image itself.
+type Layer struct {
+	Name   string
+	Canvas *Canvas
+}
+
 func NewCanvas(width, height int) *Canvas {
 	pixels := make([]tuples.Color, width*height)
 	for i := range pixels {
@@ -32,6 +44,21 @@ func (c *Canvas) PixelAt(x, y int) tuples.Color {
 	return c.Pixels[y*c.Width+x]
 }
 
+// Contrast returns the largest difference in any channel between the pixel
+// at (x, y) and its horizontal and vertical neighbors.
+func (c *Canvas) Contrast(x, y int) float64 {
+	pixel := c.PixelAt(x, y)
+	contrast := 0.0
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		nx, ny := x+d[0], y+d[1]
+		if nx < 0 || ny < 0 || nx >= c.Width || ny >= c.Height {
+			continue
+		}
+		contrast = max(contrast, pixel.Difference(c.PixelAt(nx, ny)))
+	}
+	return contrast
+}
+
 func (c *Canvas) ToPPM() string {
 	ppm := "P3\n" + strconv.Itoa(c.Width) + " " + strconv.Itoa(c.Height) + "\n255\n"
 	line := ""
@@ -76,3 +103,28 @@ func scaleAndClamp(c float64) int {
 	}
 	return int(scaled)
 }
+
+// ToImage converts the canvas to an 8-bit image, clamping each channel.
+func (c *Canvas) ToImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, c.Width, c.Height))
+	for y := range c.Height {
+		for x := range c.Width {
+			pixel := c.PixelAt(x, y)
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(scaleAndClamp(pixel.Red())),   // #nosec G115 -- clamped to [0, 255]
+				G: uint8(scaleAndClamp(pixel.Green())), // #nosec G115 -- clamped to [0, 255]
+				B: uint8(scaleAndClamp(pixel.Blue())),  // #nosec G115 -- clamped to [0, 255]
+				A: ColorScale,
+			})
+		}
+	}
+	return img
+}
+
+// WritePNG encodes the canvas as a PNG image.
+func (c *Canvas) WritePNG(w io.Writer) error {
+	if err := png.Encode(w, c.ToImage()); err != nil {
+		return fmt.Errorf("encoding png: %w", err)
+	}
+	return nil
+}
@@ -1,12 +1,16 @@
 package canvas_test
 
 import (
+	"bytes"
+	"image/color"
+	"image/png"
 	"raytracer-vibe/tuples"
 	"testing"
 
 	"raytracer-vibe/canvas"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCanvas(t *testing.T) {
@@ -60,3 +64,35 @@ func TestToPPMEndsWithNewline(t *testing.T) {
 	ppm := c.ToPPM()
 	assert.Equal(t, "\n", ppm[len(ppm)-1:])
 }
+
+func TestToImage(t *testing.T) {
+	c := canvas.NewCanvas(2, 1)
+	c.WritePixel(0, 0, tuples.NewColor(1.5, 0.5, -0.5))
+	img := c.ToImage()
+	assert.Equal(t, 2, img.Bounds().Dx())
+	assert.Equal(t, 1, img.Bounds().Dy())
+	assert.Equal(t, color.NRGBA{R: 255, G: 128, B: 0, A: 255}, img.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{R: 0, G: 0, B: 0, A: 255}, img.NRGBAAt(1, 0))
+}
+
+func TestWritePNG(t *testing.T) {
+	c := canvas.NewCanvas(3, 2)
+	c.WritePixel(2, 1, tuples.NewColor(0, 1, 0))
+	var buf bytes.Buffer
+	require.NoError(t, c.WritePNG(&buf))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, img.Bounds().Dx())
+	r, g, b, _ := img.At(2, 1).RGBA()
+	assert.Equal(t, []uint32{0, 0xffff, 0}, []uint32{r, g, b})
+}
+
+func TestContrastComparesHorizontalAndVerticalNeighbors(t *testing.T) {
+	c := canvas.NewCanvas(3, 3)
+	c.WritePixel(1, 1, tuples.NewColor(0.5, 0, 0))
+	c.WritePixel(2, 2, tuples.NewColor(0, 0, 1))
+	assert.InDelta(t, 0.5, c.Contrast(1, 1), 0.00001)
+	assert.InDelta(t, 0.5, c.Contrast(1, 0), 0.00001)
+	assert.InDelta(t, 0.0, c.Contrast(0, 0), 0.00001)
+	assert.InDelta(t, 1.0, c.Contrast(2, 2), 0.00001)
+}
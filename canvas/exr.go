@@ -0,0 +1,584 @@
+package canvas
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"raytracer-vibe/tuples"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidEXR is returned when OpenEXR data cannot be parsed, or uses a
+// feature ReadEXR does not support.
+var ErrInvalidEXR = errors.New("invalid OpenEXR")
+
+// The OpenEXR file layout.
+const (
+	exrMagic   = 20000630
+	exrVersion = 2
+	// The version flags for tiled, deep and multi-part files, which ReadEXR
+	// does not read, and for names longer than exrShortName bytes.
+	exrTiled     = 0x200
+	exrLongNames = 0x400
+	exrDeep      = 0x800
+	exrMultiPart = 0x1000
+	exrShortName = 31
+	exrLongName  = 255
+	// exrOffsetSize is the size of each entry of the table of offsets to
+	// the blocks of scanlines that follows the header.
+	exrOffsetSize = 8
+
+	// The pixel types of channels.
+	exrUint  = 0
+	exrHalf  = 1
+	exrFloat = 2
+
+	// The compression methods, and how many scanlines they compress at once.
+	exrNoCompression = 0
+	exrZIPS          = 2
+	exrZIP           = 3
+	exrZIPLines      = 16
+
+	// maxEXRPixels bounds the size of the images ReadEXR reads, so that a
+	// corrupt header cannot ask for all the memory there is.
+	maxEXRPixels = 1 << 28
+)
+
+// EXRChannels chooses how WriteEXR stores the channels of each pixel.
+type EXRChannels int
+
+const (
+	// HalfChannels store 16-bit floats, precise to about 3 significant
+	// digits up to 65504: plenty for colors, at half the size.
+	HalfChannels EXRChannels = iota
+	// FloatChannels store 32-bit floats, for depths and other data that need
+	// their precision.
+	FloatChannels
+)
+
+// EXRCompression chooses how WriteEXR compresses the pixels.
+type EXRCompression int
+
+const (
+	// NoCompression stores the pixels as they are.
+	NoCompression EXRCompression = iota
+	// ZIPCompression deflates the pixels losslessly, 16 scanlines at a time.
+	ZIPCompression
+)
+
+// EXROptions choose how WriteEXR stores an image. The zero value writes
+// uncompressed half floats.
+type EXROptions struct {
+	Channels    EXRChannels
+	Compression EXRCompression
+}
+
+// exrChannel is a channel of an OpenEXR file: one of the red, green or blue
+// components of a layer.
+type exrChannel struct {
+	name      string
+	layer     int
+	component int
+}
+
+// WriteEXR writes the layers, which must all be the same size, as a
+// scanline OpenEXR image. Each layer's red, green and blue are the channels
+// R, G and B prefixed by its name and a dot, as in depth.R, except for a
+// layer with an empty name, whose channels are the file's main image.
+func WriteEXR(w io.Writer, layers []Layer, o EXROptions) error {
+	channels, err := exrChannels(layers)
+	if err != nil {
+		return err
+	}
+	width, height := layers[0].Canvas.Width, layers[0].Canvas.Height
+	pixelType, compression, lines := exrHalf, exrNoCompression, 1
+	if o.Channels == FloatChannels {
+		pixelType = exrFloat
+	}
+	if o.Compression == ZIPCompression {
+		compression, lines = exrZIP, exrZIPLines
+	}
+
+	header := exrHeader(channels, pixelType, compression, width, height)
+	blocks := (height + lines - 1) / lines
+	offset := uint64(len(header)) + uint64(blocks)*exrOffsetSize
+	offsets := make([]byte, 0, blocks*exrOffsetSize)
+	var chunks []byte
+	for y := 0; y < height; y += lines {
+		var block []byte
+		for line := y; line < min(y+lines, height); line++ {
+			for _, ch := range channels {
+				image := layers[ch.layer].Canvas
+				for x := range width {
+					value := float32(rgb(image.PixelAt(x, line))[ch.component])
+					if pixelType == exrHalf {
+						block = binary.LittleEndian.AppendUint16(block, toHalf(value))
+					} else {
+						block = binary.LittleEndian.AppendUint32(block, math.Float32bits(value))
+					}
+				}
+			}
+		}
+		if compression == exrZIP {
+			block = deflate(block)
+		}
+		offsets = binary.LittleEndian.AppendUint64(offsets, offset+uint64(len(chunks)))
+		chunks = binary.LittleEndian.AppendUint32(chunks, uint32(y))          // #nosec G115 -- y is an image row
+		chunks = binary.LittleEndian.AppendUint32(chunks, uint32(len(block))) // #nosec G115 -- blocks are small
+		chunks = append(chunks, block...)
+	}
+
+	for _, part := range [][]byte{header, offsets, chunks} {
+		if _, err = w.Write(part); err != nil {
+			return fmt.Errorf("writing OpenEXR: %w", err)
+		}
+	}
+	return nil
+}
+
+// exrChannels returns the channels of the layers, sorted by name as
+// OpenEXR requires.
+func exrChannels(layers []Layer) ([]exrChannel, error) {
+	if len(layers) == 0 {
+		return nil, errors.New("an OpenEXR image needs at least one layer")
+	}
+	var channels []exrChannel
+	seen := map[string]bool{}
+	for i, layer := range layers {
+		if layer.Canvas.Width != layers[0].Canvas.Width || layer.Canvas.Height != layers[0].Canvas.Height {
+			return nil, fmt.Errorf("layer %q is %dx%d, but layer %q is %dx%d",
+				layer.Name, layer.Canvas.Width, layer.Canvas.Height,
+				layers[0].Name, layers[0].Canvas.Width, layers[0].Canvas.Height)
+		}
+		if seen[layer.Name] {
+			return nil, fmt.Errorf("duplicate layer %q", layer.Name)
+		}
+		seen[layer.Name] = true
+		prefix := ""
+		if layer.Name != "" {
+			prefix = layer.Name + "."
+		}
+		if len(prefix)+1 > exrLongName {
+			return nil, fmt.Errorf("layer name %q is longer than %d bytes", layer.Name, exrLongName-2)
+		}
+		for component, suffix := range []string{"R", "G", "B"} {
+			channels = append(channels, exrChannel{name: prefix + suffix, layer: i, component: component})
+		}
+	}
+	slices.SortFunc(channels, func(a, b exrChannel) int { return strings.Compare(a.name, b.name) })
+	return channels, nil
+}
+
+// exrHeader returns the magic number, version and header of an image of the
+// channels whose data window is width by height pixels.
+func exrHeader(channels []exrChannel, pixelType, compression, width, height int) []byte {
+	version := uint32(exrVersion)
+	chlist := []byte{}
+	for _, ch := range channels {
+		if len(ch.name) > exrShortName {
+			version |= exrLongNames
+		}
+		chlist = append(chlist, ch.name...)
+		chlist = append(chlist, 0)
+		chlist = binary.LittleEndian.AppendUint32(chlist, uint32(pixelType)) // #nosec G115 -- a small constant
+		// pLinear and three reserved bytes, then the x and y sampling.
+		chlist = append(chlist, 0, 0, 0, 0)
+		chlist = binary.LittleEndian.AppendUint32(chlist, 1)
+		chlist = binary.LittleEndian.AppendUint32(chlist, 1)
+	}
+	chlist = append(chlist, 0)
+
+	var window []byte
+	for _, v := range []int{0, 0, width - 1, height - 1} {
+		window = binary.LittleEndian.AppendUint32(window, uint32(v)) // #nosec G115 -- image bounds
+	}
+	one := binary.LittleEndian.AppendUint32(nil, math.Float32bits(1))
+
+	header := binary.LittleEndian.AppendUint32(nil, exrMagic)
+	header = binary.LittleEndian.AppendUint32(header, version)
+	attributes := []struct {
+		name, kind string
+		value      []byte
+	}{
+		{"channels", "chlist", chlist},
+		{"compression", "compression", []byte{byte(compression)}},
+		{"dataWindow", "box2i", window},
+		{"displayWindow", "box2i", window},
+		{"lineOrder", "lineOrder", []byte{0}},
+		{"pixelAspectRatio", "float", one},
+		{"screenWindowCenter", "v2f", make([]byte, 8)}, // nolint: mnd // two zero floats
+		{"screenWindowWidth", "float", one},
+	}
+	for _, a := range attributes {
+		header = append(header, a.name...)
+		header = append(header, 0)
+		header = append(header, a.kind...)
+		header = append(header, 0)
+		header = binary.LittleEndian.AppendUint32(header, uint32(len(a.value))) // #nosec G115 -- headers are small
+		header = append(header, a.value...)
+	}
+	return append(header, 0)
+}
+
+// deflate compresses a block of pixels as OpenEXR's ZIP compression does,
+// returning the block as it is when it does not compress.
+func deflate(block []byte) []byte {
+	// Interleaving the low and high bytes of the samples and storing the
+	// differences between neighbouring bytes turn smooth images into runs
+	// that compress well.
+	predicted := make([]byte, len(block))
+	half := (len(block) + 1) / 2
+	for i, b := range block {
+		if i%2 == 0 {
+			predicted[i/2] = b
+		} else {
+			predicted[half+i/2] = b
+		}
+	}
+	previous := byte(0)
+	for i, b := range predicted {
+		if i > 0 {
+			predicted[i] = b - previous + 128 // nolint: mnd // differences are stored around 128
+		}
+		previous = b
+	}
+
+	var compressed bytes.Buffer
+	z := zlib.NewWriter(&compressed)
+	// Writing to a bytes.Buffer never fails.
+	_, _ = z.Write(predicted)
+	_ = z.Close()
+	if compressed.Len() >= len(block) {
+		return block
+	}
+	return compressed.Bytes()
+}
+
+// inflate undoes deflate, given the size of the uncompressed block.
+func inflate(data []byte, size int) ([]byte, error) {
+	z, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEXR, err)
+	}
+	predicted, err := io.ReadAll(io.LimitReader(z, int64(size)+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEXR, err)
+	}
+	if len(predicted) != size {
+		return nil, fmt.Errorf("%w: a block holds %d bytes, expected %d", ErrInvalidEXR, len(predicted), size)
+	}
+	for i := 1; i < len(predicted); i++ {
+		predicted[i] += predicted[i-1] - 128 // nolint: mnd // differences are stored around 128
+	}
+	block := make([]byte, size)
+	half := (size + 1) / 2
+	for i := range block {
+		if i%2 == 0 {
+			block[i] = predicted[i/2]
+		} else {
+			block[i] = predicted[half+i/2]
+		}
+	}
+	return block, nil
+}
+
+// ReadEXR reads a scanline OpenEXR image that is uncompressed or ZIP
+// compressed, with half, float or uint channels, into a layer for each
+// channel name prefix, sorted by name: the channels depth.R, depth.G and
+// depth.B make the red, green and blue of layer depth, and channels without
+// a prefix make the layer with an empty name. A Y channel fills all three
+// components, and channels other than R, G, B and Y are skipped.
+func ReadEXR(r io.Reader) ([]Layer, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading OpenEXR: %w", err)
+	}
+	e := &exrReader{data: data}
+	if e.uint32() != exrMagic {
+		return nil, fmt.Errorf("%w: not an OpenEXR file", ErrInvalidEXR)
+	}
+	version := e.uint32()
+	if e.err != nil {
+		return nil, e.err
+	}
+	if version&0xff != exrVersion || version&(exrTiled|exrDeep|exrMultiPart) != 0 {
+		return nil, fmt.Errorf("%w: only single-part scanline images of version 2 are supported", ErrInvalidEXR)
+	}
+
+	h, err := e.header()
+	if err != nil {
+		return nil, err
+	}
+	lines := 1
+	if h.compression == exrZIP {
+		lines = exrZIPLines
+	}
+	lineSize := 0
+	for _, ch := range h.channels {
+		lineSize += h.width * ch.size
+	}
+	layers, components := h.layers()
+
+	blocks := (h.height + lines - 1) / lines
+	offsets := make([]uint64, blocks)
+	for i := range offsets {
+		offsets[i] = e.uint64()
+	}
+	for _, offset := range offsets {
+		if offset >= uint64(len(data)) {
+			return nil, fmt.Errorf("%w: a block lies past the end of the file", ErrInvalidEXR)
+		}
+		e.pos = int(offset)               // #nosec G115 -- checked against the length of data
+		y := int(int32(e.uint32())) - h.y // #nosec G115 -- a signed row number
+		block := e.bytes(int(e.uint32()))
+		if e.err != nil {
+			return nil, e.err
+		}
+		if y < 0 || y >= h.height || y%lines != 0 {
+			return nil, fmt.Errorf("%w: a block starts at row %d, outside the image", ErrInvalidEXR, y+h.y)
+		}
+		count := min(lines, h.height-y)
+		if size := count * lineSize; len(block) != size {
+			if h.compression == exrNoCompression {
+				return nil, fmt.Errorf("%w: a block holds %d bytes, expected %d", ErrInvalidEXR, len(block), size)
+			}
+			if block, err = inflate(block, size); err != nil {
+				return nil, err
+			}
+		}
+		h.readBlock(block, y, count, layers, components)
+	}
+	return layers, nil
+}
+
+// exrHeaderInfo is what ReadEXR needs to know of a header.
+type exrHeaderInfo struct {
+	channels            []exrChannelInfo
+	compression         int
+	x, y, width, height int
+}
+
+// exrChannelInfo describes a channel of a file being read.
+type exrChannelInfo struct {
+	name      string
+	pixelType int
+	size      int
+}
+
+// header reads the attributes of the header, checking that ReadEXR can read
+// the image they describe.
+func (e *exrReader) header() (exrHeaderInfo, error) {
+	var h exrHeaderInfo
+	found := map[string]bool{}
+	for {
+		name := e.cstring()
+		if name == "" || e.err != nil {
+			break
+		}
+		kind := e.cstring()
+		size := int(e.uint32())
+		value := &exrReader{data: e.bytes(size)}
+		found[name] = true
+		switch name {
+		case "channels":
+			h.channels = value.channels()
+		case "compression":
+			h.compression = int(value.uint8())
+		case "dataWindow":
+			xMin, yMin := int(int32(value.uint32())), int(int32(value.uint32())) // #nosec G115 -- signed bounds
+			xMax, yMax := int(int32(value.uint32())), int(int32(value.uint32())) // #nosec G115 -- signed bounds
+			h.x, h.y, h.width, h.height = xMin, yMin, xMax-xMin+1, yMax-yMin+1
+		}
+		if value.err != nil {
+			return h, fmt.Errorf("%w: bad %s attribute %q", ErrInvalidEXR, kind, name)
+		}
+	}
+	if e.err != nil {
+		return h, e.err
+	}
+	switch {
+	case !found["channels"] || !found["compression"] || !found["dataWindow"]:
+		return h, fmt.Errorf("%w: the header lacks channels, compression or dataWindow", ErrInvalidEXR)
+	case len(h.channels) == 0:
+		return h, fmt.Errorf("%w: the image has no channels", ErrInvalidEXR)
+	case h.compression != exrNoCompression && h.compression != exrZIPS && h.compression != exrZIP:
+		return h, fmt.Errorf("%w: unsupported compression %d, expected none or ZIP", ErrInvalidEXR, h.compression)
+	case h.width <= 0 || h.height <= 0 || h.width > maxEXRPixels/h.height:
+		return h, fmt.Errorf("%w: bad data window of %dx%d pixels", ErrInvalidEXR, h.width, h.height)
+	}
+	for _, ch := range h.channels {
+		if ch.size == 0 {
+			return h, fmt.Errorf("%w: channel %q has unsupported pixel type %d", ErrInvalidEXR, ch.name, ch.pixelType)
+		}
+	}
+	return h, nil
+}
+
+// channels reads a channel list, leaving the size of channels with an
+// unknown pixel type 0.
+func (e *exrReader) channels() []exrChannelInfo {
+	var channels []exrChannelInfo
+	for {
+		name := e.cstring()
+		if name == "" || e.err != nil {
+			return channels
+		}
+		ch := exrChannelInfo{name: name, pixelType: int(e.uint32())}
+		e.bytes(4) // nolint: mnd // pLinear and three reserved bytes
+		if xSampling, ySampling := e.uint32(), e.uint32(); xSampling != 1 || ySampling != 1 {
+			e.err = fmt.Errorf("%w: channel %q is subsampled", ErrInvalidEXR, name)
+		}
+		switch ch.pixelType {
+		case exrUint, exrFloat:
+			ch.size = 4 // nolint: mnd // 32-bit samples
+		case exrHalf:
+			ch.size = 2 // nolint: mnd // 16-bit samples
+		}
+		channels = append(channels, ch)
+	}
+}
+
+// layers returns an empty layer for each channel name prefix, and the
+// layer and components each channel fills, or nil for channels that are
+// skipped.
+func (h exrHeaderInfo) layers() ([]Layer, [][]int) {
+	var names []string
+	for _, ch := range h.channels {
+		if prefix, _ := splitChannel(ch.name); !slices.Contains(names, prefix) {
+			names = append(names, prefix)
+		}
+	}
+	slices.Sort(names)
+	layers := make([]Layer, len(names))
+	for i, name := range names {
+		layers[i] = Layer{Name: name, Canvas: NewCanvas(h.width, h.height)}
+	}
+	components := make([][]int, len(h.channels))
+	for i, ch := range h.channels {
+		prefix, suffix := splitChannel(ch.name)
+		layer := slices.Index(names, prefix)
+		switch suffix {
+		case "R":
+			components[i] = []int{layer, 0}
+		case "G":
+			components[i] = []int{layer, 1}
+		case "B":
+			components[i] = []int{layer, 2} // nolint: mnd // blue is the third component
+		case "Y":
+			components[i] = []int{layer, 0, 1, 2} // nolint: mnd // luminance fills every component
+		}
+	}
+	return layers, components
+}
+
+// splitChannel splits a channel name into its layer's name and the
+// channel's own name.
+func splitChannel(name string) (string, string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
+
+// readBlock copies the samples of count uncompressed scanlines, starting at
+// row y, into the components of the layers that each channel fills.
+func (h exrHeaderInfo) readBlock(block []byte, y, count int, layers []Layer, components [][]int) {
+	pos := 0
+	for line := y; line < y+count; line++ {
+		for i, ch := range h.channels {
+			for x := range h.width {
+				sample := block[pos : pos+ch.size]
+				pos += ch.size
+				if components[i] == nil {
+					continue
+				}
+				var value float64
+				switch ch.pixelType {
+				case exrHalf:
+					value = float64(fromHalf(binary.LittleEndian.Uint16(sample)))
+				case exrFloat:
+					value = float64(math.Float32frombits(binary.LittleEndian.Uint32(sample)))
+				case exrUint:
+					value = float64(binary.LittleEndian.Uint32(sample))
+				}
+				image := layers[components[i][0]].Canvas
+				c := rgb(image.PixelAt(x, line))
+				for _, component := range components[i][1:] {
+					c[component] = value
+				}
+				image.WritePixel(x, line, tuples.NewColor(c[0], c[1], c[2]))
+			}
+		}
+	}
+}
+
+// rgb returns the red, green and blue of c, in that order.
+func rgb(c tuples.Color) [3]float64 {
+	return [3]float64{c.Red(), c.Green(), c.Blue()}
+}
+
+// exrReader reads the little-endian values of an OpenEXR file, and
+// remembers the first error.
+type exrReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (e *exrReader) bytes(n int) []byte {
+	if e.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(e.data)-e.pos {
+		e.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidEXR)
+		return nil
+	}
+	b := e.data[e.pos : e.pos+n]
+	e.pos += n
+	return b
+}
+
+func (e *exrReader) uint8() uint8 {
+	b := e.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (e *exrReader) uint32() uint32 {
+	b := e.bytes(4) // nolint: mnd // 32 bits
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (e *exrReader) uint64() uint64 {
+	b := e.bytes(exrOffsetSize)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// cstring reads a string ended by a zero byte.
+func (e *exrReader) cstring() string {
+	if e.err != nil {
+		return ""
+	}
+	end := bytes.IndexByte(e.data[e.pos:], 0)
+	if end < 0 {
+		e.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidEXR)
+		return ""
+	}
+	s := string(e.data[e.pos : e.pos+end])
+	e.pos += end + 1
+	return s
+}
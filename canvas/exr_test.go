@@ -0,0 +1,181 @@
+package canvas_test
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exrLayers returns a 5x20 main image with values beyond the range of PNG
+// and a depth layer.
+func exrLayers() []canvas.Layer {
+	image := canvas.NewCanvas(5, 20)
+	depth := canvas.NewCanvas(5, 20)
+	for y := range 20 {
+		for x := range 5 {
+			image.WritePixel(x, y, tuples.NewColor(float64(x)/4, float64(y)*10, -0.5))
+			d := 100 + float64(x+y)/3
+			depth.WritePixel(x, y, tuples.NewColor(d, d, d))
+		}
+	}
+	return []canvas.Layer{{Name: "depth", Canvas: depth}, {Name: "", Canvas: image}}
+}
+
+func TestEXRRoundTrip(t *testing.T) {
+	// Scenario Outline: Writing and reading back an OpenEXR image
+	// Given a main image and a depth layer
+	// When they are written as <channels> with <compression> and read back
+	// Then the layers come back sorted by name, the main image first
+	// And float channels keep every value, and half channels keep them to
+	// within their precision
+	tests := []struct {
+		name      string
+		options   canvas.EXROptions
+		tolerance float64
+	}{
+		{"uncompressed half", canvas.EXROptions{}, 1.0 / 1024},
+		{"ZIP half", canvas.EXROptions{Compression: canvas.ZIPCompression}, 1.0 / 1024},
+		{"uncompressed float", canvas.EXROptions{Channels: canvas.FloatChannels}, 1e-6},
+		{"ZIP float", canvas.EXROptions{Channels: canvas.FloatChannels, Compression: canvas.ZIPCompression}, 1e-6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := exrLayers()
+			var b bytes.Buffer
+			require.NoError(t, canvas.WriteEXR(&b, written, tt.options))
+			read, err := canvas.ReadEXR(&b)
+			require.NoError(t, err)
+			require.Len(t, read, 2)
+			assert.Equal(t, "", read[0].Name)
+			assert.Equal(t, "depth", read[1].Name)
+			for i, layer := range []canvas.Layer{written[1], written[0]} {
+				for p, c := range layer.Canvas.Pixels {
+					got := read[i].Canvas.Pixels[p]
+					assert.InEpsilon(t, c.Red()+1, got.Red()+1, tt.tolerance, "layer %q pixel %d", layer.Name, p)
+					assert.InEpsilon(t, c.Green()+1, got.Green()+1, tt.tolerance, "layer %q pixel %d", layer.Name, p)
+					assert.InEpsilon(t, c.Blue()+1, got.Blue()+1, tt.tolerance, "layer %q pixel %d", layer.Name, p)
+				}
+			}
+		})
+	}
+}
+
+func TestEXRHalfRounding(t *testing.T) {
+	// Scenario Outline: Half channels round to the nearest half float
+	// Given a pixel of <value>
+	// When it is written with half channels and read back
+	// Then it is <expected>
+	tests := []struct {
+		value, expected float64
+	}{
+		{1.0 / 3, 0.333251953125},
+		{-1.5, -1.5},
+		{65504, 65504},
+		{70000, math.Inf(1)},
+		{math.Ldexp(1, -24), math.Ldexp(1, -24)},
+		{math.Ldexp(3, -26), math.Ldexp(1, -24)},
+		{1e-9, 0},
+	}
+	for _, tt := range tests {
+		image := canvas.NewCanvas(1, 1)
+		image.WritePixel(0, 0, tuples.NewColor(tt.value, tt.value, tt.value))
+		var b bytes.Buffer
+		require.NoError(t, canvas.WriteEXR(&b, []canvas.Layer{{Canvas: image}}, canvas.EXROptions{}))
+		read, err := canvas.ReadEXR(&b)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, read[0].Canvas.PixelAt(0, 0).Red(), "value %g", tt.value)
+	}
+}
+
+func TestEXRHeader(t *testing.T) {
+	// Scenario: An OpenEXR file starts with its magic number and version
+	// Given an image written as OpenEXR
+	// Then it starts with the bytes 76 2f 31 01 and version 2
+	// And it lists its channels in alphabetical order
+	var b bytes.Buffer
+	require.NoError(t, canvas.WriteEXR(&b, exrLayers(), canvas.EXROptions{}))
+	assert.Equal(t, []byte{0x76, 0x2f, 0x31, 0x01, 2, 0, 0, 0}, b.Bytes()[:8])
+	var order []int
+	for _, name := range []string{"B\x00", "G\x00", "R\x00", "depth.B\x00", "depth.G\x00", "depth.R\x00"} {
+		order = append(order, bytes.Index(b.Bytes(), []byte(name)))
+	}
+	assert.IsIncreasing(t, order)
+}
+
+func TestEXRZIPCompresses(t *testing.T) {
+	// Scenario: ZIP compression shrinks a smooth image
+	// Given a smooth image
+	// When it is written uncompressed and ZIP compressed
+	// Then the ZIP compressed file is less than half the size
+	var plain, zipped bytes.Buffer
+	require.NoError(t, canvas.WriteEXR(&plain, exrLayers(), canvas.EXROptions{}))
+	require.NoError(t, canvas.WriteEXR(&zipped, exrLayers(), canvas.EXROptions{Compression: canvas.ZIPCompression}))
+	assert.Less(t, zipped.Len()*2, plain.Len())
+}
+
+func TestWritingInvalidEXRLayers(t *testing.T) {
+	// Scenario Outline: Layers that cannot make an OpenEXR image
+	tests := []struct {
+		name     string
+		layers   []canvas.Layer
+		expected string
+	}{
+		{"no layers", nil, "an OpenEXR image needs at least one layer"},
+		{
+			"different sizes",
+			[]canvas.Layer{{Name: "a", Canvas: canvas.NewCanvas(2, 2)}, {Name: "b", Canvas: canvas.NewCanvas(2, 3)}},
+			`layer "b" is 2x3, but layer "a" is 2x2`,
+		},
+		{
+			"duplicate names",
+			[]canvas.Layer{{Name: "a", Canvas: canvas.NewCanvas(2, 2)}, {Name: "a", Canvas: canvas.NewCanvas(2, 2)}},
+			`duplicate layer "a"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, canvas.WriteEXR(&bytes.Buffer{}, tt.layers, canvas.EXROptions{}), tt.expected)
+		})
+	}
+}
+
+func TestReadingInvalidEXR(t *testing.T) {
+	// Scenario Outline: Reading data that is not a whole OpenEXR image fails
+	var b bytes.Buffer
+	require.NoError(t, canvas.WriteEXR(&b, exrLayers(), canvas.EXROptions{Compression: canvas.ZIPCompression}))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not OpenEXR", []byte("P3\n1 1\n255\n0 0 0\n")},
+		{"truncated header", b.Bytes()[:40]},
+		{"truncated pixels", b.Bytes()[:b.Len()-10]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := canvas.ReadEXR(bytes.NewReader(tt.data))
+			assert.ErrorIs(t, err, canvas.ErrInvalidEXR)
+		})
+	}
+}
+
+func TestLoadingEXR(t *testing.T) {
+	// Scenario: Loading an OpenEXR file gives its main image
+	// Given an OpenEXR file with a main image and a depth layer
+	// When it is loaded
+	// Then the canvas is the main image
+	path := filepath.Join(t.TempDir(), "image.exr")
+	var b bytes.Buffer
+	require.NoError(t, canvas.WriteEXR(&b, exrLayers(), canvas.EXROptions{Channels: canvas.FloatChannels}))
+	require.NoError(t, os.WriteFile(path, b.Bytes(), 0o600))
+	c, err := canvas.Load(path)
+	require.NoError(t, err)
+	assert.True(t, tuples.NewColor(0.5, 30, -0.5).Equals(c.PixelAt(2, 3).Tuple))
+}
@@ -0,0 +1,86 @@
+package canvas
+
+import "math"
+
+// The layout of IEEE 754 half (16-bit) and single (32-bit) precision floats.
+const (
+	halfSign         = 0x8000
+	halfMantissaBits = 10
+	halfExponentMax  = 0x1f
+	halfBias         = 15
+	halfInfinity     = halfExponentMax << halfMantissaBits
+	halfQuietNaN     = halfInfinity | 1<<(halfMantissaBits-1)
+
+	floatMantissaBits = 23
+	floatMantissa     = 1<<floatMantissaBits - 1
+	floatExponentMax  = 0xff
+	floatBias         = 127
+
+	// mantissaShift is the number of low mantissa bits a float loses as a
+	// half.
+	mantissaShift = floatMantissaBits - halfMantissaBits
+	// halfSubnormalExponent is the power of two of the smallest half
+	// subnormal's step.
+	halfSubnormalExponent = 1 - halfBias - halfMantissaBits
+)
+
+// toHalf returns the half float nearest to f, rounding ties to even. Values
+// too large for a half become infinite, and values too small become 0.
+func toHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & halfSign // nolint: mnd // the sign moves from bit 31 to bit 15
+	exponent := int(bits>>floatMantissaBits) & floatExponentMax
+	mantissa := bits & floatMantissa
+	if exponent == floatExponentMax {
+		if mantissa != 0 {
+			return sign | halfQuietNaN
+		}
+		return sign | halfInfinity
+	}
+	e := exponent - floatBias + halfBias
+	if e >= halfExponentMax {
+		return sign | halfInfinity
+	}
+	if e <= 0 {
+		// Too small for a normal half: shift the mantissa, with its implicit
+		// leading 1, down to a subnormal's.
+		shift := mantissaShift + 1 - e
+		if shift > floatMantissaBits+1 {
+			return sign
+		}
+		return sign | uint16(roundShift(mantissa|1<<floatMantissaBits, shift))
+	}
+	// Rounding may carry into the exponent, which is still right, up to
+	// infinity.
+	return sign | uint16(roundShift(uint32(e)<<floatMantissaBits|mantissa, mantissaShift))
+}
+
+// roundShift returns v shifted right by shift bits, rounded to the nearest
+// integer with ties to even.
+func roundShift(v uint32, shift int) uint32 {
+	shifted := v >> shift
+	rest := v & (1<<shift - 1)
+	half := uint32(1) << (shift - 1)
+	if rest > half || (rest == half && shifted&1 == 1) {
+		shifted++
+	}
+	return shifted
+}
+
+// fromHalf returns the value of the half float h.
+func fromHalf(h uint16) float32 {
+	sign := uint32(h&halfSign) << 16 // nolint: mnd // the sign moves from bit 15 to bit 31
+	exponent := uint32(h>>halfMantissaBits) & halfExponentMax
+	mantissa := uint32(h) & (1<<halfMantissaBits - 1)
+	switch exponent {
+	case 0:
+		f := float32(math.Ldexp(float64(mantissa), halfSubnormalExponent))
+		if sign != 0 {
+			return -f
+		}
+		return f
+	case halfExponentMax:
+		return math.Float32frombits(sign | floatExponentMax<<floatMantissaBits | mantissa<<mantissaShift)
+	}
+	return math.Float32frombits(sign | (exponent-halfBias+floatBias)<<floatMantissaBits | mantissa<<mantissaShift)
+}
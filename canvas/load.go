@@ -0,0 +1,180 @@
+package canvas
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // registers JPEG decoding for Load
+	_ "image/png"  // registers PNG decoding for Load
+	"io"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/tuples"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPPM is returned when PPM data cannot be parsed.
+var ErrInvalidPPM = errors.New("invalid PPM")
+
+// Load reads an image file into a canvas. PPM files are read with ReadPPM,
+// OpenEXR files with ReadEXR, keeping the main image or else the first
+// layer, and PNG and JPEG files with the image package.
+func Load(path string) (*Canvas, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening image: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ppm":
+		var c *Canvas
+		if c, err = ReadPPM(file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return c, nil
+	case ".exr":
+		var layers []Layer
+		if layers, err = ReadEXR(file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		// Layers are sorted by name, so the main image, if any, comes first.
+		return layers[0].Canvas, nil
+	}
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return FromImage(img), nil
+}
+
+// FromImage converts an image to a canvas, with channels scaled to [0, 1].
+func FromImage(img image.Image) *Canvas {
+	bounds := img.Bounds()
+	c := NewCanvas(bounds.Dx(), bounds.Dy())
+	for y := range c.Height {
+		for x := range c.Width {
+			pixel, _ := color.NRGBA64Model.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA64)
+			c.WritePixel(x, y, tuples.NewColor(
+				float64(pixel.R)/maxChannel16,
+				float64(pixel.G)/maxChannel16,
+				float64(pixel.B)/maxChannel16,
+			))
+		}
+	}
+	return c
+}
+
+// maxChannel16 is the largest value of a 16-bit color channel.
+const maxChannel16 = 0xffff
+
+// ReadPPM parses a plain (P3) or binary (P6) PPM image.
+func ReadPPM(r io.Reader) (*Canvas, error) {
+	p := &ppmReader{r: bufio.NewReader(r)}
+	magic := p.token()
+	if magic != "P3" && magic != "P6" {
+		return nil, fmt.Errorf("%w: unsupported magic number %q, expected P3 or P6", ErrInvalidPPM, magic)
+	}
+	width, height, maxValue := p.number("width"), p.number("height"), p.number("maximum value")
+	if p.err != nil {
+		return nil, p.err
+	}
+	if width <= 0 || height <= 0 || maxValue <= 0 || maxValue > maxChannel16 {
+		return nil, fmt.Errorf("%w: bad header %dx%d with maximum value %d", ErrInvalidPPM, width, height, maxValue)
+	}
+
+	c := NewCanvas(width, height)
+	scale := float64(maxValue)
+	for i := range c.Pixels {
+		var rgb [3]int
+		for j := range rgb {
+			if magic == "P3" {
+				rgb[j] = p.number("pixel value")
+			} else {
+				rgb[j] = p.binary(maxValue)
+			}
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		c.Pixels[i] = tuples.NewColor(float64(rgb[0])/scale, float64(rgb[1])/scale, float64(rgb[2])/scale)
+	}
+	return c, nil
+}
+
+// ppmReader reads the whitespace separated tokens of a PPM file, skipping
+// comments, and remembers the first error.
+type ppmReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (p *ppmReader) token() string {
+	if p.err != nil {
+		return ""
+	}
+	var token []byte
+	for {
+		b, err := p.r.ReadByte()
+		if err != nil {
+			if len(token) == 0 {
+				p.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidPPM)
+			}
+			return string(token)
+		}
+		switch {
+		case b == '#':
+			if _, err = p.r.ReadString('\n'); err != nil && !errors.Is(err, io.EOF) {
+				p.err = fmt.Errorf("reading PPM: %w", err)
+				return ""
+			}
+			if len(token) > 0 {
+				return string(token)
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			if len(token) > 0 {
+				return string(token)
+			}
+		default:
+			token = append(token, b)
+		}
+	}
+}
+
+func (p *ppmReader) number(what string) int {
+	token := p.token()
+	if p.err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(token)
+	if err != nil || n < 0 {
+		p.err = fmt.Errorf("%w: expected %s, got %q", ErrInvalidPPM, what, token)
+	}
+	return n
+}
+
+// binary reads one raw sample of a P6 image, which takes two bytes when the
+// maximum value does not fit in one.
+func (p *ppmReader) binary(maxValue int) int {
+	if p.err != nil {
+		return 0
+	}
+	const byteMax, byteBits = 0xff, 8
+	b, err := p.r.ReadByte()
+	if err != nil {
+		p.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidPPM)
+		return 0
+	}
+	if maxValue <= byteMax {
+		return int(b)
+	}
+	low, err := p.r.ReadByte()
+	if err != nil {
+		p.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidPPM)
+		return 0
+	}
+	return int(b)<<byteBits | int(low)
+}
@@ -0,0 +1,154 @@
+package canvas_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/tuples"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadingPPMWithWrongMagicNumber(t *testing.T) {
+	// Scenario: Reading a file with the wrong magic number fails
+	_, err := canvas.ReadPPM(strings.NewReader("P32\n1 1\n255\n0 0 0\n"))
+	assert.ErrorIs(t, err, canvas.ErrInvalidPPM)
+}
+
+func TestReadingPPMReturnsCanvasOfRightSize(t *testing.T) {
+	// Scenario: Reading a PPM returns a canvas of the right size
+	ppm := "P3\n10 2\n255\n" + strings.Repeat("0 0 0  ", 20) + "\n"
+	c, err := canvas.ReadPPM(strings.NewReader(ppm))
+	require.NoError(t, err)
+	assert.Equal(t, 10, c.Width)
+	assert.Equal(t, 2, c.Height)
+}
+
+func TestReadingPixelDataFromPPM(t *testing.T) {
+	// Scenario Outline: Reading pixel data from a PPM file
+	ppm := `P3
+4 3
+255
+255 127 0  0 127 255  127 255 0  255 255 255
+0 0 0  255 0 0  0 255 0  0 0 255
+255 255 0  0 255 255  255 0 255  127 127 127
+`
+	c, err := canvas.ReadPPM(strings.NewReader(ppm))
+	require.NoError(t, err)
+	tests := []struct {
+		x, y     int
+		expected tuples.Color
+	}{
+		{0, 0, tuples.NewColor(1, 0.498, 0)},
+		{1, 0, tuples.NewColor(0, 0.498, 1)},
+		{2, 0, tuples.NewColor(0.498, 1, 0)},
+		{3, 0, tuples.NewColor(1, 1, 1)},
+		{0, 1, tuples.NewColor(0, 0, 0)},
+		{1, 1, tuples.NewColor(1, 0, 0)},
+		{2, 1, tuples.NewColor(0, 1, 0)},
+		{3, 1, tuples.NewColor(0, 0, 1)},
+		{0, 2, tuples.NewColor(1, 1, 0)},
+		{1, 2, tuples.NewColor(0, 1, 1)},
+		{2, 2, tuples.NewColor(1, 0, 1)},
+		{3, 2, tuples.NewColor(0.498, 0.498, 0.498)},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected.Red(), c.PixelAt(tt.x, tt.y).Red(), 0.001)
+		assert.InDelta(t, tt.expected.Green(), c.PixelAt(tt.x, tt.y).Green(), 0.001)
+		assert.InDelta(t, tt.expected.Blue(), c.PixelAt(tt.x, tt.y).Blue(), 0.001)
+	}
+}
+
+func TestPPMParsingIgnoresComments(t *testing.T) {
+	// Scenario: PPM parsing ignores comment lines
+	ppm := `P3
+# this is a comment
+2 1
+# this, too
+255
+# another comment
+255 255 255
+# oh, no, comments in the pixel data!
+255 0 255
+`
+	c, err := canvas.ReadPPM(strings.NewReader(ppm))
+	require.NoError(t, err)
+	assert.True(t, tuples.NewColor(1, 1, 1).Equals(c.PixelAt(0, 0).Tuple))
+	assert.True(t, tuples.NewColor(1, 0, 1).Equals(c.PixelAt(1, 0).Tuple))
+}
+
+func TestPPMTripleMaySpanLines(t *testing.T) {
+	// Scenario: PPM parsing allows an RGB triple to span lines
+	ppm := "P3\n1 1\n255\n51\n153\n\n204\n"
+	c, err := canvas.ReadPPM(strings.NewReader(ppm))
+	require.NoError(t, err)
+	assert.True(t, tuples.NewColor(0.2, 0.6, 0.8).Equals(c.PixelAt(0, 0).Tuple))
+}
+
+func TestPPMParsingRespectsScale(t *testing.T) {
+	// Scenario: PPM parsing respects the scale setting
+	ppm := "P3\n2 2\n100\n100 100 100  50 50 50\n75 50 25  0 0 0\n"
+	c, err := canvas.ReadPPM(strings.NewReader(ppm))
+	require.NoError(t, err)
+	assert.True(t, tuples.NewColor(0.75, 0.5, 0.25).Equals(c.PixelAt(0, 1).Tuple))
+}
+
+func TestReadingBinaryPPM(t *testing.T) {
+	// Scenario: Reading pixel data from a binary PPM file
+	ppm := append([]byte("P6\n2 1\n255\n"), 255, 0, 51, 0, 102, 255)
+	c, err := canvas.ReadPPM(bytes.NewReader(ppm))
+	require.NoError(t, err)
+	assert.True(t, tuples.NewColor(1, 0, 0.2).Equals(c.PixelAt(0, 0).Tuple))
+	assert.True(t, tuples.NewColor(0, 0.4, 1).Equals(c.PixelAt(1, 0).Tuple))
+}
+
+func TestReadingTruncatedPPM(t *testing.T) {
+	// Scenario: Reading a PPM with missing pixel data fails
+	_, err := canvas.ReadPPM(strings.NewReader("P3\n2 1\n255\n255 255 255\n"))
+	assert.ErrorIs(t, err, canvas.ErrInvalidPPM)
+}
+
+func TestPPMRoundTrip(t *testing.T) {
+	// Scenario: A canvas written as PPM reads back the same
+	c := canvas.NewCanvas(3, 2)
+	c.WritePixel(0, 0, tuples.NewColor(1, 0, 0))
+	c.WritePixel(2, 1, tuples.NewColor(0.2, 0.4, 0.6))
+	read, err := canvas.ReadPPM(strings.NewReader(c.ToPPM()))
+	require.NoError(t, err)
+	for i := range c.Pixels {
+		assert.InDelta(t, c.Pixels[i].Red(), read.Pixels[i].Red(), 0.002)
+		assert.InDelta(t, c.Pixels[i].Green(), read.Pixels[i].Green(), 0.002)
+		assert.InDelta(t, c.Pixels[i].Blue(), read.Pixels[i].Blue(), 0.002)
+	}
+}
+
+func TestLoadingPNG(t *testing.T) {
+	// Scenario: Loading a PNG file into a canvas
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(1, 0, color.NRGBA{R: 255, G: 0, B: 255, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	path := filepath.Join(t.TempDir(), "texture.png")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+
+	c, err := canvas.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.Width)
+	assert.True(t, tuples.NewColor(1, 0, 1).Equals(c.PixelAt(1, 0).Tuple))
+}
+
+func TestLoadingPPMFileReportsPath(t *testing.T) {
+	// Scenario: Errors loading a PPM file name the file
+	path := filepath.Join(t.TempDir(), "broken.ppm")
+	require.NoError(t, os.WriteFile(path, []byte("P5\n"), 0600))
+	_, err := canvas.Load(path)
+	require.ErrorIs(t, err, canvas.ErrInvalidPPM)
+	assert.Contains(t, err.Error(), path)
+}
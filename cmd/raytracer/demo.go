@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+)
+
+func runDemo(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("demo", flag.ContinueOnError)
+	output := fs.String("o", "", "output image, .png or .ppm, defaults to <demo>.ppm")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err = requireArgs("demo", positional, 1, "one of clock, projectile or silhouette"); err != nil {
+		return err
+	}
+
+	demos := map[string]func() *canvas.Canvas{
+		"clock":      drawClock,
+		"projectile": drawProjectile,
+		"silhouette": drawSilhouette,
+	}
+	name := positional[0]
+	draw, ok := demos[name]
+	if !ok {
+		return fmt.Errorf("%w: unknown demo %q, expected one of clock, projectile or silhouette", errUsage, name)
+	}
+	if *output == "" {
+		*output = name + ".ppm"
+	}
+	if err = writeCanvas(draw(), *output); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "drew %s to %s\n", name, *output)
+	return nil
+}
+
+// drawClock draws the twelve hour marks of a clock face.
+func drawClock() *canvas.Canvas {
+	const (
+		canvasSize   = 500
+		scale        = 150
+		translate    = 250
+		hours        = 12
+		rotationStep = 6
+	)
+	c := canvas.NewCanvas(canvasSize, canvasSize)
+	white := tuples.NewColor(1, 1, 1)
+
+	// Create a transformation matrix that scales and translates the points
+	transform := matrices.Translation(translate, translate, 0).Multiply(matrices.Scaling(scale, scale, 0))
+
+	for i := range hours {
+		rotation := matrices.RotationZ(float64(i) * math.Pi / rotationStep)
+		p := transform.Multiply(rotation).MultiplyTuple(tuples.Point(0, 1, 0))
+		c.WritePixel(int(p.X), int(p.Y), white)
+	}
+	return c
+}
+
+// drawProjectile plots the trajectory of a projectile launched against
+// gravity and wind.
+func drawProjectile() *canvas.Canvas {
+	const (
+		canvasWidth          = 900
+		canvasHeight         = 550
+		velocityMultiplier   = 11.25
+		projectileUpVelocity = 1.8
+		gravity              = -0.1
+		wind                 = -0.01
+	)
+	position := tuples.Point(0, 1, 0)
+	velocity := tuples.Normalize(tuples.Vector(1, projectileUpVelocity, 0)).Multiply(velocityMultiplier)
+	acceleration := tuples.Vector(wind, gravity, 0)
+
+	c := canvas.NewCanvas(canvasWidth, canvasHeight)
+	red := tuples.NewColor(1, 0, 0)
+
+	for position.Y > 0 {
+		x := int(math.Round(position.X))
+		y := int(math.Round(position.Y))
+		if x >= 0 && x < c.Width && y > 0 && y <= c.Height {
+			c.WritePixel(x, c.Height-y, red)
+		}
+		position = position.Add(velocity)
+		velocity = velocity.Add(acceleration)
+	}
+	return c
+}
+
+// drawSilhouette casts a ray from a fixed point through every pixel of a wall
+// behind a unit sphere, drawing the pixels whose ray hits it.
+func drawSilhouette() *canvas.Canvas {
+	const (
+		wallZ        = 10.0
+		wallSize     = 7.0
+		canvasPixels = 100
+	)
+	rayOrigin := tuples.Point(0, 0, -5)
+	pixelSize := wallSize / canvasPixels
+	half := wallSize / 2 // nolint: mnd
+
+	c := canvas.NewCanvas(canvasPixels, canvasPixels)
+	color := tuples.NewColor(1, 0, 0)
+	shape := spheres.NewSphere()
+
+	for y := range canvasPixels {
+		worldY := half - pixelSize*float64(y)
+		for x := range canvasPixels {
+			worldX := -half + pixelSize*float64(x)
+			position := tuples.Point(worldX, worldY, wallZ)
+			r := rays.New(rayOrigin, tuples.Normalize(position.Subtract(rayOrigin)))
+			if _, found := shape.Intersect(r).Hit(); found {
+				c.WritePixel(x, y, color)
+			}
+		}
+	}
+	return c
+}
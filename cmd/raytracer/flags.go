@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// parseFlags parses args with fs, allowing positional arguments to appear
+// before, between or after the flags, and returns the positional arguments.
+// Errors are reported by the caller, so fs prints nothing itself.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, fmt.Errorf("%w: see the usage of %s below", errUsage, fs.Name())
+			}
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// requireArgs checks that exactly n positional arguments were given.
+func requireArgs(command string, args []string, n int, what string) error {
+	if len(args) != n {
+		return fmt.Errorf("%w: %s expects %s", errUsage, command, what)
+	}
+	return nil
+}
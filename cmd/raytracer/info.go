@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/scene"
+	"raytracer-vibe/sdf"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/world"
+	"slices"
+)
+
+func runInfo(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err = requireArgs("info", positional, 1, "exactly one scene file"); err != nil {
+		return err
+	}
+
+	s, err := scene.Load(positional[0])
+	if err != nil {
+		return err
+	}
+	c := s.Camera
+	fmt.Fprintf(stdout, "camera:  %dx%d, field of view %.4g rad\n", c.HSize, c.VSize, c.FieldOfView)
+	fmt.Fprintf(stdout, "shading: %s, %d samples per pixel\n", integratorName(c.Integrator), max(1, c.Samples))
+	if s.Stereo != nil {
+		fmt.Fprintf(stdout, "stereo:  eyes %.4g apart, converging at %.4g\n",
+			s.Stereo.InterpupillaryDistance, s.Stereo.Convergence)
+	}
+	fmt.Fprintf(stdout, "lights:  %d\n", len(s.World.Lights))
+	lightCounts := map[string]int{}
+	for _, l := range s.World.Lights {
+		lightCounts[lightKind(l)]++
+	}
+	printCounts(stdout, lightCounts)
+
+	fmt.Fprintf(stdout, "objects: %d\n", len(s.World.Objects))
+	shapeCounts := map[string]int{}
+	for _, o := range s.World.Objects {
+		countShapes(o, shapeCounts)
+	}
+	printCounts(stdout, shapeCounts)
+	return nil
+}
+
+// printCounts prints the counts sorted by kind.
+func printCounts(stdout io.Writer, counts map[string]int) {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(stdout, "  %-13s %d\n", kind+":", counts[kind])
+	}
+}
+
+func integratorName(i world.Integrator) string {
+	if _, ok := i.(world.PathTracer); ok {
+		return "path tracing"
+	}
+	return "whitted"
+}
+
+func lightKind(l lights.Light) string {
+	switch l.(type) {
+	case lights.PointLight:
+		return "point"
+	case lights.AreaLight:
+		return "area"
+	case lights.DirectionalLight:
+		return "directional"
+	case lights.SpotLight:
+		return "spot"
+	default:
+		return fmt.Sprintf("%T", l)
+	}
+}
+
+// countShapes counts s and, for composite shapes, all of its descendants by kind.
+func countShapes(s shapes.Shape, counts map[string]int) {
+	switch shape := s.(type) {
+	case *spheres.Sphere:
+		counts["sphere"]++
+	case *csg.CSG:
+		counts["csg"]++
+		countShapes(shape.Left, counts)
+		countShapes(shape.Right, counts)
+	case *sdf.SDF:
+		counts["sdf"]++
+	default:
+		counts[fmt.Sprintf("%T", s)]++
+	}
+}
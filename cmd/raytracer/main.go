@@ -0,0 +1,119 @@
+// Command raytracer renders scenes and demos.
+//
+// Usage:
+//
+//	raytracer render scene.yaml [-o out.png] [-w width] [-h height] [--workers n]
+//	                  [--samples n] [--sampler name] [--filter name] [--seed n]
+//	                  [--adaptive threshold] [--max-depth n] [--density density.png]
+//	                  [--denoise] [--aovs list] [--float]
+//	raytracer info scene.yaml
+//	raytracer demo clock|projectile|silhouette [-o out.ppm]
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/canvas"
+	"strings"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `usage: raytracer <command> [arguments]
+
+commands:
+  render scene.yaml [-o out.png] [-w width] [-h height] [--workers n]
+                    [--samples n] [--sampler name] [--filter name] [--seed n]
+                    [--adaptive threshold] [--max-depth n] [--density density.png]
+                    [--denoise] [--aovs list] [--float]
+        render a YAML scene to a PNG, PPM or OpenEXR image
+  info scene.yaml
+        describe the camera, lights and objects of a YAML scene
+  demo clock|projectile|silhouette [-o out.ppm]
+        draw one of the built-in demo images
+`
+
+// errUsage marks errors caused by invalid command line arguments.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var err error
+	switch args[0] {
+	case "render":
+		err = runRender(args[1:], stdout)
+	case "info":
+		err = runInfo(args[1:], stdout)
+	case "demo":
+		err = runDemo(args[1:], stdout)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "raytracer: %v\n\n%s", err, usage)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "raytracer: %v\n", err)
+		return exitError
+	}
+}
+
+// writeCanvas saves the canvas to path, choosing the format from its extension.
+// OpenEXR images hold ZIP compressed half floats.
+func writeCanvas(c *canvas.Canvas, path string) error {
+	var encode func(w io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		encode = c.WritePNG
+	case ".ppm":
+		encode = func(w io.Writer) error {
+			_, err := io.WriteString(w, c.ToPPM())
+			return err
+		}
+	case ".exr":
+		encode = func(w io.Writer) error {
+			return canvas.WriteEXR(w, []canvas.Layer{{Canvas: c}}, canvas.EXROptions{Compression: canvas.ZIPCompression})
+		}
+	default:
+		return fmt.Errorf("%w: unsupported output format %q, expected .png, .ppm or .exr", errUsage, filepath.Ext(path))
+	}
+	return writeFile(path, encode)
+}
+
+// writeFile creates the file at path and writes it with encode.
+func writeFile(path string, encode func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output: %w", err)
+	}
+	if err = encode(file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
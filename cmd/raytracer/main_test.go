@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sceneYAML = `
+- add: camera
+  width: 4
+  height: 2
+  field-of-view: 0.785
+  from: [0, 0, -5]
+  to: [0, 0, 0]
+  up: [0, 1, 0]
+- add: light
+  at: [-10, 10, -10]
+  intensity: [1, 1, 1]
+- add: sphere
+`
+
+func TestRunExitCodes(t *testing.T) {
+	// Scenario Outline: Commands exit with 0 on success, 1 on failure and 2
+	// on misuse, printing errors to stderr
+	// Given a scene file and a directory for output
+	// When raytracer is run with <args>
+	// Then it exits with <code>
+	// And stdout contains <stdout>, and stderr <stderr>
+	dir := t.TempDir()
+	scenePath := filepath.Join(dir, "scene.yaml")
+	require.NoError(t, os.WriteFile(scenePath, []byte(sceneYAML), 0o600))
+	out := filepath.Join(dir, "out.png")
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"help", []string{"help"}, exitOK, "usage: raytracer", ""},
+		{"render", []string{"render", scenePath, "-o", out}, exitOK, "rendered " + scenePath + " at 4x2", ""},
+		{"info", []string{"info", scenePath}, exitOK, "sphere", ""},
+		{"missing scene", []string{"info", filepath.Join(dir, "missing.yaml")}, exitError, "", "raytracer: "},
+		{"no command", nil, exitUsage, "", "usage: raytracer"},
+		{"unknown command", []string{"paint"}, exitUsage, "", `raytracer: invalid usage: unknown command "paint"`},
+		{"unknown flag", []string{"render", scenePath, "--bogus"}, exitUsage, "",
+			"raytracer: invalid usage: flag provided but not defined: -bogus"},
+		{"missing argument", []string{"render"}, exitUsage, "", "render expects exactly one scene file"},
+		{"flag help", []string{"info", "-h"}, exitUsage, "", "see the usage of info below"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, tt.code, run(tt.args, &stdout, &stderr))
+			assert.Contains(t, stdout.String(), tt.stdout)
+			assert.Contains(t, stderr.String(), tt.stderr)
+			if tt.stderr == "" {
+				assert.Empty(t, stderr.String())
+			}
+			if tt.code == exitUsage {
+				assert.Contains(t, stderr.String(), "usage: raytracer")
+			}
+		})
+	}
+	assert.FileExists(t, out)
+}
+
+func TestParsingFlagsAroundArguments(t *testing.T) {
+	// Scenario: Positional arguments may come before, between or after flags
+	// Given a flag set with -o and -w
+	// When "scene.yaml -o out.png other.yaml -w 3" is parsed
+	// Then the positional arguments are scene.yaml and other.yaml
+	// And the flags are set
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	output := fs.String("o", "", "")
+	width := fs.Int("w", 0, "")
+	positional, err := parseFlags(fs, []string{"scene.yaml", "-o", "out.png", "other.yaml", "-w", "3"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scene.yaml", "other.yaml"}, positional)
+	assert.Equal(t, "out.png", *output)
+	assert.Equal(t, 3, *width)
+
+	_, err = parseFlags(fs, []string{"scene.yaml", "-w", "wide"})
+	require.ErrorIs(t, err, errUsage)
+}
+
+func TestResize(t *testing.T) {
+	// Scenario Outline: A missing dimension keeps the scene's aspect ratio
+	tests := []struct {
+		name                          string
+		width, height                 int
+		expectedWidth, expectedHeight int
+	}{
+		{"both given", 30, 40, 30, 40},
+		{"width only", 50, 0, 50, 25},
+		{"height only", 0, 10, 20, 10},
+		{"never below one pixel", 1, 0, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := resize(200, 100, tt.width, tt.height)
+			assert.Equal(t, tt.expectedWidth, width)
+			assert.Equal(t, tt.expectedHeight, height)
+		})
+	}
+}
+
+func TestValidatingRenderOptions(t *testing.T) {
+	// Scenario Outline: Render options that contradict each other are misuse
+	valid := renderOptions{output: "out.png", maxDepth: 3}
+	tests := []struct {
+		name     string
+		change   func(o *renderOptions)
+		expected string
+	}{
+		{"negative width", func(o *renderOptions) { o.width = -1 }, "-w and -h must not be negative"},
+		{"negative height", func(o *renderOptions) { o.height = -1 }, "-w and -h must not be negative"},
+		{"negative samples", func(o *renderOptions) { o.samples = -1 }, "--samples must not be negative"},
+		{"negative workers", func(o *renderOptions) { o.workers = -1 }, "--workers must not be negative"},
+		{"negative adaptive", func(o *renderOptions) { o.adaptive = -0.1 }, "--adaptive must not be negative"},
+		{"negative max depth", func(o *renderOptions) { o.maxDepth = -1 }, "--max-depth must not be negative"},
+		{"adaptive with samples", func(o *renderOptions) { o.adaptive, o.samples = 0.1, 4 },
+			"--adaptive chooses its own samples and cannot be combined with --samples"},
+		{"density without adaptive", func(o *renderOptions) { o.density = "d.png" }, "--density requires --adaptive"},
+		{"float without exr", func(o *renderOptions) { o.float = true }, "--float requires an .exr output"},
+	}
+	require.NoError(t, valid.validate())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := valid
+			tt.change(&o)
+			err := o.validate()
+			require.ErrorIs(t, err, errUsage)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+	withEXR := valid
+	withEXR.output, withEXR.float = "out.EXR", true
+	assert.NoError(t, withEXR.validate())
+}
+
+func TestParsingAOVs(t *testing.T) {
+	// Scenario: AOVs are named in a comma separated list, or all
+	aovs, err := parseAOVs("")
+	require.NoError(t, err)
+	assert.Empty(t, aovs)
+	aovs, err = parseAOVs("depth,normal")
+	require.NoError(t, err)
+	assert.Equal(t, []world.AOV{world.DepthAOV, world.NormalAOV}, aovs)
+	aovs, err = parseAOVs("all")
+	require.NoError(t, err)
+	assert.Equal(t, world.AllAOVs(), aovs)
+	_, err = parseAOVs("depth,shine")
+	require.ErrorIs(t, err, errUsage)
+	assert.Contains(t, err.Error(), `unknown AOV "shine"`)
+}
+
+func TestUnknownSamplersAndFilters(t *testing.T) {
+	// Scenario: Samplers and filters are chosen by name, and unknown names are
+	// misuse
+	for _, name := range []string{"regular", "jittered", "halton", "sobol"} {
+		_, err := newSampler(name, 1)
+		require.NoError(t, err, name)
+	}
+	_, err := newSampler("stratified", 1)
+	require.ErrorIs(t, err, errUsage)
+	assert.Contains(t, err.Error(), `unknown sampler "stratified"`)
+
+	for _, name := range []string{"box", "tent", "gaussian", "mitchell"} {
+		_, err = newFilter(name)
+		require.NoError(t, err, name)
+	}
+	_, err = newFilter("lanczos")
+	require.ErrorIs(t, err, errUsage)
+	assert.Contains(t, err.Error(), `unknown filter "lanczos"`)
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/denoise"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/scene"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"slices"
+	"strings"
+	"time"
+)
+
+// renderOptions holds the command line settings of the render command.
+type renderOptions struct {
+	output   string
+	width    int
+	height   int
+	samples  int
+	sampler  string
+	filter   string
+	seed     uint64
+	adaptive float64
+	maxDepth int
+	density  string
+	workers  int
+	denoise  bool
+	aovs     string
+	float    bool
+}
+
+func runRender(args []string, stdout io.Writer) error {
+	var opts renderOptions
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.StringVar(&opts.output, "o", "out.png", "output image, .png, .ppm or .exr")
+	fs.IntVar(&opts.width, "w", 0, "image width in pixels, defaults to the scene's camera")
+	fs.IntVar(&opts.height, "h", 0, "image height in pixels, defaults to the scene's camera")
+	fs.IntVar(&opts.samples, "samples", 0, "samples per pixel, defaults to the scene's camera or 1")
+	fs.StringVar(&opts.sampler, "sampler", "regular", "sample placement: regular, jittered, halton or sobol")
+	fs.StringVar(&opts.filter, "filter", "box", "reconstruction filter: box, tent, gaussian or mitchell")
+	fs.Uint64Var(&opts.seed, "seed", 0, "seed for the jittered, halton and sobol samplers")
+	fs.Float64Var(&opts.adaptive, "adaptive", 0, "contrast above which pixels are refined, 0 disables adaptive sampling")
+	fs.IntVar(&opts.maxDepth, "max-depth", camera.DefaultAdaptive().MaxDepth,
+		"how many times adaptive sampling may split a pixel into quarters")
+	fs.StringVar(&opts.density, "density", "", "also write an image of the adaptive sample density")
+	fs.IntVar(&opts.workers, "workers", 0, "number of concurrent render workers, defaults to one per CPU")
+	fs.BoolVar(&opts.denoise, "denoise", false, "smooth out path tracing noise, guided by the albedo, normals and depth")
+	fs.StringVar(&opts.aovs, "aovs", "", "comma separated AOVs to write beside the image, or all: "+aovList())
+	fs.BoolVar(&opts.float, "float", false, "store .exr channels as 32-bit floats rather than half floats")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err = requireArgs("render", positional, 1, "exactly one scene file"); err != nil {
+		return err
+	}
+	if err = opts.validate(); err != nil {
+		return err
+	}
+	sampler, err := newSampler(opts.sampler, opts.seed)
+	if err != nil {
+		return err
+	}
+	filter, err := newFilter(opts.filter)
+	if err != nil {
+		return err
+	}
+	aovs, err := parseAOVs(opts.aovs)
+	if err != nil {
+		return err
+	}
+
+	s, err := scene.Load(positional[0])
+	if err != nil {
+		return err
+	}
+	c := s.Camera
+	if opts.width > 0 || opts.height > 0 {
+		c.SetSize(resize(c.HSize, c.VSize, opts.width, opts.height))
+	}
+	if opts.samples > 0 {
+		c.Samples = opts.samples
+	}
+	c.Sampler = sampler
+	c.Filter = filter
+	c.Workers = opts.workers
+
+	start := time.Now()
+	image, density, layers := render(c, s, opts, aovs)
+	if err = writeImages(image, layers, opts); err != nil {
+		return err
+	}
+	if opts.density != "" {
+		if err = writeCanvas(density, opts.density); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(stdout, "rendered %s at %dx%d in %s to %s\n",
+		positional[0], image.Width, image.Height, time.Since(start).Round(time.Millisecond), opts.output)
+	return nil
+}
+
+func (o renderOptions) validate() error {
+	switch {
+	case o.width < 0 || o.height < 0:
+		return fmt.Errorf("%w: -w and -h must not be negative", errUsage)
+	case o.samples < 0:
+		return fmt.Errorf("%w: --samples must not be negative", errUsage)
+	case o.workers < 0:
+		return fmt.Errorf("%w: --workers must not be negative", errUsage)
+	case o.adaptive < 0:
+		return fmt.Errorf("%w: --adaptive must not be negative", errUsage)
+	case o.maxDepth < 0:
+		return fmt.Errorf("%w: --max-depth must not be negative", errUsage)
+	case o.adaptive > 0 && o.samples > 1:
+		return fmt.Errorf("%w: --adaptive chooses its own samples and cannot be combined with --samples", errUsage)
+	case o.adaptive == 0 && o.density != "":
+		return fmt.Errorf("%w: --density requires --adaptive", errUsage)
+	case o.float && !isEXR(o.output):
+		return fmt.Errorf("%w: --float requires an .exr output", errUsage)
+	}
+	return nil
+}
+
+// render renders the scene, once for each eye when it has a stereo rig, and
+// returns the image, the sample density for adaptive sampling, and a layer
+// for each of the AOVs.
+func render(
+	c *camera.Camera, s *scene.Scene, opts renderOptions, aovs []world.AOV,
+) (*canvas.Canvas, *canvas.Canvas, []canvas.Layer) {
+	if s.Stereo == nil {
+		return renderEye(c, s, opts, aovs)
+	}
+	left, right := s.Stereo.Eyes()
+	leftImage, leftDensity, leftLayers := renderEye(left, s, opts, aovs)
+	rightImage, rightDensity, rightLayers := renderEye(right, s, opts, aovs)
+	image := s.Stereo.Combine(leftImage, rightImage)
+	var density *canvas.Canvas
+	if leftDensity != nil {
+		density = s.Stereo.Combine(leftDensity, rightDensity)
+	}
+	var layers []canvas.Layer
+	for i, layer := range leftLayers {
+		layers = append(layers, canvas.Layer{
+			Name:   layer.Name,
+			Canvas: s.Stereo.Combine(layer.Canvas, rightLayers[i].Canvas),
+		})
+	}
+	return image, density, layers
+}
+
+func renderEye(
+	c *camera.Camera, s *scene.Scene, opts renderOptions, aovs []world.AOV,
+) (*canvas.Canvas, *canvas.Canvas, []canvas.Layer) {
+	var image, density *canvas.Canvas
+	if opts.adaptive == 0 {
+		image = c.Render(s.World)
+	} else {
+		image, density = c.RenderAdaptive(s.World, camera.Adaptive{Threshold: opts.adaptive, MaxDepth: opts.maxDepth})
+	}
+	if opts.denoise {
+		image = denoise.ATrous(image, c.RenderFeatures(s.World), denoise.DefaultOptions())
+	}
+	var layers []canvas.Layer
+	if len(aovs) > 0 {
+		layers = c.RenderAOVs(s.World, aovs)
+	}
+	return image, density, layers
+}
+
+// parseAOVs parses a comma separated list of AOV names, or all for every
+// AOV.
+func parseAOVs(list string) ([]world.AOV, error) {
+	if list == "" {
+		return nil, nil
+	}
+	if list == "all" {
+		return world.AllAOVs(), nil
+	}
+	var aovs []world.AOV
+	for _, name := range strings.Split(list, ",") {
+		i := slices.IndexFunc(world.AllAOVs(), func(aov world.AOV) bool { return aov.String() == name })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unknown AOV %q, expected all or some of %s", errUsage, name, aovList())
+		}
+		aovs = append(aovs, world.AllAOVs()[i])
+	}
+	return aovs, nil
+}
+
+func aovList() string {
+	var names []string
+	for _, aov := range world.AllAOVs() {
+		names = append(names, aov.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// writeImages writes the image to the output, with an image for each AOV
+// layer beside it, or for OpenEXR output, the layers in the same file.
+func writeImages(image *canvas.Canvas, layers []canvas.Layer, opts renderOptions) error {
+	if isEXR(opts.output) {
+		o := canvas.EXROptions{Compression: canvas.ZIPCompression}
+		if opts.float {
+			o.Channels = canvas.FloatChannels
+		}
+		layers = append([]canvas.Layer{{Canvas: image}}, layers...)
+		return writeFile(opts.output, func(w io.Writer) error { return canvas.WriteEXR(w, layers, o) })
+	}
+	if err := writeCanvas(image, opts.output); err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		if err := writeCanvas(viewable(layer), layerPath(opts.output, layer.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isEXR(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".exr")
+}
+
+// layerPath returns the path of the image of a layer, named after the
+// output image: out.depth.png for the depth layer of out.png.
+func layerPath(output, name string) string {
+	ext := filepath.Ext(output)
+	return strings.TrimSuffix(output, ext) + "." + name + ext
+}
+
+// viewable returns the canvas of a layer mapped into the range an image
+// can show: normals from -1 to 1 are halved and shifted to lie between 0
+// and 1, and depths are inverted and scaled so that the nearest surfaces are
+// white, fading with distance to the black of the background.
+func viewable(layer canvas.Layer) *canvas.Canvas {
+	const half = 0.5
+	out := canvas.NewCanvas(layer.Canvas.Width, layer.Canvas.Height)
+	switch layer.Name {
+	case world.NormalAOV.String():
+		for i, c := range layer.Canvas.Pixels {
+			out.Pixels[i] = c.Multiply(half).Add(gray(half))
+		}
+	case world.DepthAOV.String():
+		nearest := math.Inf(1)
+		for _, c := range layer.Canvas.Pixels {
+			if c.Red() > 0 {
+				nearest = min(nearest, c.Red())
+			}
+		}
+		for i, c := range layer.Canvas.Pixels {
+			if c.Red() > 0 {
+				out.Pixels[i] = gray(nearest / c.Red())
+			}
+		}
+	default:
+		return layer.Canvas
+	}
+	return out
+}
+
+// resize returns the requested size, deriving a missing dimension from the
+// scene's aspect ratio.
+func resize(sceneWidth, sceneHeight, width, height int) (int, int) {
+	switch {
+	case height == 0:
+		height = max(1, int(math.Round(float64(width*sceneHeight)/float64(sceneWidth))))
+	case width == 0:
+		width = max(1, int(math.Round(float64(height*sceneWidth)/float64(sceneHeight))))
+	}
+	return width, height
+}
+
+func newSampler(name string, seed uint64) (sampling.Sampler, error) {
+	switch name {
+	case "regular":
+		return sampling.NewRegular(), nil
+	case "jittered":
+		return sampling.NewJittered(seed), nil
+	case "halton":
+		return sampling.NewHalton(seed), nil
+	case "sobol":
+		return sampling.NewSobol(seed), nil
+	}
+	return nil, fmt.Errorf("%w: unknown sampler %q, expected one of regular, jittered, halton, sobol", errUsage, name)
+}
+
+func newFilter(name string) (sampling.Filter, error) {
+	switch name {
+	case "box":
+		return sampling.NewBox(), nil
+	case "tent":
+		return sampling.NewTent(), nil
+	case "gaussian":
+		return sampling.NewGaussian(), nil
+	case "mitchell":
+		return sampling.NewMitchell(), nil
+	}
+	return nil, fmt.Errorf("%w: unknown filter %q, expected one of box, tent, gaussian, mitchell", errUsage, name)
+}
+
+func gray(v float64) tuples.Color {
+	return tuples.NewColor(v, v, v)
+}
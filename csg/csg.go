@@ -0,0 +1,148 @@
+package csg
+
+import (
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/objects"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/tuples"
+)
+
+// Operation selects how the two operands of a CSG shape are combined.
+type Operation int
+
+const (
+	// Union keeps every surface that is not inside the other operand.
+	Union Operation = iota
+	// Intersection keeps only the surfaces where both operands overlap.
+	Intersection
+	// Difference keeps the left operand with the right operand carved out of it.
+	Difference
+)
+
+// CSG is a constructive solid geometry shape combining two child shapes.
+// Intersections always refer to the children, so the children's materials
+// are used for shading; setting the material of a CSG shape propagates it
+// to both operands.
+type CSG struct {
+	Operation   Operation
+	Left, Right shapes.Shape
+	Transform   matrices.Matrix
+	// Motion, when set, moves the shape over time in place of Transform.
+	Motion   *matrices.Motion
+	Material materials.Material
+	parent   shapes.Shape
+}
+
+// New creates a CSG shape and makes it the parent of both operands.
+func New(op Operation, left, right shapes.Shape) *CSG {
+	c := &CSG{
+		Operation: op,
+		Left:      left,
+		Right:     right,
+		Transform: matrices.Identity(matrices.DefaultMatrixSize),
+		Material:  materials.NewMaterial(),
+	}
+	left.SetParent(c)
+	right.SetParent(c)
+	return c
+}
+
+func (c *CSG) SetTransform(m matrices.Matrix) {
+	c.Transform = m
+}
+
+func (c *CSG) GetTransform() matrices.Matrix {
+	return c.Transform
+}
+
+func (c *CSG) GetMotion() *matrices.Motion {
+	return c.Motion
+}
+
+func (c *CSG) SetMotion(m *matrices.Motion) {
+	c.Motion = m
+}
+
+func (c *CSG) GetMaterial() materials.Material {
+	return c.Material
+}
+
+func (c *CSG) SetMaterial(m materials.Material) {
+	c.Material = m
+	c.Left.SetMaterial(m)
+	c.Right.SetMaterial(m)
+}
+
+func (c *CSG) Parent() shapes.Shape {
+	return c.parent
+}
+
+func (c *CSG) SetParent(p shapes.Shape) {
+	c.parent = p
+}
+
+// Intersect intersects the ray with both operands and keeps only the
+// intersections that lie on the surface of the combined shape.
+func (c *CSG) Intersect(r rays.Ray) intersections.Intersections {
+	localRay := r.Transform(shapes.TransformAt(c, r.Time).Inverse())
+	leftXs := c.Left.Intersect(localRay)
+	rightXs := c.Right.Intersect(localRay)
+	xs := make(intersections.Intersections, 0, len(leftXs)+len(rightXs))
+	xs = append(xs, leftXs...)
+	xs = append(xs, rightXs...)
+	return c.FilterIntersections(intersections.NewIntersections(xs...))
+}
+
+// NormalAt panics, as the Shape interface allows composite shapes to: it is
+// never needed for a CSG shape, since every intersection it returns refers to
+// one of its children.
+func (c *CSG) NormalAt(_ tuples.Tuple, _ float64) tuples.Tuple {
+	panic("csg: NormalAt called on a CSG shape; normals come from its children")
+}
+
+// FilterIntersections walks the sorted intersections, tracking whether the
+// ray is inside each operand, and keeps those allowed by the operation.
+func (c *CSG) FilterIntersections(xs intersections.Intersections) intersections.Intersections {
+	inLeft := false
+	inRight := false
+	result := intersections.NewIntersections()
+
+	for _, i := range xs {
+		leftHit := includes(c.Left, i.Object)
+		if IntersectionAllowed(c.Operation, leftHit, inLeft, inRight) {
+			result = append(result, i)
+		}
+		if leftHit {
+			inLeft = !inLeft
+		} else {
+			inRight = !inRight
+		}
+	}
+
+	return result
+}
+
+// IntersectionAllowed reports whether an intersection with the left (leftHit)
+// or right operand survives the operation, given whether the ray is currently
+// inside the left and right operands.
+func IntersectionAllowed(op Operation, leftHit, inLeft, inRight bool) bool {
+	switch op {
+	case Union:
+		return (leftHit && !inRight) || (!leftHit && !inLeft)
+	case Intersection:
+		return (leftHit && inRight) || (!leftHit && inLeft)
+	case Difference:
+		return (leftHit && !inRight) || (!leftHit && inLeft)
+	}
+	return false
+}
+
+func includes(s shapes.Shape, object objects.Object) bool {
+	if c, ok := s.(*CSG); ok {
+		return includes(c.Left, object) || includes(c.Right, object)
+	}
+	return objects.Object(s) == object
+}
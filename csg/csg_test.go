@@ -0,0 +1,247 @@
+package csg_test
+
+import (
+	"raytracer-vibe/csg"
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSGIsCreatedWithOperationAndTwoShapes(t *testing.T) {
+	// Scenario: CSG is created with an operation and two shapes
+	// Given s1 ← sphere()
+	// And s2 ← sphere()
+	// When c ← csg("union", s1, s2)
+	// Then c.operation = "union"
+	// And c.left = s1
+	// And c.right = s2
+	// And s1.parent = c
+	// And s2.parent = c
+	s1 := spheres.NewSphere()
+	s2 := spheres.NewSphere()
+	c := csg.New(csg.Union, s1, s2)
+	assert.Equal(t, csg.Union, c.Operation)
+	assert.Equal(t, s1, c.Left)
+	assert.Equal(t, s2, c.Right)
+	assert.Equal(t, c, s1.Parent())
+	assert.Equal(t, c, s2.Parent())
+}
+
+func TestEvaluatingRuleForCSGOperation(t *testing.T) {
+	// Scenario Outline: Evaluating the rule for a CSG operation
+	// When result ← intersection_allowed("<op>", <lhit>, <inl>, <inr>)
+	// Then result = <result>
+	tests := []struct {
+		op                    csg.Operation
+		leftHit, inL, inR, ok bool
+	}{
+		{csg.Union, true, true, true, false},
+		{csg.Union, true, true, false, true},
+		{csg.Union, true, false, true, false},
+		{csg.Union, true, false, false, true},
+		{csg.Union, false, true, true, false},
+		{csg.Union, false, true, false, false},
+		{csg.Union, false, false, true, true},
+		{csg.Union, false, false, false, true},
+		{csg.Intersection, true, true, true, true},
+		{csg.Intersection, true, true, false, false},
+		{csg.Intersection, true, false, true, true},
+		{csg.Intersection, true, false, false, false},
+		{csg.Intersection, false, true, true, true},
+		{csg.Intersection, false, true, false, true},
+		{csg.Intersection, false, false, true, false},
+		{csg.Intersection, false, false, false, false},
+		{csg.Difference, true, true, true, false},
+		{csg.Difference, true, true, false, true},
+		{csg.Difference, true, false, true, false},
+		{csg.Difference, true, false, false, true},
+		{csg.Difference, false, true, true, true},
+		{csg.Difference, false, true, false, true},
+		{csg.Difference, false, false, true, false},
+		{csg.Difference, false, false, false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.ok, csg.IntersectionAllowed(tt.op, tt.leftHit, tt.inL, tt.inR), tt)
+	}
+}
+
+func TestFilteringListOfIntersections(t *testing.T) {
+	// Scenario Outline: Filtering a list of intersections
+	// Given s1 ← sphere()
+	// And s2 ← sphere()
+	// And c ← csg("<operation>", s1, s2)
+	// And xs ← intersections(1:s1, 2:s2, 3:s1, 4:s2)
+	// When result ← filter_intersections(c, xs)
+	// Then result.count = 2
+	// And result[0] = xs[<x0>]
+	// And result[1] = xs[<x1>]
+	tests := []struct {
+		op     csg.Operation
+		x0, x1 int
+	}{
+		{csg.Union, 0, 3},
+		{csg.Intersection, 1, 2},
+		{csg.Difference, 0, 1},
+	}
+	for _, tt := range tests {
+		s1 := spheres.NewSphere()
+		s2 := spheres.NewSphere()
+		c := csg.New(tt.op, s1, s2)
+		xs := intersections.NewIntersections(
+			intersections.NewIntersection(1, s1),
+			intersections.NewIntersection(2, s2),
+			intersections.NewIntersection(3, s1),
+			intersections.NewIntersection(4, s2),
+		)
+		result := c.FilterIntersections(xs)
+		assert.Len(t, result, 2)
+		assert.Equal(t, xs[tt.x0], result[0])
+		assert.Equal(t, xs[tt.x1], result[1])
+	}
+}
+
+func TestRayMissesCSGObject(t *testing.T) {
+	// Scenario: A ray misses a CSG object
+	// Given c ← csg("union", sphere(), sphere())
+	// And r ← ray(point(0, 2, -5), vector(0, 0, 1))
+	// When xs ← local_intersect(c, r)
+	// Then xs is empty
+	c := csg.New(csg.Union, spheres.NewSphere(), spheres.NewSphere())
+	r := rays.New(tuples.Point(0, 2, -5), tuples.Vector(0, 0, 1))
+	xs := c.Intersect(r)
+	assert.Empty(t, xs)
+}
+
+func TestRayHitsCSGObject(t *testing.T) {
+	// Scenario: A ray hits a CSG object
+	// Given s1 ← sphere()
+	// And s2 ← sphere()
+	// And set_transform(s2, translation(0, 0, 0.5))
+	// And c ← csg("union", s1, s2)
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When xs ← local_intersect(c, r)
+	// Then xs.count = 2
+	// And xs[0].t = 4
+	// And xs[0].object = s1
+	// And xs[1].t = 6.5
+	// And xs[1].object = s2
+	s1 := spheres.NewSphere()
+	s2 := spheres.NewSphere()
+	s2.SetTransform(matrices.Translation(0, 0, 0.5))
+	c := csg.New(csg.Union, s1, s2)
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	xs := c.Intersect(r)
+	assert.Len(t, xs, 2)
+	assert.InEpsilon(t, 4.0, xs[0].T, 0.00001)
+	assert.Equal(t, s1, xs[0].Object)
+	assert.InEpsilon(t, 6.5, xs[1].T, 0.00001)
+	assert.Equal(t, s2, xs[1].Object)
+}
+
+func TestIntersectingLensWithRay(t *testing.T) {
+	// Scenario: A ray passes through the lens formed by two overlapping spheres
+	// Given s1 ← sphere() with transform translation(0, 0, -0.5)
+	// And s2 ← sphere() with transform translation(0, 0, 0.5)
+	// And c ← csg("intersection", s1, s2)
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When xs ← intersect(c, r)
+	// Then xs.count = 2
+	// And xs[0].t = 4.5 on s2
+	// And xs[1].t = 5.5 on s1
+	s1 := spheres.NewSphere()
+	s1.SetTransform(matrices.Translation(0, 0, -0.5))
+	s2 := spheres.NewSphere()
+	s2.SetTransform(matrices.Translation(0, 0, 0.5))
+	c := csg.New(csg.Intersection, s1, s2)
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	xs := c.Intersect(r)
+	assert.Len(t, xs, 2)
+	assert.InEpsilon(t, 4.5, xs[0].T, 0.00001)
+	assert.Equal(t, s2, xs[0].Object)
+	assert.InEpsilon(t, 5.5, xs[1].T, 0.00001)
+	assert.Equal(t, s1, xs[1].Object)
+}
+
+func TestIntersectingTransformedCSGObject(t *testing.T) {
+	// Scenario: The CSG transform is applied before intersecting the operands
+	// Given c ← csg("difference", sphere(), sphere() scaled by 0.5)
+	// And set_transform(c, translation(5, 0, 0))
+	// And r ← ray(point(5, 0, -5), vector(0, 0, 1))
+	// When xs ← intersect(c, r)
+	// Then xs.t = 4, 4.5, 5.5, 6
+	s1 := spheres.NewSphere()
+	s2 := spheres.NewSphere()
+	s2.SetTransform(matrices.Scaling(0.5, 0.5, 0.5))
+	c := csg.New(csg.Difference, s1, s2)
+	c.SetTransform(matrices.Translation(5, 0, 0))
+	r := rays.New(tuples.Point(5, 0, -5), tuples.Vector(0, 0, 1))
+	xs := c.Intersect(r)
+	assert.Len(t, xs, 4)
+	assert.InEpsilon(t, 4.0, xs[0].T, 0.00001)
+	assert.InEpsilon(t, 4.5, xs[1].T, 0.00001)
+	assert.InEpsilon(t, 5.5, xs[2].T, 0.00001)
+	assert.InEpsilon(t, 6.0, xs[3].T, 0.00001)
+}
+
+func TestNestedCSGIncludesGrandchildren(t *testing.T) {
+	// Scenario: Intersections on nested CSG operands are attributed to the right side
+	// Given inner ← csg("union", s1, s2)
+	// And c ← csg("difference", inner, s3)
+	// And xs ← intersections(1:s2, 2:s3, 3:s2, 4:s3)
+	// When result ← filter_intersections(c, xs)
+	// Then result = [xs[0], xs[1]]
+	s1 := spheres.NewSphere()
+	s2 := spheres.NewSphere()
+	s3 := spheres.NewSphere()
+	inner := csg.New(csg.Union, s1, s2)
+	c := csg.New(csg.Difference, inner, s3)
+	xs := intersections.NewIntersections(
+		intersections.NewIntersection(1, s2),
+		intersections.NewIntersection(2, s3),
+		intersections.NewIntersection(3, s2),
+		intersections.NewIntersection(4, s3),
+	)
+	result := c.FilterIntersections(xs)
+	assert.Len(t, result, 2)
+	assert.Equal(t, xs[0], result[0])
+	assert.Equal(t, xs[1], result[1])
+}
+
+func TestIntersectingMovingCSGObject(t *testing.T) {
+	// Scenario: The operands of a moving CSG shape move with it
+	// Given c ← csg("union", sphere(), sphere())
+	// And c moves from identity to translation(0, 3, 0)
+	// And r ← ray(point(0, 3, -5), vector(0, 0, 1))
+	// Then intersect(c, r) misses at time 0
+	// And hits at t = 4 and 6 at time 1
+	c := csg.New(csg.Union, spheres.NewSphere(), spheres.NewSphere())
+	c.SetMotion(matrices.NewMotion(matrices.Identity(4), matrices.Translation(0, 3, 0)))
+	r := rays.New(tuples.Point(0, 3, -5), tuples.Vector(0, 0, 1))
+	assert.Empty(t, c.Intersect(r))
+	r.Time = 1
+	xs := c.Intersect(r)
+	assert.Len(t, xs, 2)
+	assert.InEpsilon(t, 4.0, xs[0].T, 0.00001)
+	assert.InEpsilon(t, 6.0, xs[1].T, 0.00001)
+}
+
+func TestCSGHasNoNormalOfItsOwn(t *testing.T) {
+	// Scenario: Normals come from the children of a CSG shape
+	// Given c ← csg("union", sphere(), sphere())
+	// And xs ← intersect(c, ray(point(0, 0, -5), vector(0, 0, 1)))
+	// Then the normal at the hit comes from the sphere it refers to
+	// And asking c itself for a normal panics
+	c := csg.New(csg.Union, spheres.NewSphere(), spheres.NewSphere())
+	xs := c.Intersect(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	require.Len(t, xs, 2)
+	hit, ok := xs[0].Object.(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(hit.NormalAt(tuples.Point(0, 0, -1), 0)))
+	assert.Panics(t, func() { c.NormalAt(tuples.Point(0, 0, -1), 0) })
+}
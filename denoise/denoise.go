@@ -0,0 +1,172 @@
+// Package denoise smooths the noise out of path traced images, guided by
+// feature buffers of what each pixel sees, so that a few samples per pixel
+// give a usable preview.
+package denoise
+
+import (
+	"math"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/tuples"
+)
+
+// minAlbedo is the albedo below which a channel is filtered as it is,
+// rather than divided by the albedo.
+const minAlbedo = 0.01
+
+// Features are canvases the size of the image showing what each pixel sees,
+// free of noise. Pixels are only blurred together where their features are
+// alike, so that the edges of objects and of their textures stay sharp.
+type Features struct {
+	// Albedo is the color of the surface seen.
+	Albedo *canvas.Canvas
+	// Normal is the surface normal, with x, y and z in red, green and blue.
+	Normal *canvas.Canvas
+	// Depth is the distance to the surface, in every channel.
+	Depth *canvas.Canvas
+}
+
+// Options tune the filter. Each sigma, which must be positive, is the
+// difference at which pixels are weighted e^-1 as much as identical ones:
+// the larger, the more the filter blurs across that kind of difference.
+type Options struct {
+	// Iterations is the number of passes, each twice as wide as the last.
+	// Five passes reach 62 pixels away.
+	Iterations int
+	// ColorSigma is for differences in the light reaching the surfaces.
+	// It halves with each pass, as the noise left does.
+	ColorSigma float64
+	// NormalSigma is for the distance between normals.
+	NormalSigma float64
+	// DepthSigma is for differences in depth, as a fraction of the
+	// larger depth.
+	DepthSigma float64
+	// AlbedoSigma is for differences in albedo.
+	AlbedoSigma float64
+}
+
+// DefaultOptions smooth 8 samples per pixel into a clean preview.
+func DefaultOptions() Options {
+	const (
+		iterations  = 5
+		colorSigma  = 1
+		normalSigma = 0.3
+		depthSigma  = 0.05
+		albedoSigma = 0.1
+	)
+	return Options{
+		Iterations:  iterations,
+		ColorSigma:  colorSigma,
+		NormalSigma: normalSigma,
+		DepthSigma:  depthSigma,
+		AlbedoSigma: albedoSigma,
+	}
+}
+
+// ATrous returns image denoised by the edge-avoiding à-trous wavelet
+// transform: a blur repeated with its taps spread ever further apart,
+// weighting each tap by how alike its features are to the pixel's.
+//
+// The image is divided by the albedo before filtering and multiplied by it
+// after, so that only the light falling on the surfaces is blurred and
+// textures stay as sharp as the albedo shows them.
+func ATrous(image *canvas.Canvas, f Features, o Options) *canvas.Canvas {
+	light := canvas.NewCanvas(image.Width, image.Height)
+	for i, c := range image.Pixels {
+		light.Pixels[i] = demodulate(c, f.Albedo.Pixels[i])
+	}
+	colorSigma := o.ColorSigma
+	for pass := range o.Iterations {
+		light = o.pass(light, f, 1<<pass, colorSigma)
+		colorSigma /= 2
+	}
+	denoised := canvas.NewCanvas(image.Width, image.Height)
+	for i, c := range light.Pixels {
+		denoised.Pixels[i] = remodulate(c, f.Albedo.Pixels[i])
+	}
+	return denoised
+}
+
+// pass blurs light with the kernel's taps step pixels apart.
+func (o Options) pass(light *canvas.Canvas, f Features, step int, colorSigma float64) *canvas.Canvas {
+	// The B3 spline, whose repeated, ever wider application approximates
+	// a Gaussian blur of growing radius.
+	kernel := [...]float64{1.0 / 16, 1.0 / 4, 3.0 / 8, 1.0 / 4, 1.0 / 16} // nolint: mnd // the spline's weights
+	radius := len(kernel) / 2
+	filtered := canvas.NewCanvas(light.Width, light.Height)
+	for y := range light.Height {
+		for x := range light.Width {
+			p := y*light.Width + x
+			sum := tuples.NewColor(0, 0, 0)
+			total := 0.0
+			for j := range kernel {
+				qy := y + (j-radius)*step
+				if qy < 0 || qy >= light.Height {
+					continue
+				}
+				for i := range kernel {
+					qx := x + (i-radius)*step
+					if qx < 0 || qx >= light.Width {
+						continue
+					}
+					q := qy*light.Width + qx
+					weight := kernel[i] * kernel[j] * o.similarity(light, f, p, q, colorSigma)
+					sum = sum.Add(light.Pixels[q].Multiply(weight))
+					total += weight
+				}
+			}
+			// The pixel's own tap always has a weight, so total is never 0.
+			filtered.Pixels[p] = sum.Multiply(1 / total)
+		}
+	}
+	return filtered
+}
+
+// similarity returns how alike pixels p and q are, from 1 for identical
+// pixels down towards 0.
+func (o Options) similarity(light *canvas.Canvas, f Features, p, q int, colorSigma float64) float64 {
+	color := squared(light.Pixels[p].Tuple, light.Pixels[q].Tuple) / (colorSigma * colorSigma)
+	normal := squared(f.Normal.Pixels[p].Tuple, f.Normal.Pixels[q].Tuple) / (o.NormalSigma * o.NormalSigma)
+	albedo := squared(f.Albedo.Pixels[p].Tuple, f.Albedo.Pixels[q].Tuple) / (o.AlbedoSigma * o.AlbedoSigma)
+	depth := relative(f.Depth.Pixels[p].Red(), f.Depth.Pixels[q].Red()) / o.DepthSigma
+	return math.Exp(-color - normal - albedo - depth*depth)
+}
+
+// squared returns the squared distance between a and b.
+func squared(a, b tuples.Tuple) float64 {
+	d := a.Subtract(b)
+	return d.X*d.X + d.Y*d.Y + d.Z*d.Z
+}
+
+// relative returns the difference between depths a and b as a fraction of
+// the larger.
+func relative(a, b float64) float64 {
+	larger := max(a, b)
+	if larger == 0 {
+		return 0
+	}
+	return math.Abs(a-b) / larger
+}
+
+// demodulate divides each channel of c by that of albedo, leaving the light
+// that fell on the surface. Channels too dark to divide by are kept.
+func demodulate(c, albedo tuples.Color) tuples.Color {
+	return tuples.NewColor(
+		c.Red()/albedoFactor(albedo.Red()),
+		c.Green()/albedoFactor(albedo.Green()),
+		c.Blue()/albedoFactor(albedo.Blue()))
+}
+
+// remodulate undoes demodulate.
+func remodulate(c, albedo tuples.Color) tuples.Color {
+	return tuples.NewColor(
+		c.Red()*albedoFactor(albedo.Red()),
+		c.Green()*albedoFactor(albedo.Green()),
+		c.Blue()*albedoFactor(albedo.Blue()))
+}
+
+func albedoFactor(a float64) float64 {
+	if a < minAlbedo {
+		return 1
+	}
+	return a
+}
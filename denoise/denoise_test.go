@@ -0,0 +1,133 @@
+package denoise_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/denoise"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const size = 32
+
+// flatFeatures returns the features of a white wall facing the camera,
+// 5 units away.
+func flatFeatures() denoise.Features {
+	return denoise.Features{
+		Albedo: filled(tuples.NewColor(1, 1, 1)),
+		Normal: filled(tuples.NewColor(0, 0, -1)),
+		Depth:  filled(tuples.NewColor(5, 5, 5)),
+	}
+}
+
+func filled(c tuples.Color) *canvas.Canvas {
+	image := canvas.NewCanvas(size, size)
+	for i := range image.Pixels {
+		image.Pixels[i] = c
+	}
+	return image
+}
+
+// noisy returns image with every pixel scaled by a random
+// factor between 0 and 2, as path tracing with few samples gives.
+func noisy(image *canvas.Canvas) *canvas.Canvas {
+	rng := rand.New(rand.NewPCG(1, 2)) // #nosec G404 -- a fixed seed for repeatable tests
+	out := canvas.NewCanvas(image.Width, image.Height)
+	for i, c := range image.Pixels {
+		out.Pixels[i] = c.Multiply(2 * rng.Float64())
+	}
+	return out
+}
+
+// statistics returns the mean and standard deviation of the red channel of
+// the pixels in columns x0 to x1.
+func statistics(image *canvas.Canvas, x0, x1 int) (float64, float64) {
+	var sum, squares float64
+	n := 0
+	for y := range image.Height {
+		for x := x0; x <= x1; x++ {
+			red := image.PixelAt(x, y).Red()
+			sum += red
+			squares += red * red
+			n++
+		}
+	}
+	mean := sum / float64(n)
+	return mean, math.Sqrt(squares/float64(n) - mean*mean)
+}
+
+func TestDenoisingCleanImage(t *testing.T) {
+	// Scenario: A clean, flat image is left as it is
+	// Given an image of color(0.3, 0.5, 0.7) everywhere and flat features
+	// When it is denoised
+	// Then every pixel is still color(0.3, 0.5, 0.7)
+	image := filled(tuples.NewColor(0.3, 0.5, 0.7))
+	denoised := denoise.ATrous(image, flatFeatures(), denoise.DefaultOptions())
+	for _, c := range denoised.Pixels {
+		assert.True(t, tuples.NewColor(0.3, 0.5, 0.7).Equals(c.Tuple))
+	}
+}
+
+func TestDenoisingSmoothsNoise(t *testing.T) {
+	// Scenario: Noise on a flat wall is smoothed away
+	// Given a noisy image of a wall lit by color(0.5, 0.5, 0.5)
+	// When it is denoised
+	// Then its average stays about 0.5
+	// And it varies less than a quarter as much as before
+	image := noisy(filled(tuples.NewColor(0.5, 0.5, 0.5)))
+	_, before := statistics(image, 0, size-1)
+	mean, after := statistics(denoise.ATrous(image, flatFeatures(), denoise.DefaultOptions()), 0, size-1)
+	assert.InDelta(t, 0.5, mean, 0.02)
+	assert.Less(t, after*4, before)
+}
+
+func TestDenoisingKeepsEdges(t *testing.T) {
+	// Scenario: Noise is not smoothed across the edge between two walls
+	// Given a noisy image of a wall lit by 0.2 on the left, facing the camera
+	// And a wall lit by 0.8 on the right, facing sideways
+	// When it is denoised
+	// Then the columns either side of the edge keep their own light
+	image := canvas.NewCanvas(size, size)
+	f := flatFeatures()
+	for y := range size {
+		for x := range size {
+			if x >= size/2 {
+				image.WritePixel(x, y, tuples.NewColor(0.8, 0.8, 0.8))
+				f.Normal.WritePixel(x, y, tuples.NewColor(1, 0, 0))
+			} else {
+				image.WritePixel(x, y, tuples.NewColor(0.2, 0.2, 0.2))
+			}
+		}
+	}
+	denoised := denoise.ATrous(noisy(image), f, denoise.DefaultOptions())
+	left, _ := statistics(denoised, size/2-1, size/2-1)
+	right, _ := statistics(denoised, size/2, size/2)
+	assert.InDelta(t, 0.2, left, 0.05)
+	assert.InDelta(t, 0.8, right, 0.1)
+}
+
+func TestDenoisingKeepsTextures(t *testing.T) {
+	// Scenario: Textures stay sharp
+	// Given a wall with a checkered albedo of 0.2 and 1, lit evenly by 0.5
+	// When its image is denoised
+	// Then the image is unchanged
+	image := canvas.NewCanvas(size, size)
+	f := flatFeatures()
+	for y := range size {
+		for x := range size {
+			albedo := tuples.NewColor(1, 1, 1)
+			if (x+y)%2 == 0 {
+				albedo = tuples.NewColor(0.2, 0.2, 0.2)
+			}
+			f.Albedo.WritePixel(x, y, albedo)
+			image.WritePixel(x, y, albedo.Multiply(0.5))
+		}
+	}
+	denoised := denoise.ATrous(image, f, denoise.DefaultOptions())
+	for i, c := range denoised.Pixels {
+		assert.True(t, image.Pixels[i].Equals(c.Tuple), "pixel %d", i)
+	}
+}
@@ -1,7 +1,9 @@
 package intersections
 
 import (
+	"cmp"
 	"raytracer-vibe/objects"
+	"slices"
 )
 
 type Intersection struct {
@@ -18,8 +20,14 @@ func NewIntersection(t float64, object objects.Object) Intersection {
 
 type Intersections []Intersection
 
+// NewIntersections aggregates intersections, sorted by increasing t. It
+// sorts a copy, leaving a slice passed with ... in its caller's order.
 func NewIntersections(intersections ...Intersection) Intersections {
-	return intersections
+	sorted := slices.Clone(intersections)
+	slices.SortStableFunc(sorted, func(a, b Intersection) int {
+		return cmp.Compare(a.T, b.T)
+	})
+	return sorted
 }
 
 func (xs Intersections) Hit() (Intersection, bool) {
@@ -41,6 +41,44 @@ func TestIntersections(t *testing.T) {
 	assert.InEpsilon(t, 2.0, xs[1].T, 0.00001)
 }
 
+func TestIntersectionsAreSorted(t *testing.T) {
+	// Scenario: Aggregated intersections are sorted by t
+	// Given o ← mockObject()
+	// And i1 ← intersection(5, o)
+	// And i2 ← intersection(-3, o)
+	// And i3 ← intersection(2, o)
+	// When xs ← intersections(i1, i2, i3)
+	// Then xs[0].t = -3
+	// And xs[1].t = 2
+	// And xs[2].t = 5
+	o := mockObject{}
+	xs := intersections.NewIntersections(
+		intersections.NewIntersection(5, o),
+		intersections.NewIntersection(-3, o),
+		intersections.NewIntersection(2, o),
+	)
+	assert.Len(t, xs, 3)
+	assert.InEpsilon(t, -3.0, xs[0].T, 0.00001)
+	assert.InEpsilon(t, 2.0, xs[1].T, 0.00001)
+	assert.InEpsilon(t, 5.0, xs[2].T, 0.00001)
+}
+
+func TestIntersectionsLeaveTheirArgumentsAlone(t *testing.T) {
+	// Scenario: Aggregating a slice of intersections does not reorder it
+	// Given s ← [intersection(5, o), intersection(-3, o)]
+	// When xs ← intersections(s...)
+	// Then xs[0].t = -3
+	// And s[0].t = 5
+	o := mockObject{}
+	s := []intersections.Intersection{
+		intersections.NewIntersection(5, o),
+		intersections.NewIntersection(-3, o),
+	}
+	xs := intersections.NewIntersections(s...)
+	assert.InEpsilon(t, -3.0, xs[0].T, 0.00001)
+	assert.InEpsilon(t, 5.0, s[0].T, 0.00001)
+}
+
 func TestHit(t *testing.T) {
 	o := mockObject{}
 
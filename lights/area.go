@@ -0,0 +1,82 @@
+package lights
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/tuples"
+)
+
+// AreaLight is a rectangular light divided into a grid of cells, sampled once
+// per cell. Points that see only part of the light are in its penumbra.
+type AreaLight struct {
+	Corner tuples.Tuple
+	// UVec and VVec are the edges of a single cell.
+	UVec   tuples.Tuple
+	USteps int
+	VVec   tuples.Tuple
+	VSteps int
+	// Jitter moves each sample to a random point in its cell rather than its
+	// center, trading the banding of regular samples for noise. The random
+	// points only depend on the point being lit, so renders are repeatable.
+	Jitter    bool
+	Intensity tuples.Color
+}
+
+// NewAreaLight returns a light with corner at corner and edges fullUVec and
+// fullVVec, split into usteps by vsteps cells.
+func NewAreaLight(
+	corner, fullUVec tuples.Tuple, usteps int, fullVVec tuples.Tuple, vsteps int, intensity tuples.Color,
+) AreaLight {
+	return AreaLight{
+		Corner:    corner,
+		UVec:      fullUVec.Multiply(1 / float64(usteps)),
+		USteps:    usteps,
+		VVec:      fullVVec.Multiply(1 / float64(vsteps)),
+		VSteps:    vsteps,
+		Intensity: intensity,
+	}
+}
+
+// Position returns the center of the light.
+func (l AreaLight) Position() tuples.Tuple {
+	const half = 0.5
+	return l.Corner.
+		Add(l.UVec.Multiply(float64(l.USteps) * half)).
+		Add(l.VVec.Multiply(float64(l.VSteps) * half))
+}
+
+// PointOnLight returns the point at offset (du, dv) inside cell (u, v), where
+// (0, 0) is the cell's corner nearest the light's corner and (1, 1) the
+// opposite one.
+func (l AreaLight) PointOnLight(u, v int, du, dv float64) tuples.Tuple {
+	return l.Corner.
+		Add(l.UVec.Multiply(float64(u) + du)).
+		Add(l.VVec.Multiply(float64(v) + dv))
+}
+
+// Samples returns one sample per cell, ordered row by row.
+func (l AreaLight) Samples(point tuples.Tuple) []Sample {
+	const half = 0.5
+	var rng *rand.Rand
+	if l.Jitter {
+		rng = pointRand(point)
+	}
+	samples := make([]Sample, 0, l.USteps*l.VSteps)
+	for v := range l.VSteps {
+		for u := range l.USteps {
+			du, dv := half, half
+			if rng != nil {
+				du, dv = rng.Float64(), rng.Float64()
+			}
+			samples = append(samples, sampleAt(l.PointOnLight(u, v, du, dv), point, l.Intensity))
+		}
+	}
+	return samples
+}
+
+// pointRand returns a random source seeded by the coordinates of point.
+func pointRand(point tuples.Tuple) *rand.Rand {
+	const prime = 1099511628211
+	seed := math.Float64bits(point.X)*prime ^ math.Float64bits(point.Y)
+	return rand.New(rand.NewPCG(seed, math.Float64bits(point.Z))) // #nosec G404 -- jitter needs no cryptographic randomness
+}
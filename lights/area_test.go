@@ -0,0 +1,90 @@
+package lights_test
+
+import (
+	"raytracer-vibe/lights"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatingAreaLight(t *testing.T) {
+	// Scenario: Creating an area light
+	// Given corner ← point(0, 0, 0)
+	// And v1 ← vector(2, 0, 0)
+	// And v2 ← vector(0, 0, 1)
+	// When light ← area_light(corner, v1, 4, v2, 2, color(1, 1, 1))
+	// Then light.corner = corner
+	// And light.uvec = vector(0.5, 0, 0)
+	// And light.usteps = 4
+	// And light.vvec = vector(0, 0, 0.5)
+	// And light.vsteps = 2
+	// And light.samples = 8
+	// And light.position = point(1, 0, 0.5)
+	corner := tuples.Point(0, 0, 0)
+	light := lights.NewAreaLight(corner, tuples.Vector(2, 0, 0), 4, tuples.Vector(0, 0, 1), 2, tuples.NewColor(1, 1, 1))
+	assert.True(t, corner.Equals(light.Corner))
+	assert.True(t, tuples.Vector(0.5, 0, 0).Equals(light.UVec))
+	assert.Equal(t, 4, light.USteps)
+	assert.True(t, tuples.Vector(0, 0, 0.5).Equals(light.VVec))
+	assert.Equal(t, 2, light.VSteps)
+	assert.Len(t, light.Samples(tuples.Point(0, 5, 0)), 8)
+	assert.True(t, tuples.Point(1, 0, 0.5).Equals(light.Position()))
+}
+
+func TestFindingSinglePointOnAreaLight(t *testing.T) {
+	// Scenario Outline: Finding a single point on an area light
+	// Given corner ← point(0, 0, 0)
+	// And v1 ← vector(2, 0, 0)
+	// And v2 ← vector(0, 0, 1)
+	// And light ← area_light(corner, v1, 4, v2, 2, color(1, 1, 1))
+	// When pt ← point_on_light(light, <u>, <v>)
+	// Then pt = <result>
+	light := lights.NewAreaLight(tuples.Point(0, 0, 0),
+		tuples.Vector(2, 0, 0), 4, tuples.Vector(0, 0, 1), 2, tuples.NewColor(1, 1, 1))
+	tests := []struct {
+		u, v     int
+		expected tuples.Tuple
+	}{
+		{0, 0, tuples.Point(0.25, 0, 0.25)},
+		{1, 0, tuples.Point(0.75, 0, 0.25)},
+		{0, 1, tuples.Point(0.25, 0, 0.75)},
+		{2, 0, tuples.Point(1.25, 0, 0.25)},
+		{3, 1, tuples.Point(1.75, 0, 0.75)},
+	}
+	for _, tt := range tests {
+		assert.True(t, tt.expected.Equals(light.PointOnLight(tt.u, tt.v, 0.5, 0.5)), "cell (%d, %d)", tt.u, tt.v)
+	}
+}
+
+func TestAreaLightSamplesCellCenters(t *testing.T) {
+	// Scenario: Without jitter, an area light is sampled at the center of each cell
+	light := lights.NewAreaLight(tuples.Point(-1, 2, 0),
+		tuples.Vector(2, 0, 0), 2, tuples.Vector(0, 0, 2), 2, tuples.NewColor(1, 1, 1))
+	point := tuples.Point(0, 0, 0)
+	samples := light.Samples(point)
+	require.Len(t, samples, 4)
+	for i, s := range samples {
+		expected := light.PointOnLight(i%2, i/2, 0.5, 0.5)
+		assert.True(t, expected.Equals(point.Add(s.Direction.Multiply(s.Distance))))
+	}
+}
+
+func TestJitteredAreaLightSamples(t *testing.T) {
+	// Scenario: Jittered samples stay in their cells and only depend on the lit point
+	light := lights.NewAreaLight(tuples.Point(0, 0, 0),
+		tuples.Vector(2, 0, 0), 4, tuples.Vector(0, 0, 1), 2, tuples.NewColor(1, 1, 1))
+	light.Jitter = true
+	point := tuples.Point(0.3, -4, 0.7)
+	samples := light.Samples(point)
+	require.Len(t, samples, 8)
+	for i, s := range samples {
+		onLight := point.Add(s.Direction.Multiply(s.Distance))
+		assert.InDelta(t, 0.0, onLight.Y, 0.00001)
+		assert.Equal(t, i%4, int(onLight.X/0.5), "sample %d at %v", i, onLight)
+		assert.Equal(t, i/4, int(onLight.Z/0.5), "sample %d at %v", i, onLight)
+	}
+	assert.Equal(t, samples, light.Samples(point))
+	assert.NotEqual(t, samples[0].Direction, light.Samples(tuples.Point(0.3, -4, 0.8))[0].Direction)
+}
@@ -0,0 +1,40 @@
+package lights
+
+import "math"
+
+// Attenuation describes how a light dims with distance d, dividing its
+// intensity by Constant + Linear*d + Quadratic*d². The zero value does not
+// dim the light at all.
+type Attenuation struct {
+	Constant  float64
+	Linear    float64
+	Quadratic float64
+	// Radius, when positive, fades the light smoothly to nothing at this
+	// distance, so that even physically dimmed lights have a finite reach.
+	Radius float64
+}
+
+// InverseSquare returns the physically based falloff, where the light's
+// intensity is its brightness one unit away. A radius of zero never cuts the
+// light off.
+func InverseSquare(radius float64) Attenuation {
+	return Attenuation{Quadratic: 1, Radius: radius}
+}
+
+// Factor returns the fraction of a light's intensity left at distance.
+func (a Attenuation) Factor(distance float64) float64 {
+	// minDenominator keeps lights finite at their own position.
+	const minDenominator = 1e-4
+	factor := 1.0
+	if a.Constant != 0 || a.Linear != 0 || a.Quadratic != 0 {
+		denominator := a.Constant + a.Linear*distance + a.Quadratic*distance*distance
+		factor = 1 / math.Max(denominator, minDenominator)
+	}
+	if a.Radius > 0 {
+		// The window of Karis, "Real Shading in Unreal Engine 4".
+		ratio := distance / a.Radius
+		window := math.Max(0, 1-ratio*ratio*ratio*ratio)
+		factor *= window * window
+	}
+	return factor
+}
@@ -0,0 +1,66 @@
+package lights_test
+
+import (
+	"raytracer-vibe/lights"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZeroAttenuationKeepsFullIntensity(t *testing.T) {
+	// Scenario: Lights are not attenuated by default
+	var a lights.Attenuation
+	for _, d := range []float64{0, 1, 100} {
+		assert.InDelta(t, 1.0, a.Factor(d), 0.00001)
+	}
+}
+
+func TestConstantLinearQuadraticAttenuation(t *testing.T) {
+	// Scenario Outline: Attenuation divides by a polynomial of the distance
+	a := lights.Attenuation{Constant: 1, Linear: 0.5, Quadratic: 0.25}
+	tests := []struct {
+		distance float64
+		expected float64
+	}{
+		{0, 1},
+		{2, 1.0 / 3},
+		{4, 1.0 / 7},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, a.Factor(tt.distance), 0.00001, "distance %v", tt.distance)
+	}
+}
+
+func TestInverseSquareAttenuation(t *testing.T) {
+	// Scenario: Physical attenuation falls off with the square of the distance
+	a := lights.InverseSquare(0)
+	assert.InDelta(t, 1.0, a.Factor(1), 0.00001)
+	assert.InDelta(t, 0.25, a.Factor(2), 0.00001)
+	assert.InDelta(t, 0.01, a.Factor(10), 0.00001)
+	assert.Less(t, a.Factor(0), 1e5)
+}
+
+func TestAttenuationRadiusCutsLightOff(t *testing.T) {
+	// Scenario: A light with a radius fades smoothly to nothing at the radius
+	a := lights.InverseSquare(10)
+	assert.InDelta(t, 0.25*(1-0.0016)*(1-0.0016), a.Factor(2), 0.00001)
+	assert.InDelta(t, 0.0, a.Factor(10), 0.00001)
+	assert.InDelta(t, 0.0, a.Factor(15), 0.00001)
+	previous := a.Factor(1)
+	for d := 1.5; d <= 10; d += 0.5 {
+		assert.Less(t, a.Factor(d), previous)
+		previous = a.Factor(d)
+	}
+}
+
+func TestAttenuatedLightSamples(t *testing.T) {
+	// Scenario: Point and spot light samples are dimmed by attenuation
+	point := lights.NewPointLight(tuples.Point(0, 2, 0), tuples.NewColor(1, 1, 1))
+	point.Attenuation = lights.InverseSquare(0)
+	assert.True(t, tuples.NewColor(0.25, 0.25, 0.25).Equals(point.Samples(tuples.Point(0, 0, 0))[0].Intensity.Tuple))
+
+	spot := lights.NewSpotLight(tuples.Point(0, 2, 0), tuples.Vector(0, -1, 0), 0.5, 0.6, tuples.NewColor(1, 1, 1))
+	spot.Attenuation = lights.Attenuation{Constant: 1, Linear: 1}
+	assert.True(t, tuples.NewColor(1.0/3, 1.0/3, 1.0/3).Equals(spot.Samples(tuples.Point(0, 0, 0))[0].Intensity.Tuple))
+}
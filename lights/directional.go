@@ -0,0 +1,28 @@
+package lights
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// DirectionalLight is a light so far away, like the sun, that its rays are
+// parallel and it is equally bright everywhere.
+type DirectionalLight struct {
+	// Direction is the direction the light travels in.
+	Direction tuples.Tuple
+	Intensity tuples.Color
+}
+
+func NewDirectionalLight(direction tuples.Tuple, intensity tuples.Color) DirectionalLight {
+	return DirectionalLight{Direction: tuples.Normalize(direction), Intensity: intensity}
+}
+
+// Samples returns a single sample against the light's direction, which any
+// object in the way shadows however far it is.
+func (l DirectionalLight) Samples(tuples.Tuple) []Sample {
+	return []Sample{{
+		Direction: tuples.Negate(l.Direction),
+		Distance:  math.Inf(1),
+		Intensity: l.Intensity,
+	}}
+}
@@ -0,0 +1,24 @@
+package lights_test
+
+import (
+	"math"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectionalLightIsSameEverywhere(t *testing.T) {
+	// Scenario: A directional light shines from the same direction at every point
+	light := lights.NewDirectionalLight(tuples.Vector(0, -2, 0), tuples.NewColor(1, 0.9, 0.8))
+	assert.True(t, tuples.Vector(0, -1, 0).Equals(light.Direction))
+	for _, point := range []tuples.Tuple{tuples.Point(0, 0, 0), tuples.Point(100, -50, 3)} {
+		samples := light.Samples(point)
+		require.Len(t, samples, 1)
+		assert.True(t, tuples.Vector(0, 1, 0).Equals(samples[0].Direction))
+		assert.True(t, math.IsInf(samples[0].Distance, 1))
+		assert.Equal(t, light.Intensity, samples[0].Intensity)
+	}
+}
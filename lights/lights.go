@@ -0,0 +1,50 @@
+package lights
+
+import "raytracer-vibe/tuples"
+
+// Light is a source of light. Shading asks a light for its samples as seen
+// from the point being lit and averages their contributions, so lights with
+// several samples cast soft shadows.
+type Light interface {
+	Samples(point tuples.Tuple) []Sample
+}
+
+// Sample is one point of a light, as seen from the point being lit.
+type Sample struct {
+	// Direction is the unit vector from the lit point towards the light.
+	Direction tuples.Tuple
+	// Distance is how far the light is along Direction; objects further away
+	// do not cast shadows.
+	Distance float64
+	// Intensity is the light arriving at the lit point.
+	Intensity tuples.Color
+}
+
+// PointLight is a light source with no size, radiating equally in every direction.
+type PointLight struct {
+	Position    tuples.Tuple
+	Intensity   tuples.Color
+	Attenuation Attenuation
+}
+
+func NewPointLight(position tuples.Tuple, intensity tuples.Color) PointLight {
+	return PointLight{Position: position, Intensity: intensity}
+}
+
+// Samples returns the single sample at the light's position, dimmed by the
+// light's attenuation.
+func (l PointLight) Samples(point tuples.Tuple) []Sample {
+	s := sampleAt(l.Position, point, l.Intensity)
+	s.Intensity = s.Intensity.Multiply(l.Attenuation.Factor(s.Distance))
+	return []Sample{s}
+}
+
+// sampleAt returns the sample of a light at position seen from point.
+func sampleAt(position, point tuples.Tuple, intensity tuples.Color) Sample {
+	v := position.Subtract(point)
+	return Sample{
+		Direction: tuples.Normalize(v),
+		Distance:  tuples.Magnitude(v),
+		Intensity: intensity,
+	}
+}
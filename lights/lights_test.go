@@ -0,0 +1,28 @@
+package lights_test
+
+import (
+	"raytracer-vibe/lights"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointLightHasPositionAndIntensity(t *testing.T) {
+	// Scenario: A point light has a position and intensity
+	intensity := tuples.NewColor(1, 1, 1)
+	position := tuples.Point(0, 0, 0)
+	light := lights.NewPointLight(position, intensity)
+	assert.Equal(t, position, light.Position)
+	assert.Equal(t, intensity, light.Intensity)
+}
+
+func TestPointLightHasSingleSample(t *testing.T) {
+	// Scenario: A point light is sampled at its position
+	light := lights.NewPointLight(tuples.Point(0, 3, 4), tuples.NewColor(1, 1, 1))
+	samples := light.Samples(tuples.Point(0, 0, 0))
+	assert.Len(t, samples, 1)
+	assert.True(t, tuples.Vector(0, 0.6, 0.8).Equals(samples[0].Direction))
+	assert.InDelta(t, 5.0, samples[0].Distance, 0.00001)
+	assert.Equal(t, light.Intensity, samples[0].Intensity)
+}
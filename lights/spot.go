@@ -0,0 +1,58 @@
+package lights
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// SpotLight is a point light that only shines within a cone. It is at full
+// intensity inside the inner cone and fades smoothly to nothing at the outer
+// cone.
+type SpotLight struct {
+	Position tuples.Tuple
+	// Direction is the axis of the cone, pointing away from the light.
+	Direction tuples.Tuple
+	// InnerAngle and OuterAngle are the angles between the axis and the
+	// edges of the cones, in radians.
+	InnerAngle  float64
+	OuterAngle  float64
+	Intensity   tuples.Color
+	Attenuation Attenuation
+}
+
+func NewSpotLight(position, direction tuples.Tuple, innerAngle, outerAngle float64, intensity tuples.Color) SpotLight {
+	return SpotLight{
+		Position:   position,
+		Direction:  tuples.Normalize(direction),
+		InnerAngle: innerAngle,
+		OuterAngle: outerAngle,
+		Intensity:  intensity,
+	}
+}
+
+// Samples returns the single sample at the light's position, dimmed by how
+// far point is from the cone's axis and by the light's attenuation.
+func (l SpotLight) Samples(point tuples.Tuple) []Sample {
+	s := sampleAt(l.Position, point, l.Intensity)
+	s.Intensity = s.Intensity.Multiply(l.Falloff(point) * l.Attenuation.Factor(s.Distance))
+	return []Sample{s}
+}
+
+// Falloff returns the fraction of the light's intensity reaching point: 1
+// inside the inner cone, 0 outside the outer cone and a smooth blend between.
+func (l SpotLight) Falloff(point tuples.Tuple) float64 {
+	cosAngle := tuples.Normalize(point.Subtract(l.Position)).Dot(l.Direction)
+	return smoothstep(math.Cos(l.OuterAngle), math.Cos(l.InnerAngle), cosAngle)
+}
+
+// smoothstep rises smoothly from 0 at edge0 to 1 at edge1.
+func smoothstep(edge0, edge1, x float64) float64 {
+	if edge0 >= edge1 {
+		if x < edge1 {
+			return 0
+		}
+		return 1
+	}
+	t := math.Max(0, math.Min(1, (x-edge0)/(edge1-edge0)))
+	return t * t * (3 - 2*t) // nolint: mnd // the smoothstep polynomial
+}
@@ -0,0 +1,72 @@
+package lights_test
+
+import (
+	"math"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpotLightFalloff(t *testing.T) {
+	// Scenario Outline: A spot light is full inside its inner cone and dark outside its outer cone
+	// Given light ← spot_light(point(0, 10, 0), vector(0, -1, 0), π/8, π/4, color(1, 1, 1))
+	// Then falloff(light, <point>) = <result>
+	light := lights.NewSpotLight(tuples.Point(0, 10, 0), tuples.Vector(0, -1, 0),
+		math.Pi/8, math.Pi/4, tuples.NewColor(1, 1, 1))
+	tests := []struct {
+		name     string
+		point    tuples.Tuple
+		expected float64
+	}{
+		{"on the axis", tuples.Point(0, 0, 0), 1},
+		{"inside the inner cone", tuples.Point(3, 0, 0), 1},
+		{"outside the outer cone", tuples.Point(11, 0, 0), 0},
+		{"behind the light", tuples.Point(0, 20, 0), 0},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, light.Falloff(tt.point), 0.00001, tt.name)
+	}
+
+	// Halfway between the cones the smooth blend is exactly one half.
+	halfway := (math.Cos(math.Pi/8) + math.Cos(math.Pi/4)) / 2
+	angle := math.Acos(halfway)
+	assert.InDelta(t, 0.5, light.Falloff(tuples.Point(10*math.Tan(angle), 0, 0)), 0.00001)
+}
+
+func TestSpotLightFalloffDecreasesAwayFromAxis(t *testing.T) {
+	// Scenario: A spot light fades monotonically between its cones
+	light := lights.NewSpotLight(tuples.Point(0, 10, 0), tuples.Vector(0, -1, 0),
+		math.Pi/8, math.Pi/4, tuples.NewColor(1, 1, 1))
+	previous := 1.0
+	for x := 4.0; x <= 10; x += 0.5 {
+		f := light.Falloff(tuples.Point(x, 0, 0))
+		assert.LessOrEqual(t, f, previous)
+		previous = f
+	}
+}
+
+func TestSpotLightSampleIsDimmedByFalloff(t *testing.T) {
+	// Scenario: A spot light sample points at the light and carries the dimmed intensity
+	light := lights.NewSpotLight(tuples.Point(0, 10, 0), tuples.Vector(0, -1, 0),
+		math.Pi/8, math.Pi/4, tuples.NewColor(1, 1, 1))
+	point := tuples.Point(7, 0, 0)
+	samples := light.Samples(point)
+	require.Len(t, samples, 1)
+	assert.True(t, tuples.Normalize(tuples.Vector(-7, 10, 0)).Equals(samples[0].Direction))
+	assert.InDelta(t, math.Sqrt(149), samples[0].Distance, 0.00001)
+	f := light.Falloff(point)
+	assert.Greater(t, f, 0.0)
+	assert.Less(t, f, 1.0)
+	assert.True(t, tuples.NewColor(f, f, f).Equals(samples[0].Intensity.Tuple))
+}
+
+func TestSpotLightWithHardEdge(t *testing.T) {
+	// Scenario: A spot light whose cones are equal has a hard edge
+	light := lights.NewSpotLight(tuples.Point(0, 10, 0), tuples.Vector(0, -1, 0),
+		math.Pi/4, math.Pi/4, tuples.NewColor(1, 1, 1))
+	assert.InDelta(t, 1.0, light.Falloff(tuples.Point(9.9, 0, 0)), 0.00001)
+	assert.InDelta(t, 0.0, light.Falloff(tuples.Point(10.1, 0, 0)), 0.00001)
+}
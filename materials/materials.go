@@ -0,0 +1,123 @@
+package materials
+
+import (
+	"math"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/bumps"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/media"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+)
+
+const (
+	DefaultAmbient   = 0.1
+	DefaultDiffuse   = 0.9
+	DefaultSpecular  = 0.9
+	DefaultShininess = 200.0
+)
+
+// Material holds the Phong reflection attributes of a surface.
+type Material struct {
+	// Name is the name the material was defined with in a scene, if any.
+	// Objects sharing a name share a color in the material ID pass.
+	Name  string
+	Color tuples.Color
+	// Pattern, when set, replaces Color with a color that varies over the
+	// surface.
+	Pattern patterns.Pattern
+	// Bump, when set, tilts the surface's normal to give it fine detail.
+	Bump      bumps.Bump
+	Ambient   float64
+	Diffuse   float64
+	Specular  float64
+	Shininess float64
+	// Reflective is the fraction of light mirrored off the surface, from 0
+	// for a matte surface to 1 for a perfect mirror.
+	Reflective float64
+	// Emission is the color of the light the surface gives off by itself,
+	// which the path tracer also lets light the surfaces around it. Black
+	// surfaces give off none.
+	Emission tuples.Color
+	// EmissionStrength scales Emission, so that a glow can be brightened
+	// without changing its color.
+	EmissionStrength float64
+	// BSDF, when set, shades the surface physically instead of with the
+	// Phong model, ignoring the Phong attributes and Reflective. Color still
+	// tints it.
+	BSDF bsdfs.BSDF
+	// Medium, when set, fills the object with smoke or murky water and
+	// makes its surface invisible, so that it only bounds the medium.
+	Medium *media.Homogeneous
+}
+
+func NewMaterial() Material {
+	return Material{
+		Color:            tuples.NewColor(1, 1, 1),
+		Ambient:          DefaultAmbient,
+		Diffuse:          DefaultDiffuse,
+		Specular:         DefaultSpecular,
+		Shininess:        DefaultShininess,
+		Emission:         tuples.NewColor(0, 0, 0),
+		EmissionStrength: 1,
+	}
+}
+
+// Emitted returns the light the surface gives off: its Emission scaled by its
+// EmissionStrength.
+func (m Material) Emitted() tuples.Color {
+	return m.Emission.Multiply(m.EmissionStrength)
+}
+
+// ColorAt returns the color of the material at a point in the space of the
+// object it is applied to.
+func (m Material) ColorAt(objectPoint tuples.Tuple) tuples.Color {
+	if m.Pattern == nil {
+		return m.Color
+	}
+	return patterns.AtObject(m.Pattern, objectPoint)
+}
+
+// FilteredColorAt returns the color of the material at a point in object
+// space, averaged over the area of the surface spanned by the object space
+// vectors dx and dy where the pattern supports it.
+func (m Material) FilteredColorAt(objectPoint, dx, dy tuples.Tuple) tuples.Color {
+	if m.Pattern == nil {
+		return m.Color
+	}
+	return patterns.AtObjectFiltered(m.Pattern, objectPoint, dx, dy)
+}
+
+// Lighting computes the color of the material at point using the Phong
+// reflection model, averaged over the light's samples. Intensity is the
+// fraction of the light reaching point, from 0 in full shadow, which only
+// receives the ambient term, to 1 in full light.
+func (m Material) Lighting(light lights.Light, point, eyev, normalv tuples.Tuple, intensity float64) tuples.Color {
+	samples := light.Samples(point)
+	ambient := tuples.NewColor(0, 0, 0)
+	lit := tuples.NewColor(0, 0, 0)
+	for _, s := range samples {
+		effectiveColor := m.Color.Hadamard(s.Intensity)
+		ambient = ambient.Add(effectiveColor.Multiply(m.Ambient))
+		lit = lit.Add(m.diffuseAndSpecular(s, effectiveColor, eyev, normalv))
+	}
+	share := 1 / float64(len(samples))
+	return ambient.Multiply(share).Add(lit.Multiply(share * intensity))
+}
+
+// diffuseAndSpecular returns the light of a single sample reflected towards
+// the eye.
+func (m Material) diffuseAndSpecular(s lights.Sample, effectiveColor tuples.Color, eyev, normalv tuples.Tuple) tuples.Color {
+	lightDotNormal := s.Direction.Dot(normalv)
+	if lightDotNormal < 0 {
+		return tuples.NewColor(0, 0, 0)
+	}
+	diffuse := effectiveColor.Multiply(m.Diffuse * lightDotNormal)
+	reflectv := tuples.Reflect(tuples.Negate(s.Direction), normalv)
+	reflectDotEye := reflectv.Dot(eyev)
+	if reflectDotEye <= 0 {
+		return diffuse
+	}
+	factor := math.Pow(reflectDotEye, m.Shininess)
+	return diffuse.Add(s.Intensity.Multiply(m.Specular * factor))
+}
@@ -0,0 +1,148 @@
+package materials_test
+
+import (
+	"math"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultMaterial(t *testing.T) {
+	// Scenario: The default material
+	m := materials.NewMaterial()
+	assert.True(t, tuples.NewColor(1, 1, 1).Equals(m.Color.Tuple))
+	assert.InEpsilon(t, 0.1, m.Ambient, 0.00001)
+	assert.InEpsilon(t, 0.9, m.Diffuse, 0.00001)
+	assert.InEpsilon(t, 0.9, m.Specular, 0.00001)
+	assert.InEpsilon(t, 200.0, m.Shininess, 0.00001)
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(m.Emitted().Tuple))
+}
+
+func TestEmittedLight(t *testing.T) {
+	// Scenario: A material's emission is scaled by its strength
+	// Given m ← material() with emission color(1, 0.5, 0) and strength 3
+	// Then emitted(m) = color(3, 1.5, 0)
+	m := materials.NewMaterial()
+	m.Emission = tuples.NewColor(1, 0.5, 0)
+	m.EmissionStrength = 3
+	assert.True(t, tuples.NewColor(3, 1.5, 0).Equals(m.Emitted().Tuple))
+}
+
+func TestLighting(t *testing.T) {
+	m := materials.NewMaterial()
+	position := tuples.Point(0, 0, 0)
+	val := math.Sqrt(2) / 2
+
+	t.Run("Lighting with the eye between the light and the surface", func(t *testing.T) {
+		// Scenario: Lighting with the eye between the light and the surface
+		eyev := tuples.Vector(0, 0, -1)
+		normalv := tuples.Vector(0, 0, -1)
+		light := lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))
+		result := m.Lighting(light, position, eyev, normalv, 1.0)
+		assert.True(t, tuples.NewColor(1.9, 1.9, 1.9).Equals(result.Tuple))
+	})
+
+	t.Run("Lighting with the eye between light and surface, eye offset 45°", func(t *testing.T) {
+		// Scenario: Lighting with the eye between light and surface, eye offset 45°
+		eyev := tuples.Vector(0, val, -val)
+		normalv := tuples.Vector(0, 0, -1)
+		light := lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))
+		result := m.Lighting(light, position, eyev, normalv, 1.0)
+		assert.True(t, tuples.NewColor(1.0, 1.0, 1.0).Equals(result.Tuple))
+	})
+
+	t.Run("Lighting with eye opposite surface, light offset 45°", func(t *testing.T) {
+		// Scenario: Lighting with eye opposite surface, light offset 45°
+		eyev := tuples.Vector(0, 0, -1)
+		normalv := tuples.Vector(0, 0, -1)
+		light := lights.NewPointLight(tuples.Point(0, 10, -10), tuples.NewColor(1, 1, 1))
+		result := m.Lighting(light, position, eyev, normalv, 1.0)
+		assert.True(t, tuples.NewColor(0.7364, 0.7364, 0.7364).Equals(result.Tuple))
+	})
+
+	t.Run("Lighting with eye in the path of the reflection vector", func(t *testing.T) {
+		// Scenario: Lighting with eye in the path of the reflection vector
+		eyev := tuples.Vector(0, -val, -val)
+		normalv := tuples.Vector(0, 0, -1)
+		light := lights.NewPointLight(tuples.Point(0, 10, -10), tuples.NewColor(1, 1, 1))
+		result := m.Lighting(light, position, eyev, normalv, 1.0)
+		assert.True(t, tuples.NewColor(1.6364, 1.6364, 1.6364).Equals(result.Tuple))
+	})
+
+	t.Run("Lighting with the light behind the surface", func(t *testing.T) {
+		// Scenario: Lighting with the light behind the surface
+		eyev := tuples.Vector(0, 0, -1)
+		normalv := tuples.Vector(0, 0, -1)
+		light := lights.NewPointLight(tuples.Point(0, 0, 10), tuples.NewColor(1, 1, 1))
+		result := m.Lighting(light, position, eyev, normalv, 1.0)
+		assert.True(t, tuples.NewColor(0.1, 0.1, 0.1).Equals(result.Tuple))
+	})
+
+	t.Run("Lighting with the surface in shadow", func(t *testing.T) {
+		// Scenario: Lighting with the surface in shadow
+		eyev := tuples.Vector(0, 0, -1)
+		normalv := tuples.Vector(0, 0, -1)
+		light := lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))
+		result := m.Lighting(light, position, eyev, normalv, 0.0)
+		assert.True(t, tuples.NewColor(0.1, 0.1, 0.1).Equals(result.Tuple))
+	})
+}
+
+func TestLightingUsesLightIntensityToAttenuateColor(t *testing.T) {
+	// Scenario: lighting() uses light intensity to attenuate color
+	// Given m.ambient ← 0.1, m.diffuse ← 0.9, m.specular ← 0, m.color ← color(1, 1, 1)
+	// And light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	// And pt ← point(0, 0, -1), eyev ← vector(0, 0, -1), normalv ← vector(0, 0, -1)
+	// When result ← lighting(m, light, pt, eyev, normalv, intensity)
+	// Then result = <result>
+	m := materials.NewMaterial()
+	m.Specular = 0
+	light := lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))
+	pt := tuples.Point(0, 0, -1)
+	eyev := tuples.Vector(0, 0, -1)
+	normalv := tuples.Vector(0, 0, -1)
+	tests := []struct {
+		intensity float64
+		expected  tuples.Color
+	}{
+		{1.0, tuples.NewColor(1, 1, 1)},
+		{0.5, tuples.NewColor(0.55, 0.55, 0.55)},
+		{0.0, tuples.NewColor(0.1, 0.1, 0.1)},
+	}
+	for _, tt := range tests {
+		result := m.Lighting(light, pt, eyev, normalv, tt.intensity)
+		assert.True(t, tt.expected.Equals(result.Tuple), "intensity %v: got %v", tt.intensity, result)
+	}
+}
+
+func TestLightingSamplesAreaLight(t *testing.T) {
+	// Scenario Outline: lighting() samples the area light
+	// Given corner ← point(-0.5, -0.5, -5)
+	// And light ← area_light(corner, vector(1, 0, 0), 2, vector(0, 1, 0), 2, color(1, 1, 1))
+	// And m.ambient ← 0.1, m.diffuse ← 0.9, m.specular ← 0, m.color ← color(1, 1, 1)
+	// And eye ← point(0, 0, -5)
+	// And pt ← <point> on the unit sphere
+	// When result ← lighting(m, light, pt, normalize(eye - pt), normal_at(sphere, pt), 1.0)
+	// Then result = <result>
+	light := lights.NewAreaLight(tuples.Point(-0.5, -0.5, -5),
+		tuples.Vector(1, 0, 0), 2, tuples.Vector(0, 1, 0), 2, tuples.NewColor(1, 1, 1))
+	m := materials.NewMaterial()
+	m.Specular = 0
+	eye := tuples.Point(0, 0, -5)
+	tests := []struct {
+		point    tuples.Tuple
+		expected tuples.Color
+	}{
+		{tuples.Point(0, 0, -1), tuples.NewColor(0.9965, 0.9965, 0.9965)},
+		{tuples.Point(0, 0.7071, -0.7071), tuples.NewColor(0.62318, 0.62318, 0.62318)},
+	}
+	for _, tt := range tests {
+		eyev := tuples.Normalize(eye.Subtract(tt.point))
+		normalv := tuples.Vector(tt.point.X, tt.point.Y, tt.point.Z)
+		result := m.Lighting(light, tt.point, eyev, normalv, 1.0)
+		assert.True(t, tt.expected.Equals(result.Tuple), "point %v: got %v", tt.point, result)
+	}
+}
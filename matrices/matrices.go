@@ -3,21 +3,31 @@ package matrices
 import (
 	"math"
 	"raytracer-vibe/tuples"
+	"sync"
 )
 
 const epsilon = 0.00001
 const DefaultMatrixSize = 4
 
+// Matrix is immutable once constructed, which lets copies share the lazily
+// computed inverse.
 type Matrix struct {
 	rows, cols int
 	data       [][]float64
+	inverse    *inverseCache
+}
+
+type inverseCache struct {
+	once   sync.Once
+	matrix Matrix
 }
 
 func New(rows, cols int, data ...float64) Matrix {
 	m := Matrix{
-		rows: rows,
-		cols: cols,
-		data: make([][]float64, rows),
+		rows:    rows,
+		cols:    cols,
+		data:    make([][]float64, rows),
+		inverse: &inverseCache{},
 	}
 	for i := range rows {
 		m.data[i] = make([]float64, cols)
@@ -139,7 +149,22 @@ func (m Matrix) IsInvertible() bool {
 	return m.Determinant() != 0
 }
 
+// Inverse returns the inverse of the matrix. It is computed once per matrix
+// and reused, since shapes and cameras invert their transform for every ray.
 func (m Matrix) Inverse() Matrix {
+	if m.inverse == nil {
+		return m.computeInverse()
+	}
+	m.inverse.once.Do(func() {
+		m.inverse.matrix = m.computeInverse()
+	})
+	return m.inverse.matrix
+}
+
+func (m Matrix) computeInverse() Matrix {
+	if m.rows == DefaultMatrixSize && m.cols == DefaultMatrixSize {
+		return m.inverse4()
+	}
 	if !m.IsInvertible() {
 		panic("matrix not invertible")
 	}
@@ -154,6 +179,49 @@ func (m Matrix) Inverse() Matrix {
 	return m2
 }
 
+// inverse4 inverts a 4x4 matrix from the determinants of its 2x2 corners,
+// which gives the same result as the cofactors without building a
+// submatrix for each of them. Moving shapes need a new inverse for every
+// ray, so this matters.
+func (m Matrix) inverse4() Matrix {
+	a := m.data
+	s0 := a[0][0]*a[1][1] - a[1][0]*a[0][1]
+	s1 := a[0][0]*a[1][2] - a[1][0]*a[0][2]
+	s2 := a[0][0]*a[1][3] - a[1][0]*a[0][3]
+	s3 := a[0][1]*a[1][2] - a[1][1]*a[0][2]
+	s4 := a[0][1]*a[1][3] - a[1][1]*a[0][3]
+	s5 := a[0][2]*a[1][3] - a[1][2]*a[0][3]
+	c5 := a[2][2]*a[3][3] - a[3][2]*a[2][3]
+	c4 := a[2][1]*a[3][3] - a[3][1]*a[2][3]
+	c3 := a[2][1]*a[3][2] - a[3][1]*a[2][2]
+	c2 := a[2][0]*a[3][3] - a[3][0]*a[2][3]
+	c1 := a[2][0]*a[3][2] - a[3][0]*a[2][2]
+	c0 := a[2][0]*a[3][1] - a[3][0]*a[2][1]
+	det := s0*c5 - s1*c4 + s2*c3 + s3*c2 - s4*c1 + s5*c0
+	if det == 0 {
+		panic("matrix not invertible")
+	}
+	inv := 1 / det
+	return New(DefaultMatrixSize, DefaultMatrixSize,
+		(a[1][1]*c5-a[1][2]*c4+a[1][3]*c3)*inv,
+		(-a[0][1]*c5+a[0][2]*c4-a[0][3]*c3)*inv,
+		(a[3][1]*s5-a[3][2]*s4+a[3][3]*s3)*inv,
+		(-a[2][1]*s5+a[2][2]*s4-a[2][3]*s3)*inv,
+		(-a[1][0]*c5+a[1][2]*c2-a[1][3]*c1)*inv,
+		(a[0][0]*c5-a[0][2]*c2+a[0][3]*c1)*inv,
+		(-a[3][0]*s5+a[3][2]*s2-a[3][3]*s1)*inv,
+		(a[2][0]*s5-a[2][2]*s2+a[2][3]*s1)*inv,
+		(a[1][0]*c4-a[1][1]*c2+a[1][3]*c0)*inv,
+		(-a[0][0]*c4+a[0][1]*c2-a[0][3]*c0)*inv,
+		(a[3][0]*s4-a[3][1]*s2+a[3][3]*s0)*inv,
+		(-a[2][0]*s4+a[2][1]*s2-a[2][3]*s0)*inv,
+		(-a[1][0]*c3+a[1][1]*c1-a[1][2]*c0)*inv,
+		(a[0][0]*c3-a[0][1]*c1+a[0][2]*c0)*inv,
+		(-a[3][0]*s3+a[3][1]*s1-a[3][2]*s0)*inv,
+		(a[2][0]*s3-a[2][1]*s1+a[2][2]*s0)*inv,
+	)
+}
+
 func Translation(x, y, z float64) Matrix {
 	m := Identity(DefaultMatrixSize)
 	m.data[0][3] = x
@@ -207,3 +275,18 @@ func Shearing(xy, xz, yx, yz, zx, zy float64) Matrix {
 	m.data[2][1] = zy
 	return m
 }
+
+// ViewTransform orients the world relative to an eye at from, looking at to,
+// with up pointing roughly upwards.
+func ViewTransform(from, to, up tuples.Tuple) Matrix {
+	forward := tuples.Normalize(to.Subtract(from))
+	left := tuples.Cross(forward, tuples.Normalize(up))
+	trueUp := tuples.Cross(left, forward)
+	orientation := New(DefaultMatrixSize, DefaultMatrixSize,
+		left.X, left.Y, left.Z, 0,
+		trueUp.X, trueUp.Y, trueUp.Z, 0,
+		-forward.X, -forward.Y, -forward.Z, 0,
+		0, 0, 0, 1,
+	)
+	return orientation.Multiply(Translation(-from.X, -from.Y, -from.Z))
+}
@@ -669,3 +669,78 @@ func TestChainedTransformations(t *testing.T) {
 	transform := c.Multiply(b).Multiply(a)
 	assert.True(t, transform.MultiplyTuple(p).Equals(tuples.Point(15, 0, 7)))
 }
+
+// Scenario: The transformation matrix for the default orientation
+// Given from ← point(0, 0, 0)
+// And to ← point(0, 0, -1)
+// And up ← vector(0, 1, 0)
+// When t ← view_transform(from, to, up)
+// Then t = identity_matrix.
+func TestViewTransformDefaultOrientation(t *testing.T) {
+	from := tuples.Point(0, 0, 0)
+	to := tuples.Point(0, 0, -1)
+	up := tuples.Vector(0, 1, 0)
+	assert.True(t, matrices.ViewTransform(from, to, up).Equals(matrices.Identity(4)))
+}
+
+// Scenario: A view transformation matrix looking in positive z direction
+// Given from ← point(0, 0, 0)
+// And to ← point(0, 0, 1)
+// And up ← vector(0, 1, 0)
+// When t ← view_transform(from, to, up)
+// Then t = scaling(-1, 1, -1).
+func TestViewTransformLookingInPositiveZ(t *testing.T) {
+	from := tuples.Point(0, 0, 0)
+	to := tuples.Point(0, 0, 1)
+	up := tuples.Vector(0, 1, 0)
+	assert.True(t, matrices.ViewTransform(from, to, up).Equals(matrices.Scaling(-1, 1, -1)))
+}
+
+// Scenario: The view transformation moves the world
+// Given from ← point(0, 0, 8)
+// And to ← point(0, 0, 0)
+// And up ← vector(0, 1, 0)
+// When t ← view_transform(from, to, up)
+// Then t = translation(0, 0, -8).
+func TestViewTransformMovesTheWorld(t *testing.T) {
+	from := tuples.Point(0, 0, 8)
+	to := tuples.Point(0, 0, 0)
+	up := tuples.Vector(0, 1, 0)
+	assert.True(t, matrices.ViewTransform(from, to, up).Equals(matrices.Translation(0, 0, -8)))
+}
+
+// Scenario: An arbitrary view transformation
+// Given from ← point(1, 3, 2)
+// And to ← point(4, -2, 8)
+// And up ← vector(1, 1, 0)
+// When t ← view_transform(from, to, up)
+// Then t is the following 4x4 matrix:
+// | -0.50709 | 0.50709 |  0.67612 | -2.36643 |
+// |  0.76772 | 0.60609 |  0.12122 | -2.82843 |
+// | -0.35857 | 0.59761 | -0.71714 |  0.00000 |
+// |  0.00000 | 0.00000 |  0.00000 |  1.00000 |.
+func TestArbitraryViewTransform(t *testing.T) {
+	from := tuples.Point(1, 3, 2)
+	to := tuples.Point(4, -2, 8)
+	up := tuples.Vector(1, 1, 0)
+	expected := matrices.New(4, 4,
+		-0.50709, 0.50709, 0.67612, -2.36643,
+		0.76772, 0.60609, 0.12122, -2.82843,
+		-0.35857, 0.59761, -0.71714, 0.00000,
+		0.00000, 0.00000, 0.00000, 1.00000,
+	)
+	assert.True(t, matrices.ViewTransform(from, to, up).Equals(expected))
+}
+
+// Scenario: The inverse of a matrix is reused by its copies
+// Given A ← translation(1, 2, 3)
+// And B ← A
+// Then inverse(B) = inverse(A)
+// And inverse(inverse(A)) = A.
+func TestInverseIsSharedByCopies(t *testing.T) {
+	a := matrices.Translation(1, 2, 3)
+	b := a
+	assert.True(t, b.Inverse().Equals(a.Inverse()))
+	assert.True(t, a.Inverse().Equals(matrices.Translation(-1, -2, -3)))
+	assert.True(t, a.Inverse().Inverse().Equals(a))
+}
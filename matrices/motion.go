@@ -0,0 +1,95 @@
+package matrices
+
+import "math"
+
+// Motion moves between two transforms over time, from Start at time 0 to End
+// at time 1. Each transform is split into a translation, a rotation and a
+// scale, which are interpolated separately so that turning objects keep
+// their shape instead of shrinking through the blend of two matrices.
+type Motion struct {
+	Start, End Matrix
+	start, end decomposition
+}
+
+// decomposition is a transform split as translation * rotation * scale,
+// where the scale may include shearing.
+type decomposition struct {
+	translation [3]float64
+	rotation    Quaternion
+	scale       Matrix
+}
+
+func NewMotion(start, end Matrix) *Motion {
+	return &Motion{Start: start, End: end, start: decompose(start), end: decompose(end)}
+}
+
+// At returns the transform at time, which is clamped to [0, 1].
+func (m *Motion) At(time float64) Matrix {
+	t := min(max(time, 0), 1)
+	switch t {
+	case 0:
+		return m.Start
+	case 1:
+		return m.End
+	}
+	a, b := m.start, m.end
+	translation := Translation(
+		lerp(a.translation[0], b.translation[0], t),
+		lerp(a.translation[1], b.translation[1], t),
+		lerp(a.translation[2], b.translation[2], t))
+	scale := New(DefaultMatrixSize, DefaultMatrixSize)
+	for i := range DefaultMatrixSize {
+		for j := range DefaultMatrixSize {
+			scale.data[i][j] = lerp(a.scale.data[i][j], b.scale.data[i][j], t)
+		}
+	}
+	return translation.Multiply(Slerp(a.rotation, b.rotation, t).Matrix()).Multiply(scale)
+}
+
+// decompose splits the affine transform m into its translation, rotation
+// and scale, finding the rotation by polar decomposition: repeatedly
+// averaging a matrix with its inverse transpose converges on the nearest
+// rotation.
+func decompose(m Matrix) decomposition {
+	const (
+		half          = 0.5
+		maxIterations = 100
+		tolerance     = 1e-9
+	)
+	var d decomposition
+	linear := Identity(DefaultMatrixSize)
+	for i := range 3 {
+		d.translation[i] = m.data[i][3]
+		for j := range 3 {
+			linear.data[i][j] = m.data[i][j]
+		}
+	}
+	rotation := linear
+	for range maxIterations {
+		inverseTranspose := rotation.Transpose().Inverse()
+		next := New(DefaultMatrixSize, DefaultMatrixSize)
+		change := 0.0
+		for i := range DefaultMatrixSize {
+			for j := range DefaultMatrixSize {
+				next.data[i][j] = half * (rotation.data[i][j] + inverseTranspose.data[i][j])
+				change = max(change, math.Abs(next.data[i][j]-rotation.data[i][j]))
+			}
+		}
+		rotation = next
+		if change < tolerance {
+			break
+		}
+	}
+	if rotation.Determinant() < 0 {
+		// A mirroring transform has no rotation to match, so the mirror
+		// is moved into the scale.
+		rotation = Scaling(-1, -1, -1).Multiply(rotation)
+	}
+	d.rotation = QuaternionFromMatrix(rotation)
+	d.scale = rotation.Inverse().Multiply(linear)
+	return d
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + t*(b-a)
+}
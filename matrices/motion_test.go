@@ -0,0 +1,86 @@
+package matrices_test
+
+import (
+	"math"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuaternionRoundTrip(t *testing.T) {
+	// Scenario Outline: A rotation matrix survives conversion to a quaternion and back
+	// Given q ← quaternion_from_matrix(<rotation>)
+	// Then matrix(q) = <rotation>
+	tests := []matrices.Matrix{
+		matrices.Identity(4),
+		matrices.RotationX(math.Pi / 3),
+		matrices.RotationY(-2),
+		matrices.RotationZ(math.Pi),
+		matrices.RotationX(3).Multiply(matrices.RotationY(1)).Multiply(matrices.RotationZ(-0.5)),
+	}
+	for _, rotation := range tests {
+		assert.True(t, rotation.Equals(matrices.QuaternionFromMatrix(rotation).Matrix()))
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	// Scenario: Slerp turns at constant speed between two rotations
+	// Given a ← quaternion_from_matrix(identity)
+	// And b ← quaternion_from_matrix(rotation_y(π/2))
+	// Then slerp(a, b, 0) = a and slerp(a, b, 1) = b
+	// And matrix(slerp(a, b, 0.25)) = rotation_y(π/8)
+	a := matrices.QuaternionFromMatrix(matrices.Identity(4))
+	b := matrices.QuaternionFromMatrix(matrices.RotationY(math.Pi / 2))
+	assert.True(t, matrices.Identity(4).Equals(matrices.Slerp(a, b, 0).Matrix()))
+	assert.True(t, matrices.RotationY(math.Pi/2).Equals(matrices.Slerp(a, b, 1).Matrix()))
+	assert.True(t, matrices.RotationY(math.Pi/8).Equals(matrices.Slerp(a, b, 0.25).Matrix()))
+}
+
+func TestSlerpTakesShortestPath(t *testing.T) {
+	// Scenario: Slerp turns the short way round
+	// Given a ← quaternion_from_matrix(rotation_z(-3π/4))
+	// And b ← quaternion_from_matrix(rotation_z(3π/4))
+	// Then matrix(slerp(a, b, 0.5)) = rotation_z(π)
+	a := matrices.QuaternionFromMatrix(matrices.RotationZ(-3 * math.Pi / 4))
+	b := matrices.QuaternionFromMatrix(matrices.RotationZ(3 * math.Pi / 4))
+	assert.True(t, matrices.RotationZ(math.Pi).Equals(matrices.Slerp(a, b, 0.5).Matrix()))
+}
+
+func TestMotionAtEnds(t *testing.T) {
+	// Scenario: A motion starts and ends at its transforms, and stays there outside [0, 1]
+	start := matrices.Translation(1, 2, 3)
+	end := matrices.RotationX(1).Multiply(matrices.Scaling(2, 2, 2))
+	m := matrices.NewMotion(start, end)
+	assert.True(t, start.Equals(m.At(0)))
+	assert.True(t, start.Equals(m.At(-1)))
+	assert.True(t, end.Equals(m.At(1)))
+	assert.True(t, end.Equals(m.At(2)))
+}
+
+func TestMotionInterpolatesParts(t *testing.T) {
+	// Scenario: A motion interpolates translation, rotation and scale separately
+	// Given m ← motion(identity, translation(2, 0, 0) * rotation_y(π/2) * scaling(3, 1, 1))
+	// Then at(m, 0.5) = translation(1, 0, 0) * rotation_y(π/4) * scaling(2, 1, 1)
+	m := matrices.NewMotion(matrices.Identity(4),
+		matrices.Translation(2, 0, 0).Multiply(matrices.RotationY(math.Pi/2)).Multiply(matrices.Scaling(3, 1, 1)))
+	expected := matrices.Translation(1, 0, 0).Multiply(matrices.RotationY(math.Pi / 4)).Multiply(matrices.Scaling(2, 1, 1))
+	assert.True(t, expected.Equals(m.At(0.5)))
+}
+
+func TestMotionKeepsTurningObjectsWhole(t *testing.T) {
+	// Scenario: Points of a turning object stay the same distance from its center
+	// Given m ← motion(identity, rotation_z(π))
+	// Then at(m, 0.5) * point(1, 0, 0) = point(0, 1, 0), not the origin a blended matrix would give
+	m := matrices.NewMotion(matrices.Identity(4), matrices.RotationZ(math.Pi))
+	assert.True(t, tuples.Point(0, 1, 0).Equals(m.At(0.5).MultiplyTuple(tuples.Point(1, 0, 0))))
+}
+
+func TestMotionWithMirroring(t *testing.T) {
+	// Scenario: A mirrored object keeps its mirroring while it moves
+	// Given m ← motion(scaling(-1, 1, 1), scaling(-3, 1, 1))
+	// Then at(m, 0.5) = scaling(-2, 1, 1)
+	m := matrices.NewMotion(matrices.Scaling(-1, 1, 1), matrices.Scaling(-3, 1, 1))
+	assert.True(t, matrices.Scaling(-2, 1, 1).Equals(m.At(0.5)))
+}
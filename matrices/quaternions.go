@@ -0,0 +1,80 @@
+package matrices
+
+import "math"
+
+// Quaternion is a rotation stored as a unit quaternion, which can be
+// interpolated smoothly between two orientations.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// QuaternionFromMatrix returns the rotation of the upper left 3x3 part of m,
+// which must be a rotation matrix.
+func QuaternionFromMatrix(m Matrix) Quaternion {
+	const quarter, half = 0.25, 0.5
+	d := m.data
+	trace := d[0][0] + d[1][1] + d[2][2]
+	var q Quaternion
+	switch {
+	case trace > 0:
+		s := half / math.Sqrt(trace+1)
+		q = Quaternion{quarter / s, (d[2][1] - d[1][2]) * s, (d[0][2] - d[2][0]) * s, (d[1][0] - d[0][1]) * s}
+	case d[0][0] > d[1][1] && d[0][0] > d[2][2]:
+		s := 2 * math.Sqrt(1+d[0][0]-d[1][1]-d[2][2])
+		q = Quaternion{(d[2][1] - d[1][2]) / s, quarter * s, (d[0][1] + d[1][0]) / s, (d[0][2] + d[2][0]) / s}
+	case d[1][1] > d[2][2]:
+		s := 2 * math.Sqrt(1+d[1][1]-d[0][0]-d[2][2])
+		q = Quaternion{(d[0][2] - d[2][0]) / s, (d[0][1] + d[1][0]) / s, quarter * s, (d[1][2] + d[2][1]) / s}
+	default:
+		s := 2 * math.Sqrt(1+d[2][2]-d[0][0]-d[1][1])
+		q = Quaternion{(d[1][0] - d[0][1]) / s, (d[0][2] + d[2][0]) / s, (d[1][2] + d[2][1]) / s, quarter * s}
+	}
+	return q.Normalize()
+}
+
+// Matrix returns the rotation matrix of q.
+func (q Quaternion) Matrix() Matrix {
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return New(DefaultMatrixSize, DefaultMatrixSize,
+		1-2*(y*y+z*z), 2*(x*y-w*z), 2*(x*z+w*y), 0,
+		2*(x*y+w*z), 1-2*(x*x+z*z), 2*(y*z-w*x), 0,
+		2*(x*z-w*y), 2*(y*z+w*x), 1-2*(x*x+y*y), 0,
+		0, 0, 0, 1,
+	)
+}
+
+func (q Quaternion) Dot(q2 Quaternion) float64 {
+	return q.W*q2.W + q.X*q2.X + q.Y*q2.Y + q.Z*q2.Z
+}
+
+func (q Quaternion) Normalize() Quaternion {
+	length := math.Sqrt(q.Dot(q))
+	return Quaternion{q.W / length, q.X / length, q.Y / length, q.Z / length}
+}
+
+// Slerp returns the rotation a fraction t of the way from a to b, turning at
+// a constant speed along the shortest path.
+func Slerp(a, b Quaternion, t float64) Quaternion {
+	// Nearly equal rotations are blended linearly, where the angle between
+	// them is too small to divide by.
+	const nearlyEqual = 0.9995
+	cosine := a.Dot(b)
+	if cosine < 0 {
+		// q and -q are the same rotation; the closer of the two is the
+		// shorter way round.
+		b = Quaternion{-b.W, -b.X, -b.Y, -b.Z}
+		cosine = -cosine
+	}
+	wa, wb := 1-t, t
+	if cosine < nearlyEqual {
+		angle := math.Acos(cosine)
+		wa = math.Sin((1-t)*angle) / math.Sin(angle)
+		wb = math.Sin(t*angle) / math.Sin(angle)
+	}
+	return Quaternion{
+		wa*a.W + wb*b.W,
+		wa*a.X + wb*b.X,
+		wa*a.Y + wb*b.Y,
+		wa*a.Z + wb*b.Z,
+	}.Normalize()
+}
@@ -0,0 +1,148 @@
+// Package media describes what light meets between surfaces: fog that fades
+// distant objects into a color, and volumes of smoke or murky water that
+// absorb and scatter light throughout.
+package media
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/tuples"
+)
+
+// Fog fades what is seen into its color, exponentially with distance. It is
+// a cheap stand-in for a scattering medium filling the whole scene, which
+// gives a sense of depth to distant objects.
+type Fog struct {
+	Color tuples.Color
+	// Density is the fraction of the remaining view lost to fog per unit of
+	// distance. Half of it is lost after ln(2) / Density.
+	Density float64
+}
+
+func NewFog(color tuples.Color, density float64) Fog {
+	return Fog{Color: color, Density: density}
+}
+
+// Apply returns color c, seen from distance away through the fog. Distance
+// may be infinite, for rays that hit nothing and see only fog.
+func (f Fog) Apply(c tuples.Color, distance float64) tuples.Color {
+	if f.Density == 0 {
+		return c
+	}
+	seen := math.Exp(-f.Density * distance)
+	return c.Multiply(seen).Add(f.Color.Multiply(1 - seen))
+}
+
+// Homogeneous is a medium of even density, such as smoke or murky water,
+// which absorbs some of the light passing through it and scatters some into
+// other directions.
+type Homogeneous struct {
+	// Absorption and Scattering are the chances, per unit of distance, of
+	// light being absorbed and of it being scattered.
+	Absorption, Scattering float64
+	// Color tints the light that is scattered.
+	Color tuples.Color
+	// Phase says in which directions light is scattered.
+	Phase HenyeyGreenstein
+}
+
+// NewHomogeneous returns a white medium that scatters light equally in
+// every direction.
+func NewHomogeneous(absorption, scattering float64) Homogeneous {
+	return Homogeneous{Absorption: absorption, Scattering: scattering, Color: tuples.NewColor(1, 1, 1)}
+}
+
+// Extinction returns the chance, per unit of distance, of light being
+// either absorbed or scattered.
+func (h Homogeneous) Extinction() float64 {
+	return h.Absorption + h.Scattering
+}
+
+// Transmittance returns the fraction of light that passes through distance
+// of the medium unabsorbed and unscattered.
+func (h Homogeneous) Transmittance(distance float64) float64 {
+	return math.Exp(-h.Extinction() * distance)
+}
+
+// SampleDistance returns how far light travels through the medium before it
+// is absorbed or scattered, at random: short distances are likelier the
+// denser the medium. Light never stops in an empty medium.
+func (h Homogeneous) SampleDistance(rng *rand.Rand) float64 {
+	extinction := h.Extinction()
+	if extinction == 0 {
+		return math.Inf(1)
+	}
+	return -math.Log(1-rng.Float64()) / extinction
+}
+
+// Albedo returns the fraction of the light stopped by the medium that is
+// scattered rather than absorbed, tinted by its color.
+func (h Homogeneous) Albedo() tuples.Color {
+	extinction := h.Extinction()
+	if extinction == 0 {
+		return tuples.NewColor(0, 0, 0)
+	}
+	return h.Color.Multiply(h.Scattering / extinction)
+}
+
+// HenyeyGreenstein is the phase function of a medium: how the light it
+// scatters is spread over directions. It serves as the BSDF of a point in the
+// medium, which has no surface and so no cosine to weight the light by; the
+// Surface's Out is the direction towards the eye, and its Color tints the
+// scattered light.
+type HenyeyGreenstein struct {
+	// G runs from -1, scattering light back where it came from, through 0,
+	// scattering it equally in every direction, to 1, letting it carry on
+	// as it was. Smoke and fog scatter forwards, at about 0.5 to 0.9.
+	G float64
+}
+
+func NewHenyeyGreenstein(g float64) HenyeyGreenstein {
+	return HenyeyGreenstein{G: g}
+}
+
+// density returns the phase function for directions out and in, both
+// pointing away from the point, at cosine cosine to each other. Light
+// carrying on forwards has out opposite in, at a cosine of -1.
+func (p HenyeyGreenstein) density(cosine float64) float64 {
+	denominator := 1 + p.G*p.G + 2*p.G*cosine                                   // nolint: mnd // the Henyey-Greenstein formula
+	return (1 - p.G*p.G) / (4 * math.Pi * denominator * math.Sqrt(denominator)) // nolint: mnd // over the whole sphere
+}
+
+func (p HenyeyGreenstein) Evaluate(s bsdfs.Surface, wi tuples.Tuple) tuples.Color {
+	return s.Color.Multiply(p.density(s.Out.Dot(wi)))
+}
+
+// Sample picks a direction in proportion to the phase function, so its
+// weight is the color alone.
+func (p HenyeyGreenstein) Sample(s bsdfs.Surface, rng *rand.Rand) (bsdfs.Sample, bool) {
+	cosine := p.cosineAt(rng.Float64())
+	sine := math.Sqrt(max(0, 1-cosine*cosine))
+	phi := 2 * math.Pi * rng.Float64() // nolint: mnd // a full turn
+	local := tuples.Vector(sine*math.Cos(phi), sine*math.Sin(phi), cosine)
+	return bsdfs.Sample{
+		Direction: bsdfs.NewFrame(s.Out).ToWorld(local),
+		Weight:    s.Color,
+		PDF:       p.density(cosine),
+	}, true
+}
+
+// cosineAt returns the cosine to Out below which a fraction u of the
+// scattered light leaves: the inverse of the distribution of cosines.
+func (p HenyeyGreenstein) cosineAt(u float64) float64 { // nolint: mnd // the inverted Henyey-Greenstein formula
+	const isotropic = 1e-3
+	if math.Abs(p.G) < isotropic {
+		return 2*u - 1
+	}
+	root := (1 - p.G*p.G) / (1 + p.G - 2*p.G*u)
+	return -(1 + p.G*p.G - root*root) / (2 * p.G)
+}
+
+func (p HenyeyGreenstein) PDF(s bsdfs.Surface, wi tuples.Tuple) float64 {
+	return p.density(s.Out.Dot(wi))
+}
+
+func (HenyeyGreenstein) Specular(bsdfs.Surface) []bsdfs.Sample {
+	return nil
+}
@@ -0,0 +1,118 @@
+package media_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/media"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2)) // #nosec G404 -- a fixed seed for repeatable tests
+}
+
+// point returns the state of a point in a medium of color c, seen from
+// along the z axis.
+func point(c tuples.Color) bsdfs.Surface {
+	out := tuples.Vector(0, 0, -1)
+	return bsdfs.Surface{Normal: out, Out: out, Color: c, Entering: true}
+}
+
+func TestFog(t *testing.T) {
+	// Scenario: Fog fades distant colors into its own
+	// Given fog ← fog(color(0.5, 0.5, 0.5), density 0.2)
+	// Then apply(fog, color(1, 0, 0), 0) = color(1, 0, 0)
+	// And at ln(2) / 0.2 the color is halfway to the fog's
+	// And at an infinite distance only the fog is seen
+	// And fog with no density leaves colors alone, even at an infinite distance
+	fog := media.NewFog(tuples.NewColor(0.5, 0.5, 0.5), 0.2)
+	red := tuples.NewColor(1, 0, 0)
+	assert.True(t, red.Equals(fog.Apply(red, 0).Tuple))
+	assert.True(t, tuples.NewColor(0.75, 0.25, 0.25).Equals(fog.Apply(red, math.Ln2/0.2).Tuple))
+	assert.True(t, tuples.NewColor(0.5, 0.5, 0.5).Equals(fog.Apply(red, math.Inf(1)).Tuple))
+	none := media.NewFog(tuples.NewColor(0.5, 0.5, 0.5), 0)
+	assert.True(t, red.Equals(none.Apply(red, math.Inf(1)).Tuple))
+}
+
+func TestHomogeneousMedium(t *testing.T) {
+	// Scenario: A homogeneous medium thins light exponentially
+	// Given m ← homogeneous(absorption 0.5, scattering 1.5)
+	// Then extinction(m) = 2
+	// And transmittance(m, 1) = e^-2
+	// And albedo(m) = color(0.75, 0.75, 0.75)
+	// And sampled distances average 1 / 2
+	m := media.NewHomogeneous(0.5, 1.5)
+	assert.InDelta(t, 2, m.Extinction(), 1e-12)
+	assert.InDelta(t, math.Exp(-2), m.Transmittance(1), 1e-12)
+	assert.True(t, tuples.NewColor(0.75, 0.75, 0.75).Equals(m.Albedo().Tuple))
+	rng := newRand()
+	sum := 0.0
+	const n = 20000
+	for range n {
+		sum += m.SampleDistance(rng)
+	}
+	assert.InDelta(t, 0.5, sum/n, 0.01)
+}
+
+func TestEmptyMedium(t *testing.T) {
+	// Scenario: Light passes through an empty medium untouched
+	// Given m ← homogeneous(absorption 0, scattering 0)
+	// Then transmittance(m, 100) = 1
+	// And light never stops in it
+	// And it scatters nothing
+	m := media.NewHomogeneous(0, 0)
+	assert.InDelta(t, 1, m.Transmittance(100), 1e-12)
+	assert.True(t, math.IsInf(m.SampleDistance(newRand()), 1))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(m.Albedo().Tuple))
+}
+
+func TestHenyeyGreensteinScattersAllLight(t *testing.T) {
+	// Scenario: The phase function spreads all the light it scatters
+	// Given phase functions with g from -0.8 to 0.8
+	// When each is integrated over the sphere
+	// Then the result is 1
+	for _, g := range []float64{-0.8, -0.3, 0, 0.3, 0.8} {
+		p := media.NewHenyeyGreenstein(g)
+		s := point(tuples.NewColor(1, 1, 1))
+		const n = 400
+		sum := 0.0
+		for i := range n {
+			// The phase function is symmetric about Out, so integrating
+			// over its cosine is enough.
+			cosine := -1 + (float64(i)+0.5)*2/n
+			wi := tuples.Vector(math.Sqrt(1-cosine*cosine), 0, -cosine)
+			sum += p.Evaluate(s, wi).Red() * 2 * math.Pi * 2 / n
+		}
+		assert.InDelta(t, 1, sum, 0.01, "g = %g", g)
+	}
+}
+
+func TestSamplingHenyeyGreenstein(t *testing.T) {
+	// Scenario: Sampling the phase function follows it
+	// Given phase functions with g of -0.6, 0 and 0.6
+	// When directions are sampled at a point in a medium of color(0.5, 1, 1)
+	// Then each weight is color(0.5, 1, 1) and each pdf is the phase function
+	// And the average cosine between the eye and the sample is -g, since light
+	// carrying on forwards leaves opposite the eye
+	for _, g := range []float64{-0.6, 0, 0.6} {
+		p := media.NewHenyeyGreenstein(g)
+		s := point(tuples.NewColor(0.5, 1, 1))
+		rng := newRand()
+		sum := 0.0
+		const n = 20000
+		for range n {
+			sample, ok := p.Sample(s, rng)
+			require.True(t, ok)
+			assert.True(t, s.Color.Equals(sample.Weight.Tuple))
+			assert.InDelta(t, p.PDF(s, sample.Direction), sample.PDF, 1e-9)
+			assert.InDelta(t, 1, tuples.Magnitude(sample.Direction), 1e-9)
+			sum += s.Out.Dot(sample.Direction)
+		}
+		assert.InDelta(t, -g, sum/n, 0.02, "g = %g", g)
+	}
+}
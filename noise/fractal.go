@@ -0,0 +1,72 @@
+package noise
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+const (
+	// DefaultLacunarity is how much finer each octave of fractal noise is
+	// than the one before.
+	DefaultLacunarity = 2.0
+	// DefaultGain is how much weaker each octave of fractal noise is than
+	// the one before.
+	DefaultGain = 0.5
+)
+
+// FBM is fractal Brownian motion: octaves of a source summed at rising
+// frequencies and falling amplitudes, which adds fine detail to its broad
+// shapes. The sum is divided by the total amplitude so that it keeps the
+// range of the source.
+type FBM struct {
+	Source     Source
+	Octaves    int
+	Lacunarity float64
+	Gain       float64
+}
+
+func NewFBM(source Source, octaves int) FBM {
+	return FBM{Source: source, Octaves: octaves, Lacunarity: DefaultLacunarity, Gain: DefaultGain}
+}
+
+func (f FBM) At(point tuples.Tuple) float64 {
+	return octaves(f.Source, point, f.Octaves, f.Lacunarity, f.Gain, func(v float64) float64 { return v })
+}
+
+// Turbulence is like FBM but sums the magnitude of each octave, giving the
+// sharp creases seen in marble, fire and clouds. For sources within [-1, 1]
+// it lies within [0, 1].
+type Turbulence struct {
+	Source     Source
+	Octaves    int
+	Lacunarity float64
+	Gain       float64
+}
+
+func NewTurbulence(source Source, octaves int) Turbulence {
+	return Turbulence{Source: source, Octaves: octaves, Lacunarity: DefaultLacunarity, Gain: DefaultGain}
+}
+
+func (t Turbulence) At(point tuples.Tuple) float64 {
+	return octaves(t.Source, point, t.Octaves, t.Lacunarity, t.Gain, math.Abs)
+}
+
+// octaves returns the weighted average of shape applied to count octaves of
+// source.
+func octaves(
+	source Source, point tuples.Tuple, count int, lacunarity, gain float64, shape func(float64) float64,
+) float64 {
+	sum, total := 0.0, 0.0
+	frequency, amplitude := 1.0, 1.0
+	for range count {
+		p := tuples.Point(point.X*frequency, point.Y*frequency, point.Z*frequency)
+		sum += amplitude * shape(source.At(p))
+		total += amplitude
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
@@ -0,0 +1,71 @@
+package noise_test
+
+import (
+	"math"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// constant is noise with the same value everywhere.
+type constant float64
+
+func (c constant) At(tuples.Tuple) float64 {
+	return float64(c)
+}
+
+// xRamp is noise equal to the x coordinate of the point.
+type xRamp struct{}
+
+func (xRamp) At(point tuples.Tuple) float64 {
+	return point.X
+}
+
+func TestFBMWithOneOctaveIsTheSource(t *testing.T) {
+	// Scenario: A single octave of fBm is the source itself
+	// Given p ← perlin(1)
+	// And f ← fbm(p, 1)
+	// Then f and p agree everywhere
+	p := noise.NewPerlin(1)
+	f := noise.NewFBM(p, 1)
+	for i := range 20 {
+		point := tuples.Point(float64(i)*0.31, float64(i)*-0.7, 0.5)
+		assert.InDelta(t, p.At(point), f.At(point), 1e-12)
+	}
+}
+
+func TestFBMSumsOctaves(t *testing.T) {
+	// Scenario: fBm averages octaves at doubling frequencies and halving amplitudes
+	// Given f ← fbm(x ramp, 3)
+	// When f is sampled at x = 1
+	// Then it is (1×1 + 0.5×2 + 0.25×4) / (1 + 0.5 + 0.25)
+	f := noise.NewFBM(xRamp{}, 3)
+	assert.InDelta(t, 3/1.75, f.At(tuples.Point(1, 0, 0)), 1e-12)
+}
+
+func TestFBMKeepsRangeOfSource(t *testing.T) {
+	// Scenario: fBm of a constant is the constant
+	f := noise.NewFBM(constant(0.4), 5)
+	assert.InDelta(t, 0.4, f.At(tuples.Point(1, 2, 3)), 1e-12)
+}
+
+func TestTurbulenceSumsMagnitudes(t *testing.T) {
+	// Scenario: Turbulence averages the magnitude of each octave
+	// Given t ← turbulence(constant -0.4, 4)
+	// Then t is 0.4 everywhere
+	// And the turbulence of Perlin noise lies within [0, 1]
+	assert.InDelta(t, 0.4, noise.NewTurbulence(constant(-0.4), 4).At(tuples.Point(1, 2, 3)), 1e-12)
+	turbulence := noise.NewTurbulence(noise.NewSimplex(2), 4)
+	for i := range 200 {
+		value := turbulence.At(tuples.Point(float64(i)*0.13, math.Sin(float64(i)), 0.25))
+		assert.GreaterOrEqual(t, value, 0.0)
+		assert.LessOrEqual(t, value, 1.0)
+	}
+}
+
+func TestFBMWithoutOctavesIsZero(t *testing.T) {
+	// Scenario: fBm of no octaves is zero rather than undefined
+	assert.InDelta(t, 0, noise.NewFBM(constant(1), 0).At(tuples.Point(0, 0, 0)), 0)
+}
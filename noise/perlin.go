@@ -0,0 +1,117 @@
+// Package noise generates smooth pseudo-random values over space, for
+// procedural patterns and bumps.
+package noise
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/tuples"
+)
+
+// permutationSize is the number of lattice cells before the noise repeats.
+const permutationSize = 256
+
+// Source is noise: a value that varies smoothly but unpredictably with the
+// point it is sampled at.
+type Source interface {
+	At(point tuples.Tuple) float64
+}
+
+// permutation is a shuffle of the lattice cells, repeated so that it can be
+// indexed by the sum of two cells without wrapping.
+type permutation [2 * permutationSize]int
+
+func newPermutation(seed uint64) permutation {
+	rng := rand.New(rand.NewPCG(seed, seed)) // #nosec G404 -- noise needs no cryptographic randomness
+	var perm permutation
+	for i, v := range rng.Perm(permutationSize) {
+		perm[i] = v
+		perm[i+permutationSize] = v
+	}
+	return perm
+}
+
+// Perlin is Ken Perlin's improved gradient noise. It is smooth, zero at every
+// integer lattice point and stays roughly within [-1, 1].
+type Perlin struct {
+	perm permutation
+}
+
+// NewPerlin returns Perlin noise whose lattice gradients are shuffled by
+// seed, so that different seeds give unrelated noise.
+func NewPerlin(seed uint64) *Perlin {
+	return &Perlin{perm: newPermutation(seed)}
+}
+
+// At returns the noise at point.
+func (p *Perlin) At(point tuples.Tuple) float64 {
+	x, y, z := point.X, point.Y, point.Z
+	xi, yi, zi := lattice(x), lattice(y), lattice(z)
+	x -= math.Floor(x)
+	y -= math.Floor(y)
+	z -= math.Floor(z)
+	u, v, w := fade(x), fade(y), fade(z)
+
+	a := p.perm[xi] + yi
+	aa, ab := p.perm[a]+zi, p.perm[a+1]+zi
+	b := p.perm[xi+1] + yi
+	ba, bb := p.perm[b]+zi, p.perm[b+1]+zi
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p.perm[aa], x, y, z), grad(p.perm[ba], x-1, y, z)),
+			lerp(u, grad(p.perm[ab], x, y-1, z), grad(p.perm[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(p.perm[aa+1], x, y, z-1), grad(p.perm[ba+1], x-1, y, z-1)),
+			lerp(u, grad(p.perm[ab+1], x, y-1, z-1), grad(p.perm[bb+1], x-1, y-1, z-1))))
+}
+
+// lattice returns the cell containing v, wrapped into the permutation table.
+func lattice(v float64) int {
+	return int(math.Floor(v)) & (permutationSize - 1)
+}
+
+// fade eases t so that the noise has continuous first and second
+// derivatives across cells.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10) // nolint: mnd // Perlin's quintic fade curve
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad returns the dot product of (x, y, z) with one of twelve gradient
+// directions toward the edges of a cube, chosen by hash.
+func grad(hash int, x, y, z float64) float64 {
+	const (
+		mask = 15
+		// Hashes below uFromX take u from x, and the rest from y.
+		uFromX = 8
+		// Hashes below vFromY take v from y, vFromX and vFromXToo from x,
+		// and the rest from z.
+		vFromY    = 4
+		vFromX    = 12
+		vFromXToo = 14
+		negFirst  = 1
+		negOther  = 2
+	)
+	h := hash & mask
+	u := y
+	if h < uFromX {
+		u = x
+	}
+	v := z
+	if h < vFromY {
+		v = y
+	} else if h == vFromX || h == vFromXToo {
+		v = x
+	}
+	if h&negFirst != 0 {
+		u = -u
+	}
+	if h&negOther != 0 {
+		v = -v
+	}
+	return u + v
+}
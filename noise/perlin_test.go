@@ -0,0 +1,57 @@
+package noise_test
+
+import (
+	"raytracer-vibe/noise"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerlinIsZeroAtLatticePoints(t *testing.T) {
+	// Scenario: Perlin noise is zero at every integer point
+	p := noise.NewPerlin(1)
+	for _, point := range []tuples.Tuple{
+		tuples.Point(0, 0, 0), tuples.Point(3, -2, 7), tuples.Point(-100, 255, 256),
+	} {
+		assert.InDelta(t, 0, p.At(point), 1e-12, "at %v", point)
+	}
+}
+
+func TestPerlinIsRepeatableForSeed(t *testing.T) {
+	// Scenario: The same seed always gives the same noise
+	// Given a ← perlin(7) and b ← perlin(7) and c ← perlin(8)
+	// Then a and b agree everywhere
+	// And a and c differ
+	a, b, c := noise.NewPerlin(7), noise.NewPerlin(7), noise.NewPerlin(8)
+	differ := false
+	for i := range 100 {
+		point := tuples.Point(float64(i)*0.37, float64(i)*0.11, float64(i)*-0.23)
+		assert.InDelta(t, a.At(point), b.At(point), 0, "at %v", point)
+		differ = differ || a.At(point) != c.At(point)
+	}
+	assert.True(t, differ)
+}
+
+func TestPerlinIsSmoothAndBounded(t *testing.T) {
+	// Scenario: Perlin noise stays within [-1, 1] and changes gradually
+	// Given p ← perlin(3)
+	// When the noise is sampled along a line in small steps
+	// Then every value lies within [-1, 1]
+	// And neighboring values differ by much less than the step would allow for random values
+	// And the values are not all the same
+	p := noise.NewPerlin(3)
+	const step = 0.01
+	previous := p.At(tuples.Point(0.5, 0.25, 0.125))
+	lowest, highest := previous, previous
+	for i := 1; i < 1000; i++ {
+		value := p.At(tuples.Point(0.5+float64(i)*step, 0.25+float64(i)*step/2, 0.125))
+		assert.LessOrEqual(t, value, 1.0)
+		assert.GreaterOrEqual(t, value, -1.0)
+		assert.Less(t, value-previous, 0.05)
+		assert.Greater(t, value-previous, -0.05)
+		lowest, highest = min(lowest, value), max(highest, value)
+		previous = value
+	}
+	assert.Greater(t, highest-lowest, 0.5)
+}
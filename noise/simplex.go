@@ -0,0 +1,84 @@
+package noise
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// Simplex is Ken Perlin's simplex noise. It sums the gradients of the four
+// corners of the tetrahedron containing the point rather than the eight of a
+// cube, so it is cheaper than Perlin noise and shows no grid-aligned
+// artifacts. It is zero at the origin and stays roughly within [-1, 1].
+type Simplex struct {
+	perm permutation
+}
+
+// NewSimplex returns simplex noise whose gradients are shuffled by seed.
+func NewSimplex(seed uint64) *Simplex {
+	return &Simplex{perm: newPermutation(seed)}
+}
+
+// At returns the noise at point.
+func (s *Simplex) At(point tuples.Tuple) float64 {
+	const (
+		// skew and unskew map between space and the lattice of cubes, each
+		// split into six tetrahedra.
+		skew   = 1.0 / 3
+		unskew = 1.0 / 6
+		// scale stretches the sum of the corners to about [-1, 1].
+		scale = 32
+	)
+	x, y, z := point.X, point.Y, point.Z
+	f := (x + y + z) * skew
+	i, j, k := math.Floor(x+f), math.Floor(y+f), math.Floor(z+f)
+	t := (i + j + k) * unskew
+	x0, y0, z0 := x-(i-t), y-(j-t), z-(k-t)
+
+	// The corners after the first are reached by stepping one cell along
+	// the axes in order of how far the point lies along them.
+	i1, j1, k1, i2, j2, k2 := simplexSteps(x0, y0, z0)
+	x1, y1, z1 := x0-float64(i1)+unskew, y0-float64(j1)+unskew, z0-float64(k1)+unskew
+	x2, y2, z2 := x0-float64(i2)+2*unskew, y0-float64(j2)+2*unskew, z0-float64(k2)+2*unskew
+	x3, y3, z3 := x0-1+3*unskew, y0-1+3*unskew, z0-1+3*unskew
+
+	ii, jj, kk := lattice(i), lattice(j), lattice(k)
+	p := &s.perm
+	g0 := p[ii+p[jj+p[kk]]]
+	g1 := p[ii+i1+p[jj+j1+p[kk+k1]]]
+	g2 := p[ii+i2+p[jj+j2+p[kk+k2]]]
+	g3 := p[ii+1+p[jj+1+p[kk+1]]]
+
+	return scale * (corner(g0, x0, y0, z0) + corner(g1, x1, y1, z1) +
+		corner(g2, x2, y2, z2) + corner(g3, x3, y3, z3))
+}
+
+// simplexSteps returns the offsets of the second and third corners of the
+// tetrahedron containing the point at (x, y, z) within its cube.
+func simplexSteps(x, y, z float64) (int, int, int, int, int, int) {
+	switch {
+	case x >= y && y >= z:
+		return 1, 0, 0, 1, 1, 0
+	case x >= y && x >= z:
+		return 1, 0, 0, 1, 0, 1
+	case x >= y:
+		return 0, 0, 1, 1, 0, 1
+	case y < z:
+		return 0, 0, 1, 0, 1, 1
+	case x < z:
+		return 0, 1, 0, 0, 1, 1
+	default:
+		return 0, 1, 0, 1, 1, 0
+	}
+}
+
+// corner returns the contribution of a corner at offset (x, y, z) from the
+// point, which falls to zero before reaching the neighboring tetrahedra.
+func corner(hash int, x, y, z float64) float64 {
+	const radius = 0.6
+	t := radius - x*x - y*y - z*z
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	return t * t * grad(hash, x, y, z)
+}
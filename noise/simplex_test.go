@@ -0,0 +1,52 @@
+package noise_test
+
+import (
+	"raytracer-vibe/noise"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimplexIsZeroAtOrigin(t *testing.T) {
+	// Scenario: Simplex noise is zero at the origin
+	s := noise.NewSimplex(1)
+	assert.InDelta(t, 0, s.At(tuples.Point(0, 0, 0)), 1e-12)
+}
+
+func TestSimplexIsRepeatableForSeed(t *testing.T) {
+	// Scenario: The same seed always gives the same simplex noise
+	// Given a ← simplex(7) and b ← simplex(7) and c ← simplex(8)
+	// Then a and b agree everywhere
+	// And a and c differ
+	a, b, c := noise.NewSimplex(7), noise.NewSimplex(7), noise.NewSimplex(8)
+	differ := false
+	for i := range 100 {
+		point := tuples.Point(float64(i)*0.37, float64(i)*0.11, float64(i)*-0.23)
+		assert.InDelta(t, a.At(point), b.At(point), 0, "at %v", point)
+		differ = differ || a.At(point) != c.At(point)
+	}
+	assert.True(t, differ)
+}
+
+func TestSimplexIsSmoothAndBounded(t *testing.T) {
+	// Scenario: Simplex noise stays within [-1, 1] and changes gradually
+	// Given s ← simplex(3)
+	// When the noise is sampled along a line in small steps
+	// Then every value lies within [-1, 1]
+	// And neighboring values differ by little
+	// And the values cover much of the range
+	s := noise.NewSimplex(3)
+	const step = 0.01
+	previous := s.At(tuples.Point(0.5, 0.25, 0.125))
+	lowest, highest := previous, previous
+	for i := 1; i < 1000; i++ {
+		value := s.At(tuples.Point(0.5+float64(i)*step, 0.25+float64(i)*step/2, 0.125-float64(i)*step/3))
+		assert.LessOrEqual(t, value, 1.0)
+		assert.GreaterOrEqual(t, value, -1.0)
+		assert.InDelta(t, previous, value, 0.1)
+		lowest, highest = min(lowest, value), max(highest, value)
+		previous = value
+	}
+	assert.Greater(t, highest-lowest, 0.5)
+}
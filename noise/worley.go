@@ -0,0 +1,82 @@
+package noise
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// Worley is cellular noise: space is scattered with feature points, one in
+// each unit cell, and the noise is the distance to the nearest of them. It
+// is zero at the feature points and rarely above 1, and looks like cells,
+// stones or scales.
+type Worley struct {
+	seed uint64
+}
+
+// NewWorley returns Worley noise whose feature points are placed by seed.
+func NewWorley(seed uint64) *Worley {
+	return &Worley{seed: seed}
+}
+
+// At returns the distance from point to the nearest feature point.
+func (w *Worley) At(point tuples.Tuple) float64 {
+	nearest, _ := w.Distances(point)
+	return nearest
+}
+
+// Distances returns the distances from point to the nearest and second
+// nearest feature points. Their difference is zero along the borders between
+// cells, which gives cracks and cell walls.
+func (w *Worley) Distances(point tuples.Tuple) (float64, float64) {
+	// The nearest two feature points are always within the cells around
+	// the one containing the point.
+	cx, cy, cz := math.Floor(point.X), math.Floor(point.Y), math.Floor(point.Z)
+	nearest, second := math.Inf(1), math.Inf(1)
+	for dz := -1.0; dz <= 1; dz++ {
+		for dy := -1.0; dy <= 1; dy++ {
+			for dx := -1.0; dx <= 1; dx++ {
+				d := tuples.Magnitude(point.Subtract(w.feature(cx+dx, cy+dy, cz+dz)))
+				if d < nearest {
+					nearest, second = d, nearest
+				} else if d < second {
+					second = d
+				}
+			}
+		}
+	}
+	return nearest, second
+}
+
+// feature returns the feature point in the cell whose lowest corner is
+// (x, y, z).
+func (w *Worley) feature(x, y, z float64) tuples.Tuple {
+	const (
+		bits = 21
+		mask = 1<<bits - 1
+		size = 1 << bits
+	)
+	h := w.seed
+	for _, c := range []float64{x, y, z} {
+		h = mix(h ^ uint64(int64(c))) // #nosec G115 -- any bit pattern of the cell will do
+	}
+	return tuples.Point(
+		x+float64(h&mask)/size,
+		y+float64(h>>bits&mask)/size,
+		z+float64(h>>(2*bits)&mask)/size)
+}
+
+// mix scrambles the bits of h, as in the SplitMix64 generator.
+func mix(h uint64) uint64 {
+	const (
+		increment = 0x9e3779b97f4a7c15
+		first     = 0xbf58476d1ce4e5b9
+		second    = 0x94d049bb133111eb
+		shift1    = 30
+		shift2    = 27
+		shift3    = 31
+	)
+	h += increment
+	h = (h ^ h>>shift1) * first
+	h = (h ^ h>>shift2) * second
+	return h ^ h>>shift3
+}
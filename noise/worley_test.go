@@ -0,0 +1,56 @@
+package noise_test
+
+import (
+	"raytracer-vibe/noise"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorleyDistances(t *testing.T) {
+	// Scenario: The nearest feature point is never further than the second
+	// Given w ← worley(1)
+	// Then at every sample the nearest distance is at most the second
+	// And the nearest distance is never more than the diagonal of a cell
+	w := noise.NewWorley(1)
+	for i := range 200 {
+		point := tuples.Point(float64(i)*0.173, float64(i)*-0.311, float64(i)*0.057)
+		nearest, second := w.Distances(point)
+		assert.GreaterOrEqual(t, nearest, 0.0)
+		assert.LessOrEqual(t, nearest, second)
+		assert.LessOrEqual(t, nearest, 1.7321)
+		assert.InDelta(t, nearest, w.At(point), 0)
+	}
+}
+
+func TestWorleyChangesNoFasterThanThePoint(t *testing.T) {
+	// Scenario: Worley noise is continuous
+	// Given w ← worley(4)
+	// When the point moves a small step
+	// Then the distance to the nearest feature point changes by at most that step
+	// And somewhere along the way the point passes close to a feature point
+	w := noise.NewWorley(4)
+	const step = 0.01
+	previous := w.At(tuples.Point(0, 0.5, 0.5))
+	lowest := previous
+	for i := 1; i < 2000; i++ {
+		value := w.At(tuples.Point(float64(i)*step, 0.5, 0.5))
+		assert.InDelta(t, previous, value, step+1e-9)
+		lowest = min(lowest, value)
+		previous = value
+	}
+	assert.Less(t, lowest, 0.2)
+}
+
+func TestWorleyIsRepeatableForSeed(t *testing.T) {
+	// Scenario: The same seed places the same feature points
+	a, b, c := noise.NewWorley(9), noise.NewWorley(9), noise.NewWorley(10)
+	differ := false
+	for i := range 50 {
+		point := tuples.Point(float64(i)*0.7, float64(i)*0.3, -float64(i)*0.9)
+		assert.InDelta(t, a.At(point), b.At(point), 0)
+		differ = differ || a.At(point) != c.At(point)
+	}
+	assert.True(t, differ)
+}
@@ -0,0 +1,148 @@
+package patterns
+
+import (
+	"math"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/tuples"
+)
+
+// TextureFilter chooses how an ImageTexture combines the pixels around a
+// texture coordinate.
+type TextureFilter int
+
+const (
+	// FilterNearest returns the pixel containing the coordinate.
+	FilterNearest TextureFilter = iota
+	// FilterBilinear blends the four pixels nearest the coordinate.
+	FilterBilinear
+	// FilterTrilinear blends bilinear samples from the two mipmap levels
+	// whose pixels best match the area of the texture seen by a pixel of the
+	// image, so that distant and steeply viewed textures do not shimmer.
+	FilterTrilinear
+)
+
+// Wrap chooses how an ImageTexture extends beyond its edges.
+type Wrap int
+
+const (
+	// WrapRepeat tiles the image.
+	WrapRepeat Wrap = iota
+	// WrapClamp extends the edge pixels outwards.
+	WrapClamp
+	// WrapMirror tiles the image, flipping every other tile.
+	WrapMirror
+)
+
+// ImageTexture looks colors up in an image, with v = 0 at the bottom row.
+// Pixels cover equal squares of the texture, so (0, 0) is the corner of the
+// bottom left pixel rather than its center.
+type ImageTexture struct {
+	// Levels is the image followed by its mipmaps, each half the size of the
+	// one before, down to a single pixel.
+	Levels []*canvas.Canvas
+	Filter TextureFilter
+	Wrap   Wrap
+}
+
+func NewImageTexture(c *canvas.Canvas, filter TextureFilter, wrap Wrap) ImageTexture {
+	levels := []*canvas.Canvas{c}
+	for c.Width > 1 || c.Height > 1 {
+		c = downsample(c)
+		levels = append(levels, c)
+	}
+	return ImageTexture{Levels: levels, Filter: filter, Wrap: wrap}
+}
+
+// UVPatternAt returns the color at (u, v) in the full size image.
+func (t ImageTexture) UVPatternAt(u, v float64) tuples.Color {
+	return t.UVPatternFiltered(u, v, 0)
+}
+
+// UVPatternFiltered returns the color at (u, v), averaged over a square of
+// the texture footprint wide when the filter is trilinear.
+func (t ImageTexture) UVPatternFiltered(u, v, footprint float64) tuples.Color {
+	switch t.Filter {
+	case FilterBilinear:
+		return t.bilinear(t.Levels[0], u, v)
+	case FilterTrilinear:
+		return t.trilinear(u, v, footprint)
+	case FilterNearest:
+	}
+	return t.nearest(t.Levels[0], u, v)
+}
+
+// trilinear picks the mipmap level whose pixels are footprint wide, and
+// blends between the levels either side of it.
+func (t ImageTexture) trilinear(u, v, footprint float64) tuples.Color {
+	base := t.Levels[0]
+	lod := math.Log2(footprint * float64(max(base.Width, base.Height)))
+	if footprint <= 0 || lod <= 0 {
+		return t.bilinear(base, u, v)
+	}
+	last := len(t.Levels) - 1
+	if lod >= float64(last) {
+		return t.bilinear(t.Levels[last], u, v)
+	}
+	level := int(lod)
+	blend := lod - float64(level)
+	fine := t.bilinear(t.Levels[level], u, v)
+	coarse := t.bilinear(t.Levels[level+1], u, v)
+	return fine.Multiply(1 - blend).Add(coarse.Multiply(blend))
+}
+
+func (t ImageTexture) nearest(c *canvas.Canvas, u, v float64) tuples.Color {
+	x := int(math.Floor(u * float64(c.Width)))
+	y := int(math.Floor((1 - v) * float64(c.Height)))
+	return c.PixelAt(t.wrap(x, c.Width), t.wrap(y, c.Height))
+}
+
+func (t ImageTexture) bilinear(c *canvas.Canvas, u, v float64) tuples.Color {
+	const half = 0.5
+	x := u*float64(c.Width) - half
+	y := (1-v)*float64(c.Height) - half
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	left, right := t.wrap(int(x0), c.Width), t.wrap(int(x0)+1, c.Width)
+	top, bottom := t.wrap(int(y0), c.Height), t.wrap(int(y0)+1, c.Height)
+	upper := c.PixelAt(left, top).Multiply(1 - fx).Add(c.PixelAt(right, top).Multiply(fx))
+	lower := c.PixelAt(left, bottom).Multiply(1 - fx).Add(c.PixelAt(right, bottom).Multiply(fx))
+	return upper.Multiply(1 - fy).Add(lower.Multiply(fy))
+}
+
+// wrap maps a pixel index that may lie outside the image onto one inside it.
+func (t ImageTexture) wrap(i, size int) int {
+	const two = 2
+	switch t.Wrap {
+	case WrapClamp:
+		return min(max(i, 0), size-1)
+	case WrapMirror:
+		i = int(mod(float64(i), float64(two*size)))
+		if i >= size {
+			return two*size - 1 - i
+		}
+		return i
+	case WrapRepeat:
+	}
+	return int(mod(float64(i), float64(size)))
+}
+
+// downsample returns c at half its size, rounded up, with each pixel the
+// average of the pixels it covers.
+func downsample(c *canvas.Canvas) *canvas.Canvas {
+	const two = 2
+	width, height := (c.Width+1)/two, (c.Height+1)/two
+	half := canvas.NewCanvas(width, height)
+	for y := range height {
+		for x := range width {
+			sum, count := tuples.NewColor(0, 0, 0), 0
+			for sy := two * y; sy < min(two*y+two, c.Height); sy++ {
+				for sx := two * x; sx < min(two*x+two, c.Width); sx++ {
+					sum = sum.Add(c.PixelAt(sx, sy))
+					count++
+				}
+			}
+			half.WritePixel(x, y, sum.Multiply(1/float64(count)))
+		}
+	}
+	return half
+}
@@ -0,0 +1,126 @@
+package patterns_test
+
+import (
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quadrants returns a 2x2 canvas with a different color in each pixel.
+func quadrants() *canvas.Canvas {
+	c := canvas.NewCanvas(2, 2)
+	c.WritePixel(0, 0, tuples.NewColor(1, 0, 0))
+	c.WritePixel(1, 0, tuples.NewColor(0, 1, 0))
+	c.WritePixel(0, 1, tuples.NewColor(0, 0, 1))
+	c.WritePixel(1, 1, tuples.NewColor(1, 1, 1))
+	return c
+}
+
+func TestImageTextureBuildsMipmaps(t *testing.T) {
+	// Scenario: An image texture halves its image down to a single pixel
+	// Given c ← a 4x3 canvas, black except for a white pixel at (0, 0)
+	// When texture ← image_texture(c, nearest, repeat)
+	// Then texture has levels of 4x3, 2x2 and 1x1 pixels
+	// And each pixel of a level averages the pixels it covers in the level before
+	c := canvas.NewCanvas(4, 3)
+	c.WritePixel(0, 0, tuples.NewColor(1, 1, 1))
+	texture := patterns.NewImageTexture(c, patterns.FilterNearest, patterns.WrapRepeat)
+	require.Len(t, texture.Levels, 3)
+	assert.Equal(t, 2, texture.Levels[1].Width)
+	assert.Equal(t, 2, texture.Levels[1].Height)
+	assert.True(t, tuples.NewColor(0.25, 0.25, 0.25).Equals(texture.Levels[1].PixelAt(0, 0).Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(texture.Levels[1].PixelAt(0, 1).Tuple))
+	assert.Equal(t, 1, texture.Levels[2].Width)
+	assert.True(t, tuples.NewColor(0.0625, 0.0625, 0.0625).Equals(texture.Levels[2].PixelAt(0, 0).Tuple))
+}
+
+func TestImageTextureNearest(t *testing.T) {
+	// Scenario Outline: Nearest filtering returns the pixel containing (u, v)
+	// Given texture ← image_texture(quadrants, nearest, repeat)
+	// Then uv_pattern_at(texture, <u>, <v>) = <color>
+	texture := patterns.NewImageTexture(quadrants(), patterns.FilterNearest, patterns.WrapRepeat)
+	tests := []struct {
+		u, v     float64
+		expected tuples.Color
+	}{
+		{0.25, 0.75, tuples.NewColor(1, 0, 0)},
+		{0.75, 0.75, tuples.NewColor(0, 1, 0)},
+		{0.25, 0.25, tuples.NewColor(0, 0, 1)},
+		{0.99, 0.01, tuples.NewColor(1, 1, 1)},
+		{0, 1, tuples.NewColor(1, 0, 0)},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, texture.UVPatternAt(tt.u, tt.v), "(%v, %v)", tt.u, tt.v)
+	}
+}
+
+func TestImageTextureBilinear(t *testing.T) {
+	// Scenario Outline: Bilinear filtering blends the four nearest pixels
+	// Given texture ← image_texture(quadrants, bilinear, clamp)
+	// Then uv_pattern_at(texture, <u>, <v>) = <color>
+	texture := patterns.NewImageTexture(quadrants(), patterns.FilterBilinear, patterns.WrapClamp)
+	tests := []struct {
+		u, v     float64
+		expected tuples.Color
+	}{
+		{0.25, 0.75, tuples.NewColor(1, 0, 0)},
+		{0.5, 0.75, tuples.NewColor(0.5, 0.5, 0)},
+		{0.5, 0.5, tuples.NewColor(0.5, 0.5, 0.5)},
+		{0.375, 0.5, tuples.NewColor(0.5, 0.25, 0.5)},
+		{0, 1, tuples.NewColor(1, 0, 0)},
+	}
+	for _, tt := range tests {
+		assert.True(t, tt.expected.Equals(texture.UVPatternAt(tt.u, tt.v).Tuple), "(%v, %v)", tt.u, tt.v)
+	}
+}
+
+func TestImageTextureWrapModes(t *testing.T) {
+	// Scenario Outline: Wrap modes extend the image beyond its edges
+	// Given c ← a 2x1 canvas, red then blue
+	// And texture ← image_texture(c, nearest, <wrap>)
+	// Then uv_pattern_at(texture, <u>, 0.5) = <color>
+	red := tuples.NewColor(1, 0, 0)
+	blue := tuples.NewColor(0, 0, 1)
+	c := canvas.NewCanvas(2, 1)
+	c.WritePixel(0, 0, red)
+	c.WritePixel(1, 0, blue)
+	tests := []struct {
+		wrap     patterns.Wrap
+		u        float64
+		expected tuples.Color
+	}{
+		{patterns.WrapRepeat, 1.25, red},
+		{patterns.WrapRepeat, -0.25, blue},
+		{patterns.WrapClamp, 1.25, blue},
+		{patterns.WrapClamp, -3, red},
+		{patterns.WrapMirror, 1.25, blue},
+		{patterns.WrapMirror, 1.75, red},
+		{patterns.WrapMirror, -0.25, red},
+		{patterns.WrapMirror, 2.25, red},
+	}
+	for _, tt := range tests {
+		texture := patterns.NewImageTexture(c, patterns.FilterNearest, tt.wrap)
+		assert.Equal(t, tt.expected, texture.UVPatternAt(tt.u, 0.5), "wrap %d at u = %v", tt.wrap, tt.u)
+	}
+}
+
+func TestImageTextureTrilinear(t *testing.T) {
+	// Scenario: Trilinear filtering blurs more as the footprint grows
+	// Given texture ← image_texture(quadrants, trilinear, repeat)
+	// Then a footprint of 0 or a single pixel samples the image bilinearly
+	// And a footprint of the whole texture returns its average color
+	// And a footprint in between blends the two levels
+	texture := patterns.NewImageTexture(quadrants(), patterns.FilterTrilinear, patterns.WrapRepeat)
+	red := tuples.NewColor(1, 0, 0)
+	average := tuples.NewColor(0.5, 0.5, 0.5)
+	assert.True(t, red.Equals(texture.UVPatternFiltered(0.25, 0.75, 0).Tuple))
+	assert.True(t, red.Equals(texture.UVPatternFiltered(0.25, 0.75, 0.5).Tuple))
+	assert.True(t, average.Equals(texture.UVPatternFiltered(0.25, 0.75, 1).Tuple))
+	assert.True(t, average.Equals(texture.UVPatternFiltered(0.25, 0.75, 4).Tuple))
+	between := texture.UVPatternFiltered(0.25, 0.75, 0.7071067811865476)
+	assert.True(t, tuples.NewColor(0.75, 0.25, 0.25).Equals(between.Tuple))
+}
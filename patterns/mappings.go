@@ -0,0 +1,93 @@
+package patterns
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// Mapping turns a point on the surface of an object into texture
+// coordinates (u, v), both in [0, 1].
+type Mapping func(point tuples.Tuple) (float64, float64)
+
+// SphericalMap wraps the texture around a unit sphere at the origin, with u
+// running around the equator and v from the south to the north pole.
+func SphericalMap(p tuples.Tuple) (float64, float64) {
+	const half = 0.5
+	theta := math.Atan2(p.X, p.Z)
+	radius := tuples.Magnitude(tuples.Vector(p.X, p.Y, p.Z))
+	phi := math.Acos(p.Y / radius)
+	rawU := theta / (2 * math.Pi)
+	u := 1 - (rawU + half)
+	v := 1 - phi/math.Pi
+	return u, v
+}
+
+// PlanarMap tiles the texture over the xz plane, repeating every unit.
+func PlanarMap(p tuples.Tuple) (float64, float64) {
+	return mod(p.X, 1), mod(p.Z, 1)
+}
+
+// CylindricalMap wraps the texture around a unit cylinder along the y axis,
+// repeating every unit of height.
+func CylindricalMap(p tuples.Tuple) (float64, float64) {
+	const half = 0.5
+	theta := math.Atan2(p.X, p.Z)
+	rawU := theta / (2 * math.Pi)
+	u := 1 - (rawU + half)
+	return u, mod(p.Y, 1)
+}
+
+// Face is a face of the cube from -1 to 1 on every axis.
+type Face int
+
+const (
+	FaceLeft Face = iota
+	FaceRight
+	FaceFront
+	FaceBack
+	FaceUp
+	FaceDown
+	faceCount
+)
+
+// FaceFromPoint returns the face of the cube nearest to p.
+func FaceFromPoint(p tuples.Tuple) Face {
+	coord := max(math.Abs(p.X), math.Abs(p.Y), math.Abs(p.Z))
+	switch coord {
+	case p.X:
+		return FaceRight
+	case -p.X:
+		return FaceLeft
+	case p.Y:
+		return FaceUp
+	case -p.Y:
+		return FaceDown
+	case p.Z:
+		return FaceFront
+	}
+	return FaceBack
+}
+
+// CubeMap maps a point on the cube to coordinates on the face it lies on.
+// Each face is seen from outside the cube, upright, with the up and down
+// faces seen from the front.
+func CubeMap(p tuples.Tuple) (Face, float64, float64) {
+	const two = 2
+	face := FaceFromPoint(p)
+	var u, v float64
+	switch face {
+	case FaceFront:
+		u, v = mod(p.X+1, two)/two, mod(p.Y+1, two)/two
+	case FaceBack:
+		u, v = mod(1-p.X, two)/two, mod(p.Y+1, two)/two
+	case FaceLeft:
+		u, v = mod(p.Z+1, two)/two, mod(p.Y+1, two)/two
+	case FaceRight:
+		u, v = mod(1-p.Z, two)/two, mod(p.Y+1, two)/two
+	case FaceUp:
+		u, v = mod(p.X+1, two)/two, mod(1-p.Z, two)/two
+	case FaceDown, faceCount:
+		u, v = mod(p.X+1, two)/two, mod(p.Z+1, two)/two
+	}
+	return face, u, v
+}
@@ -0,0 +1,127 @@
+package patterns_test
+
+import (
+	"math"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSphericalMapping(t *testing.T) {
+	// Scenario Outline: Using a spherical mapping on a 3D point
+	// Given p ← <point>
+	// When (u, v) ← spherical_map(p)
+	// Then u = <u>
+	// And v = <v>
+	tests := []struct {
+		point tuples.Tuple
+		u, v  float64
+	}{
+		{tuples.Point(0, 0, -1), 0.0, 0.5},
+		{tuples.Point(1, 0, 0), 0.25, 0.5},
+		{tuples.Point(0, 0, 1), 0.5, 0.5},
+		{tuples.Point(-1, 0, 0), 0.75, 0.5},
+		{tuples.Point(0, 1, 0), 0.5, 1.0},
+		{tuples.Point(0, -1, 0), 0.5, 0.0},
+		{tuples.Point(math.Sqrt2/2, math.Sqrt2/2, 0), 0.25, 0.75},
+	}
+	for _, tt := range tests {
+		u, v := patterns.SphericalMap(tt.point)
+		assert.InDelta(t, tt.u, u, 0.00001, "u at %v", tt.point)
+		assert.InDelta(t, tt.v, v, 0.00001, "v at %v", tt.point)
+	}
+}
+
+func TestPlanarMapping(t *testing.T) {
+	// Scenario Outline: Using a planar mapping on a 3D point
+	tests := []struct {
+		point tuples.Tuple
+		u, v  float64
+	}{
+		{tuples.Point(0.25, 0, 0.5), 0.25, 0.5},
+		{tuples.Point(0.25, 0, -0.25), 0.25, 0.75},
+		{tuples.Point(0.25, 0.5, -0.25), 0.25, 0.75},
+		{tuples.Point(1.25, 0, 0.5), 0.25, 0.5},
+		{tuples.Point(0.25, 0, -1.75), 0.25, 0.25},
+		{tuples.Point(1, 0, -1), 0.0, 0.0},
+		{tuples.Point(0, 0, 0), 0.0, 0.0},
+	}
+	for _, tt := range tests {
+		u, v := patterns.PlanarMap(tt.point)
+		assert.InDelta(t, tt.u, u, 0.00001, "u at %v", tt.point)
+		assert.InDelta(t, tt.v, v, 0.00001, "v at %v", tt.point)
+	}
+}
+
+func TestCylindricalMapping(t *testing.T) {
+	// Scenario Outline: Using a cylindrical mapping on a 3D point
+	tests := []struct {
+		point tuples.Tuple
+		u, v  float64
+	}{
+		{tuples.Point(0, 0, -1), 0.0, 0.0},
+		{tuples.Point(0, 0.5, -1), 0.0, 0.5},
+		{tuples.Point(0, 1, -1), 0.0, 0.0},
+		{tuples.Point(0.70711, 0.5, -0.70711), 0.125, 0.5},
+		{tuples.Point(1, 0.5, 0), 0.25, 0.5},
+		{tuples.Point(0.70711, 0.5, 0.70711), 0.375, 0.5},
+		{tuples.Point(0, -0.25, 1), 0.5, 0.75},
+		{tuples.Point(-0.70711, 0.5, 0.70711), 0.625, 0.5},
+		{tuples.Point(-1, 1.25, 0), 0.75, 0.25},
+		{tuples.Point(-0.70711, 0.5, -0.70711), 0.875, 0.5},
+	}
+	for _, tt := range tests {
+		u, v := patterns.CylindricalMap(tt.point)
+		assert.InDelta(t, tt.u, u, 0.00001, "u at %v", tt.point)
+		assert.InDelta(t, tt.v, v, 0.00001, "v at %v", tt.point)
+	}
+}
+
+func TestFaceFromPoint(t *testing.T) {
+	// Scenario Outline: Identifying the face of a cube from a point
+	tests := []struct {
+		point tuples.Tuple
+		face  patterns.Face
+	}{
+		{tuples.Point(-1, 0.5, -0.25), patterns.FaceLeft},
+		{tuples.Point(1.1, -0.75, 0.8), patterns.FaceRight},
+		{tuples.Point(0.1, 0.6, 0.9), patterns.FaceFront},
+		{tuples.Point(-0.7, 0, -2), patterns.FaceBack},
+		{tuples.Point(0.5, 1, 0.9), patterns.FaceUp},
+		{tuples.Point(-0.2, -1.3, 1.1), patterns.FaceDown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.face, patterns.FaceFromPoint(tt.point), "face at %v", tt.point)
+	}
+}
+
+func TestCubeMapping(t *testing.T) {
+	// Scenario Outline: UV mapping each face of a cube
+	tests := []struct {
+		name  string
+		point tuples.Tuple
+		face  patterns.Face
+		u, v  float64
+	}{
+		{"front", tuples.Point(-0.5, 0.5, 1), patterns.FaceFront, 0.25, 0.75},
+		{"front", tuples.Point(0.5, -0.5, 1), patterns.FaceFront, 0.75, 0.25},
+		{"back", tuples.Point(0.5, 0.5, -1), patterns.FaceBack, 0.25, 0.75},
+		{"back", tuples.Point(-0.5, -0.5, -1), patterns.FaceBack, 0.75, 0.25},
+		{"left", tuples.Point(-1, 0.5, -0.5), patterns.FaceLeft, 0.25, 0.75},
+		{"left", tuples.Point(-1, -0.5, 0.5), patterns.FaceLeft, 0.75, 0.25},
+		{"right", tuples.Point(1, 0.5, 0.5), patterns.FaceRight, 0.25, 0.75},
+		{"right", tuples.Point(1, -0.5, -0.5), patterns.FaceRight, 0.75, 0.25},
+		{"upper", tuples.Point(-0.5, 1, -0.5), patterns.FaceUp, 0.25, 0.75},
+		{"upper", tuples.Point(0.5, 1, 0.5), patterns.FaceUp, 0.75, 0.25},
+		{"lower", tuples.Point(-0.5, -1, 0.5), patterns.FaceDown, 0.25, 0.75},
+		{"lower", tuples.Point(0.5, -1, -0.5), patterns.FaceDown, 0.75, 0.25},
+	}
+	for _, tt := range tests {
+		face, u, v := patterns.CubeMap(tt.point)
+		assert.Equal(t, tt.face, face, tt.name)
+		assert.InDelta(t, tt.u, u, 0.00001, "%s u at %v", tt.name, tt.point)
+		assert.InDelta(t, tt.v, v, 0.00001, "%s v at %v", tt.name, tt.point)
+	}
+}
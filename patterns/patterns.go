@@ -0,0 +1,49 @@
+// Package patterns colors surfaces by position rather than with a single
+// color.
+package patterns
+
+import (
+	"math"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/tuples"
+)
+
+// Pattern gives the color at a point in pattern space. Its transform places
+// the pattern relative to the object it is applied to.
+type Pattern interface {
+	PatternAt(point tuples.Tuple) tuples.Color
+	GetTransform() matrices.Matrix
+	SetTransform(m matrices.Matrix)
+}
+
+// AtObject returns the color of p at a point in the space of the object it
+// is applied to.
+func AtObject(p Pattern, objectPoint tuples.Tuple) tuples.Color {
+	return p.PatternAt(p.GetTransform().Inverse().MultiplyTuple(objectPoint))
+}
+
+// FilteredPattern is a Pattern that can average its color over the area of
+// the surface seen by a pixel, given as the vectors dx and dy spanning that
+// area from point.
+type FilteredPattern interface {
+	Pattern
+	PatternAtFiltered(point, dx, dy tuples.Tuple) tuples.Color
+}
+
+// AtObjectFiltered returns the color of p at a point in object space,
+// averaged over the area spanned by the object space vectors dx and dy when
+// p is a FilteredPattern.
+func AtObjectFiltered(p Pattern, objectPoint, dx, dy tuples.Tuple) tuples.Color {
+	filtered, ok := p.(FilteredPattern)
+	if !ok {
+		return AtObject(p, objectPoint)
+	}
+	inverse := p.GetTransform().Inverse()
+	return filtered.PatternAtFiltered(
+		inverse.MultiplyTuple(objectPoint), inverse.MultiplyTuple(dx), inverse.MultiplyTuple(dy))
+}
+
+// mod returns a modulo b, wrapped into [0, b) even for negative a.
+func mod(a, b float64) float64 {
+	return a - b*math.Floor(a/b)
+}
@@ -0,0 +1,78 @@
+package patterns
+
+import (
+	"math"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/tuples"
+)
+
+// Marble blends between two colors in veins running across the x axis,
+// waved by noise, usually turbulence. The veins are two units apart.
+type Marble struct {
+	A, B tuples.Color
+	// Noise bends the veins; Distortion scales how far.
+	Noise      noise.Source
+	Distortion float64
+	Transform  matrices.Matrix
+}
+
+func NewMarble(a, b tuples.Color, source noise.Source, distortion float64) *Marble {
+	return &Marble{
+		A: a, B: b,
+		Noise:      source,
+		Distortion: distortion,
+		Transform:  matrices.Identity(matrices.DefaultMatrixSize),
+	}
+}
+
+func (p *Marble) PatternAt(point tuples.Tuple) tuples.Color {
+	const half = 0.5
+	phase := point.X + p.Distortion*p.Noise.At(point)
+	return blend(p.A, p.B, half+half*math.Sin(math.Pi*phase))
+}
+
+func (p *Marble) GetTransform() matrices.Matrix {
+	return p.Transform
+}
+
+func (p *Marble) SetTransform(m matrices.Matrix) {
+	p.Transform = m
+}
+
+// Wood colors rings one unit apart around the y axis, each shading from A at
+// its inner edge to B at its outer edge, with the rings warped by noise.
+type Wood struct {
+	A, B tuples.Color
+	// Noise warps the rings; Distortion scales how far.
+	Noise      noise.Source
+	Distortion float64
+	Transform  matrices.Matrix
+}
+
+func NewWood(a, b tuples.Color, source noise.Source, distortion float64) *Wood {
+	return &Wood{
+		A: a, B: b,
+		Noise:      source,
+		Distortion: distortion,
+		Transform:  matrices.Identity(matrices.DefaultMatrixSize),
+	}
+}
+
+func (p *Wood) PatternAt(point tuples.Tuple) tuples.Color {
+	radius := math.Hypot(point.X, point.Z) + p.Distortion*p.Noise.At(point)
+	return blend(p.A, p.B, mod(radius, 1))
+}
+
+func (p *Wood) GetTransform() matrices.Matrix {
+	return p.Transform
+}
+
+func (p *Wood) SetTransform(m matrices.Matrix) {
+	p.Transform = m
+}
+
+// blend returns the color a fraction t of the way from a to b.
+func blend(a, b tuples.Color, t float64) tuples.Color {
+	return a.Multiply(1 - t).Add(b.Multiply(t))
+}
@@ -0,0 +1,79 @@
+package patterns_test
+
+import (
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flat is noise with the same value everywhere.
+type flat float64
+
+func (f flat) At(tuples.Tuple) float64 {
+	return float64(f)
+}
+
+func TestMarbleVeins(t *testing.T) {
+	// Scenario Outline: Marble blends between its colors across x
+	// Given pattern ← marble(black, white, flat noise 0, distortion 1)
+	// Then pattern_at(pattern, <point>) = <color>
+	black := tuples.NewColor(0, 0, 0)
+	white := tuples.NewColor(1, 1, 1)
+	pattern := patterns.NewMarble(black, white, flat(0), 1)
+	tests := []struct {
+		point    tuples.Tuple
+		expected tuples.Color
+	}{
+		{tuples.Point(0, 0, 0), tuples.NewColor(0.5, 0.5, 0.5)},
+		{tuples.Point(0.5, 3, -2), white},
+		{tuples.Point(1, 0, 0), tuples.NewColor(0.5, 0.5, 0.5)},
+		{tuples.Point(1.5, 0, 7), black},
+		{tuples.Point(2.5, -1, 0), white},
+	}
+	for _, tt := range tests {
+		assert.True(t, tt.expected.Equals(pattern.PatternAt(tt.point).Tuple), "at %v", tt.point)
+	}
+}
+
+func TestMarbleIsDistortedByNoise(t *testing.T) {
+	// Scenario: Noise shifts marble's veins
+	// Given pattern ← marble(black, white, flat noise 0.25, distortion 2)
+	// Then the vein at x = 0 is where it would be at x = 0.5 without noise
+	pattern := patterns.NewMarble(tuples.NewColor(0, 0, 0), tuples.NewColor(1, 1, 1), flat(0.25), 2)
+	assert.True(t, tuples.NewColor(1, 1, 1).Equals(pattern.PatternAt(tuples.Point(0, 0, 0)).Tuple))
+}
+
+func TestWoodRings(t *testing.T) {
+	// Scenario Outline: Wood shades each ring around the y axis from A to B
+	// Given pattern ← wood(black, white, flat noise 0, distortion 1)
+	// Then pattern_at(pattern, <point>) = <color>
+	pattern := patterns.NewWood(tuples.NewColor(0, 0, 0), tuples.NewColor(1, 1, 1), flat(0), 1)
+	tests := []struct {
+		point    tuples.Tuple
+		expected tuples.Color
+	}{
+		{tuples.Point(0, 0, 0), tuples.NewColor(0, 0, 0)},
+		{tuples.Point(0.25, 5, 0), tuples.NewColor(0.25, 0.25, 0.25)},
+		{tuples.Point(0, -1, 1.5), tuples.NewColor(0.5, 0.5, 0.5)},
+		{tuples.Point(0.6, 0, 0.8), tuples.NewColor(0, 0, 0)},
+		{tuples.Point(1.8, 0, 2.4), tuples.NewColor(0, 0, 0)},
+	}
+	for _, tt := range tests {
+		assert.True(t, tt.expected.Equals(pattern.PatternAt(tt.point).Tuple), "at %v", tt.point)
+	}
+}
+
+func TestProceduralPatternsWithTransform(t *testing.T) {
+	// Scenario: Procedural patterns are placed by their transform
+	// Given pattern ← wood(black, white, perlin(1), distortion 0)
+	// And set_pattern_transform(pattern, scaling(2, 2, 2))
+	// Then pattern_at_object(pattern, point(0.5, 0, 0)) = color(0.25, 0.25, 0.25)
+	pattern := patterns.NewWood(tuples.NewColor(0, 0, 0), tuples.NewColor(1, 1, 1), noise.NewPerlin(1), 0)
+	pattern.SetTransform(matrices.Scaling(2, 2, 2))
+	assert.True(t, tuples.NewColor(0.25, 0.25, 0.25).Equals(
+		patterns.AtObject(pattern, tuples.Point(0.5, 0, 0)).Tuple))
+}
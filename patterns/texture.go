@@ -0,0 +1,112 @@
+package patterns
+
+import (
+	"math"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/tuples"
+)
+
+// TextureMap wraps a UV pattern around a surface using a mapping.
+type TextureMap struct {
+	UVPattern UVPattern
+	Mapping   Mapping
+	Transform matrices.Matrix
+}
+
+func NewTextureMap(uvPattern UVPattern, mapping Mapping) *TextureMap {
+	return &TextureMap{
+		UVPattern: uvPattern,
+		Mapping:   mapping,
+		Transform: matrices.Identity(matrices.DefaultMatrixSize),
+	}
+}
+
+func (p *TextureMap) PatternAt(point tuples.Tuple) tuples.Color {
+	u, v := p.Mapping(point)
+	return p.UVPattern.UVPatternAt(u, v)
+}
+
+// PatternAtFiltered averages the UV pattern over the texture coordinates
+// covered by dx and dy, when it is a FilteredUVPattern.
+func (p *TextureMap) PatternAtFiltered(point, dx, dy tuples.Tuple) tuples.Color {
+	filtered, ok := p.UVPattern.(FilteredUVPattern)
+	if !ok {
+		return p.PatternAt(point)
+	}
+	u, v := p.Mapping(point)
+	return filtered.UVPatternFiltered(u, v, uvFootprint(p.Mapping, point, dx, dy))
+}
+
+func (p *TextureMap) GetTransform() matrices.Matrix {
+	return p.Transform
+}
+
+func (p *TextureMap) SetTransform(m matrices.Matrix) {
+	p.Transform = m
+}
+
+// CubeTexture applies a separate UV pattern to each face of a cube, indexed
+// by Face.
+type CubeTexture struct {
+	Faces     [faceCount]UVPattern
+	Transform matrices.Matrix
+}
+
+func NewCubeTexture(left, front, right, back, up, down UVPattern) *CubeTexture {
+	c := &CubeTexture{Transform: matrices.Identity(matrices.DefaultMatrixSize)}
+	c.Faces[FaceLeft] = left
+	c.Faces[FaceFront] = front
+	c.Faces[FaceRight] = right
+	c.Faces[FaceBack] = back
+	c.Faces[FaceUp] = up
+	c.Faces[FaceDown] = down
+	return c
+}
+
+func (p *CubeTexture) PatternAt(point tuples.Tuple) tuples.Color {
+	face, u, v := CubeMap(point)
+	return p.Faces[face].UVPatternAt(u, v)
+}
+
+// PatternAtFiltered averages the pattern of the face containing point over
+// the texture coordinates covered by dx and dy, when it is a
+// FilteredUVPattern.
+func (p *CubeTexture) PatternAtFiltered(point, dx, dy tuples.Tuple) tuples.Color {
+	face, u, v := CubeMap(point)
+	filtered, ok := p.Faces[face].(FilteredUVPattern)
+	if !ok {
+		return p.Faces[face].UVPatternAt(u, v)
+	}
+	mapping := func(q tuples.Tuple) (float64, float64) {
+		_, fu, fv := CubeMap(q)
+		return fu, fv
+	}
+	return filtered.UVPatternFiltered(u, v, uvFootprint(mapping, point, dx, dy))
+}
+
+func (p *CubeTexture) GetTransform() matrices.Matrix {
+	return p.Transform
+}
+
+func (p *CubeTexture) SetTransform(m matrices.Matrix) {
+	p.Transform = m
+}
+
+// uvFootprint returns the larger of the distances in texture space from the
+// coordinates of point to those of point offset by dx and by dy. Distances
+// are measured across the seams where coordinates wrap from 1 back to 0.
+func uvFootprint(mapping Mapping, point, dx, dy tuples.Tuple) float64 {
+	u, v := mapping(point)
+	footprint := 0.0
+	for _, d := range []tuples.Tuple{dx, dy} {
+		du, dv := mapping(point.Add(d))
+		footprint = max(footprint, math.Hypot(seamDistance(du-u), seamDistance(dv-v)))
+	}
+	return footprint
+}
+
+// seamDistance returns the shortest distance between two texture
+// coordinates d apart, going across the seam if that is shorter.
+func seamDistance(d float64) float64 {
+	return math.Abs(d - math.Round(d))
+}
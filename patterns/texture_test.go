@@ -0,0 +1,182 @@
+package patterns_test
+
+import (
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextureMapWithSphericalMap(t *testing.T) {
+	// Scenario Outline: Using a texture map pattern with a spherical map
+	// Given checkers ← uv_checkers(16, 8, black, white)
+	// And pattern ← texture_map(checkers, spherical_map)
+	// Then pattern_at(pattern, <point>) = <color>
+	black := tuples.NewColor(0, 0, 0)
+	white := tuples.NewColor(1, 1, 1)
+	pattern := patterns.NewTextureMap(patterns.NewUVCheckers(16, 8, black, white), patterns.SphericalMap)
+	tests := []struct {
+		point    tuples.Tuple
+		expected tuples.Color
+	}{
+		{tuples.Point(0.4315, 0.4670, 0.7719), white},
+		{tuples.Point(-0.9654, 0.2552, -0.0534), black},
+		{tuples.Point(0.1039, 0.7090, 0.6975), white},
+		{tuples.Point(-0.4986, -0.7856, -0.3663), black},
+		{tuples.Point(-0.0317, -0.9395, 0.3411), black},
+		{tuples.Point(0.4809, -0.7721, 0.4154), black},
+		{tuples.Point(0.0285, -0.9612, -0.2745), black},
+		{tuples.Point(-0.5734, -0.2162, -0.7903), white},
+		{tuples.Point(0.7688, -0.1470, 0.6223), black},
+		{tuples.Point(-0.7652, 0.2175, 0.6060), black},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, pattern.PatternAt(tt.point), "at %v", tt.point)
+	}
+}
+
+func TestPatternTransformAppliesToObjectPoint(t *testing.T) {
+	// Scenario: A pattern's transform moves it over the object
+	// Given pattern ← texture_map(uv_checkers(2, 2, black, white), planar_map)
+	// And set_pattern_transform(pattern, scaling(2, 2, 2))
+	// Then pattern_at_object(pattern, point(1.5, 0, 0)) = white
+	// And pattern_at_object(pattern, point(2.5, 0, 0)) = black
+	black := tuples.NewColor(0, 0, 0)
+	white := tuples.NewColor(1, 1, 1)
+	pattern := patterns.NewTextureMap(patterns.NewUVCheckers(2, 2, black, white), patterns.PlanarMap)
+	assert.Equal(t, white, patterns.AtObject(pattern, tuples.Point(0.75, 0, 0)))
+	pattern.SetTransform(matrices.Scaling(2, 2, 2))
+	assert.Equal(t, white, patterns.AtObject(pattern, tuples.Point(1.5, 0, 0)))
+	assert.Equal(t, black, patterns.AtObject(pattern, tuples.Point(2.5, 0, 0)))
+}
+
+func TestCubeTexture(t *testing.T) {
+	// Scenario Outline: Finding the colors on a mapped cube
+	// Given each face has an align check pattern with its own main color
+	// And the corner colors red, yellow, brown, green, cyan, blue, purple and white
+	// When pattern ← cube_map(left, front, right, back, up, down)
+	// Then pattern_at(pattern, <point>) = <color>
+	red := tuples.NewColor(1, 0, 0)
+	yellow := tuples.NewColor(1, 1, 0)
+	brown := tuples.NewColor(1, 0.5, 0)
+	green := tuples.NewColor(0, 1, 0)
+	cyan := tuples.NewColor(0, 1, 1)
+	blue := tuples.NewColor(0, 0, 1)
+	purple := tuples.NewColor(1, 0, 1)
+	white := tuples.NewColor(1, 1, 1)
+	left := patterns.NewUVAlignCheck(yellow, cyan, red, blue, brown)
+	front := patterns.NewUVAlignCheck(cyan, red, yellow, brown, green)
+	right := patterns.NewUVAlignCheck(red, yellow, purple, green, white)
+	back := patterns.NewUVAlignCheck(green, purple, cyan, white, blue)
+	up := patterns.NewUVAlignCheck(brown, cyan, purple, red, yellow)
+	down := patterns.NewUVAlignCheck(purple, brown, green, blue, white)
+	pattern := patterns.NewCubeTexture(left, front, right, back, up, down)
+	tests := []struct {
+		name     string
+		point    tuples.Tuple
+		expected tuples.Color
+	}{
+		{"left", tuples.Point(-1, 0, 0), yellow},
+		{"left", tuples.Point(-1, 0.9, -0.9), cyan},
+		{"left", tuples.Point(-1, 0.9, 0.9), red},
+		{"left", tuples.Point(-1, -0.9, -0.9), blue},
+		{"left", tuples.Point(-1, -0.9, 0.9), brown},
+		{"front", tuples.Point(0, 0, 1), cyan},
+		{"front", tuples.Point(-0.9, 0.9, 1), red},
+		{"front", tuples.Point(0.9, 0.9, 1), yellow},
+		{"front", tuples.Point(-0.9, -0.9, 1), brown},
+		{"front", tuples.Point(0.9, -0.9, 1), green},
+		{"right", tuples.Point(1, 0, 0), red},
+		{"right", tuples.Point(1, 0.9, 0.9), yellow},
+		{"right", tuples.Point(1, 0.9, -0.9), purple},
+		{"right", tuples.Point(1, -0.9, 0.9), green},
+		{"right", tuples.Point(1, -0.9, -0.9), white},
+		{"back", tuples.Point(0, 0, -1), green},
+		{"back", tuples.Point(0.9, 0.9, -1), purple},
+		{"back", tuples.Point(-0.9, 0.9, -1), cyan},
+		{"back", tuples.Point(0.9, -0.9, -1), white},
+		{"back", tuples.Point(-0.9, -0.9, -1), blue},
+		{"up", tuples.Point(0, 1, 0), brown},
+		{"up", tuples.Point(-0.9, 1, -0.9), cyan},
+		{"up", tuples.Point(0.9, 1, -0.9), purple},
+		{"up", tuples.Point(-0.9, 1, 0.9), red},
+		{"up", tuples.Point(0.9, 1, 0.9), yellow},
+		{"down", tuples.Point(0, -1, 0), purple},
+		{"down", tuples.Point(-0.9, -1, 0.9), brown},
+		{"down", tuples.Point(0.9, -1, 0.9), green},
+		{"down", tuples.Point(-0.9, -1, -0.9), blue},
+		{"down", tuples.Point(0.9, -1, -0.9), white},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, pattern.PatternAt(tt.point), "%s at %v", tt.name, tt.point)
+	}
+}
+
+// footprintPattern reports the coordinates and footprint it is sampled with
+// as a color.
+type footprintPattern struct{}
+
+func (footprintPattern) UVPatternAt(u, v float64) tuples.Color {
+	return tuples.NewColor(u, v, 0)
+}
+
+func (footprintPattern) UVPatternFiltered(u, v, footprint float64) tuples.Color {
+	return tuples.NewColor(u, v, footprint)
+}
+
+func TestTextureMapFootprint(t *testing.T) {
+	// Scenario Outline: A texture map measures the footprint in texture space
+	// Given pattern ← texture_map(footprint_pattern, planar_map)
+	// And set_pattern_transform(pattern, scaling(2, 2, 2))
+	// When color ← pattern_at_object_filtered(pattern, <point>, <dx>, <dy>)
+	// Then color = color(u, v, footprint) = <expected>
+	pattern := patterns.NewTextureMap(footprintPattern{}, patterns.PlanarMap)
+	pattern.SetTransform(matrices.Scaling(2, 2, 2))
+	tests := []struct {
+		name      string
+		point, dx tuples.Tuple
+		dy        tuples.Tuple
+		expected  tuples.Color
+	}{
+		{
+			name:     "the larger offset wins",
+			point:    tuples.Point(0.5, 0, 1),
+			dx:       tuples.Vector(0.2, 0, 0),
+			dy:       tuples.Vector(0, 0, 0.6),
+			expected: tuples.NewColor(0.25, 0.5, 0.3),
+		},
+		{
+			name:     "offsets across the seam are short",
+			point:    tuples.Point(1.9, 0, 1),
+			dx:       tuples.Vector(0.2, 0, 0),
+			dy:       tuples.Vector(0, 0, 0),
+			expected: tuples.NewColor(0.95, 0.5, 0.1),
+		},
+		{
+			name:     "diagonal offsets",
+			point:    tuples.Point(0.5, 0, 1),
+			dx:       tuples.Vector(0.6, 0, 0.8),
+			dy:       tuples.Vector(0, 0, 0),
+			expected: tuples.NewColor(0.25, 0.5, 0.5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color := patterns.AtObjectFiltered(pattern, tt.point, tt.dx, tt.dy)
+			assert.True(t, tt.expected.Equals(color.Tuple), "got %v", color)
+		})
+	}
+}
+
+func TestUnfilteredPatternIgnoresFootprint(t *testing.T) {
+	// Scenario: Patterns that cannot be filtered are sampled at the point
+	// Given pattern ← texture_map(uv_checkers(2, 2, black, white), planar_map)
+	// Then pattern_at_object_filtered(pattern, point(0.75, 0, 0), vector(1, 0, 0), vector(0, 0, 1)) = white
+	black := tuples.NewColor(0, 0, 0)
+	white := tuples.NewColor(1, 1, 1)
+	pattern := patterns.NewTextureMap(patterns.NewUVCheckers(2, 2, black, white), patterns.PlanarMap)
+	color := patterns.AtObjectFiltered(pattern, tuples.Point(0.75, 0, 0), tuples.Vector(1, 0, 0), tuples.Vector(0, 0, 1))
+	assert.Equal(t, white, color)
+}
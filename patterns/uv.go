@@ -0,0 +1,76 @@
+package patterns
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// UVPattern gives the color at texture coordinates (u, v), both in [0, 1].
+type UVPattern interface {
+	UVPatternAt(u, v float64) tuples.Color
+}
+
+// FilteredUVPattern is a UVPattern that can average its color over a square
+// of the texture, footprint wide, which keeps it from aliasing where a pixel
+// of the image covers much of the texture.
+type FilteredUVPattern interface {
+	UVPattern
+	UVPatternFiltered(u, v, footprint float64) tuples.Color
+}
+
+// UVCheckers is a checkerboard of Width by Height squares.
+type UVCheckers struct {
+	Width  float64
+	Height float64
+	A      tuples.Color
+	B      tuples.Color
+}
+
+func NewUVCheckers(width, height float64, a, b tuples.Color) UVCheckers {
+	return UVCheckers{Width: width, Height: height, A: a, B: b}
+}
+
+func (p UVCheckers) UVPatternAt(u, v float64) tuples.Color {
+	const two = 2
+	u2 := math.Floor(u * p.Width)
+	v2 := math.Floor(v * p.Height)
+	if mod(u2+v2, two) == 0 {
+		return p.A
+	}
+	return p.B
+}
+
+// UVAlignCheck is a solid color with a differently colored square in each
+// corner, which shows how a texture is oriented on a surface.
+type UVAlignCheck struct {
+	Main        tuples.Color
+	UpperLeft   tuples.Color
+	UpperRight  tuples.Color
+	BottomLeft  tuples.Color
+	BottomRight tuples.Color
+}
+
+func NewUVAlignCheck(main, upperLeft, upperRight, bottomLeft, bottomRight tuples.Color) UVAlignCheck {
+	return UVAlignCheck{
+		Main:        main,
+		UpperLeft:   upperLeft,
+		UpperRight:  upperRight,
+		BottomLeft:  bottomLeft,
+		BottomRight: bottomRight,
+	}
+}
+
+func (p UVAlignCheck) UVPatternAt(u, v float64) tuples.Color {
+	const low, high = 0.2, 0.8
+	switch {
+	case v > high && u < low:
+		return p.UpperLeft
+	case v > high && u > high:
+		return p.UpperRight
+	case v < low && u < low:
+		return p.BottomLeft
+	case v < low && u > high:
+		return p.BottomRight
+	}
+	return p.Main
+}
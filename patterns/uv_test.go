@@ -0,0 +1,55 @@
+package patterns_test
+
+import (
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUVCheckers(t *testing.T) {
+	// Scenario Outline: Checker pattern in 2D
+	// Given checkers ← uv_checkers(2, 2, black, white)
+	// When color ← uv_pattern_at(checkers, <u>, <v>)
+	// Then color = <expected>
+	black := tuples.NewColor(0, 0, 0)
+	white := tuples.NewColor(1, 1, 1)
+	checkers := patterns.NewUVCheckers(2, 2, black, white)
+	tests := []struct {
+		u, v     float64
+		expected tuples.Color
+	}{
+		{0.0, 0.0, black},
+		{0.5, 0.0, white},
+		{0.0, 0.5, white},
+		{0.5, 0.5, black},
+		{1.0, 1.0, black},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, checkers.UVPatternAt(tt.u, tt.v), "(%v, %v)", tt.u, tt.v)
+	}
+}
+
+func TestUVAlignCheck(t *testing.T) {
+	// Scenario Outline: Layout of the "align check" pattern
+	main := tuples.NewColor(1, 1, 1)
+	ul := tuples.NewColor(1, 0, 0)
+	ur := tuples.NewColor(1, 1, 0)
+	bl := tuples.NewColor(0, 1, 0)
+	br := tuples.NewColor(0, 1, 1)
+	pattern := patterns.NewUVAlignCheck(main, ul, ur, bl, br)
+	tests := []struct {
+		u, v     float64
+		expected tuples.Color
+	}{
+		{0.5, 0.5, main},
+		{0.1, 0.9, ul},
+		{0.9, 0.9, ur},
+		{0.1, 0.1, bl},
+		{0.9, 0.1, br},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, pattern.UVPatternAt(tt.u, tt.v), "(%v, %v)", tt.u, tt.v)
+	}
+}
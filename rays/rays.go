@@ -7,6 +7,13 @@ import (
 
 type Ray struct {
 	Origin, Direction tuples.Tuple
+	// Spread is how much wider the ray grows per unit it travels, so that it
+	// approximates the cone of space seen by a pixel. Zero is an infinitely
+	// thin ray.
+	Spread float64
+	// Time is when the ray is cast, for shapes that move while the camera's
+	// shutter is open.
+	Time float64
 }
 
 func New(origin, direction tuples.Tuple) Ray {
@@ -17,6 +24,9 @@ func (r Ray) Position(t float64) tuples.Tuple {
 	return r.Origin.Add(r.Direction.Multiply(t))
 }
 
+// Transform returns the ray transformed by m, keeping its spread and time.
 func (r Ray) Transform(m matrices.Matrix) Ray {
-	return New(m.MultiplyTuple(r.Origin), m.MultiplyTuple(r.Direction))
+	r.Origin = m.MultiplyTuple(r.Origin)
+	r.Direction = m.MultiplyTuple(r.Direction)
+	return r
 }
@@ -44,3 +44,13 @@ func TestRayScaling(t *testing.T) {
 	assert.True(t, tuples.Point(2, 6, 12).Equals(r2.Origin))
 	assert.True(t, tuples.Vector(0, 3, 0).Equals(r2.Direction))
 }
+
+func TestRayTransformKeepsSpreadAndTime(t *testing.T) {
+	// Scenario: Transforming a ray keeps its spread and time
+	r := rays.New(tuples.Point(1, 2, 3), tuples.Vector(0, 1, 0))
+	r.Spread = 0.01
+	r.Time = 0.3
+	r2 := r.Transform(matrices.Scaling(2, 3, 4))
+	assert.InEpsilon(t, 0.01, r2.Spread, 0.00001)
+	assert.InEpsilon(t, 0.3, r2.Time, 0.00001)
+}
@@ -0,0 +1,81 @@
+package sampling
+
+import (
+	"math"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/tuples"
+)
+
+// Sample is the color seen through a point of the image, given in pixels
+// from the image's top left corner.
+type Sample struct {
+	X, Y  float64
+	Color tuples.Color
+}
+
+// Film reconstructs an image from samples. Each sample is spread over every
+// pixel whose center lies within the filter's radius, weighted by the filter,
+// and each pixel ends up as the weighted average of the samples it received.
+//
+// Film is not safe for concurrent use; adding the same samples in the same
+// order always gives the same image.
+type Film struct {
+	width   int
+	height  int
+	filter  Filter
+	sums    []tuples.Color
+	weights []float64
+}
+
+func NewFilm(width, height int, filter Filter) *Film {
+	sums := make([]tuples.Color, width*height)
+	for i := range sums {
+		sums[i] = tuples.NewColor(0, 0, 0)
+	}
+	return &Film{
+		width:   width,
+		height:  height,
+		filter:  filter,
+		sums:    sums,
+		weights: make([]float64, width*height),
+	}
+}
+
+func (f *Film) AddSample(s Sample) {
+	const half = 0.5
+	r := f.filter.Radius()
+	// Pixel x has its center at x + 0.5.
+	x0 := max(0, int(math.Ceil(s.X-half-r)))
+	x1 := min(f.width-1, int(math.Floor(s.X-half+r)))
+	y0 := max(0, int(math.Ceil(s.Y-half-r)))
+	y1 := min(f.height-1, int(math.Floor(s.Y-half+r)))
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			weight := f.filter.Evaluate(s.X-(float64(x)+half), s.Y-(float64(y)+half))
+			if weight == 0 {
+				continue
+			}
+			i := y*f.width + x
+			f.sums[i] = f.sums[i].Add(s.Color.Multiply(weight))
+			f.weights[i] += weight
+		}
+	}
+}
+
+// Canvas returns the reconstructed image. Pixels that received no weight are
+// black, as are those whose weights sum to zero or less, which filters with
+// negative lobes such as Mitchell's can leave at sparsely sampled borders;
+// dividing by such a sum would flip the pixel's sign or blow it up.
+func (f *Film) Canvas() *canvas.Canvas {
+	image := canvas.NewCanvas(f.width, f.height)
+	for y := range f.height {
+		for x := range f.width {
+			i := y*f.width + x
+			if f.weights[i] <= 0 {
+				continue
+			}
+			image.WritePixel(x, y, f.sums[i].Multiply(1/f.weights[i]))
+		}
+	}
+	return image
+}
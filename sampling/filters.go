@@ -0,0 +1,119 @@
+package sampling
+
+import "math"
+
+// Filter weights a sample by its offset (dx, dy) from a pixel center, in
+// pixels. Samples further than Radius from the center in either direction
+// have no weight.
+type Filter interface {
+	Radius() float64
+	Evaluate(dx, dy float64) float64
+}
+
+// Box weights every sample inside the pixel equally, which averages them.
+type Box struct{}
+
+func NewBox() Box {
+	return Box{}
+}
+
+func (Box) Radius() float64 {
+	const half = 0.5
+	return half
+}
+
+// Evaluate treats the pixel as half open, so a sample on the edge between two
+// pixels only counts towards one of them.
+func (b Box) Evaluate(dx, dy float64) float64 {
+	r := b.Radius()
+	if dx < -r || dx >= r || dy < -r || dy >= r {
+		return 0
+	}
+	return 1
+}
+
+// Tent weights samples linearly less the further they are from the center.
+type Tent struct {
+	R float64
+}
+
+// NewTent returns a tent filter reaching the centers of the neighboring pixels.
+func NewTent() Tent {
+	return Tent{R: 1}
+}
+
+func (f Tent) Radius() float64 {
+	return f.R
+}
+
+func (f Tent) Evaluate(dx, dy float64) float64 {
+	return tent(dx, f.R) * tent(dy, f.R)
+}
+
+// Gaussian weights samples by a Gaussian bell, shifted down so that it falls
+// to zero at the radius.
+type Gaussian struct {
+	R float64
+	// Alpha is the falloff of the bell; larger values give a sharper image.
+	Alpha float64
+}
+
+func NewGaussian() Gaussian {
+	const radius, alpha = 1.5, 2
+	return Gaussian{R: radius, Alpha: alpha}
+}
+
+func (f Gaussian) Radius() float64 {
+	return f.R
+}
+
+func (f Gaussian) Evaluate(dx, dy float64) float64 {
+	return f.gaussian(dx) * f.gaussian(dy)
+}
+
+func (f Gaussian) gaussian(d float64) float64 {
+	edge := math.Exp(-f.Alpha * f.R * f.R)
+	return max(0, math.Exp(-f.Alpha*d*d)-edge)
+}
+
+// Mitchell is the Mitchell-Netravali cubic filter. Its small negative lobes
+// keep edges sharper than the other filters, at the cost of slight ringing.
+type Mitchell struct {
+	R float64
+	B float64
+	C float64
+}
+
+// NewMitchell returns the filter with the parameters B = C = 1/3 recommended
+// by Mitchell and Netravali.
+func NewMitchell() Mitchell {
+	const radius, third = 2, 1.0 / 3
+	return Mitchell{R: radius, B: third, C: third}
+}
+
+func (f Mitchell) Radius() float64 {
+	return f.R
+}
+
+func (f Mitchell) Evaluate(dx, dy float64) float64 {
+	return f.mitchell(dx) * f.mitchell(dy)
+}
+
+// mitchell evaluates the one-dimensional cubic, which is defined on [-2, 2].
+// nolint: mnd // coefficients of the Mitchell-Netravali polynomials
+func (f Mitchell) mitchell(d float64) float64 {
+	x := math.Abs(2 * d / f.R)
+	b, c := f.B, f.C
+	switch {
+	case x > 2:
+		return 0
+	case x > 1:
+		return ((-b-6*c)*x*x*x + (6*b+30*c)*x*x + (-12*b-48*c)*x + (8*b + 24*c)) / 6
+	default:
+		return ((12-9*b-6*c)*x*x*x + (-18+12*b+6*c)*x*x + (6 - 2*b)) / 6
+	}
+}
+
+func tent(d, r float64) float64 {
+	return max(0, 1-math.Abs(d)/r)
+}
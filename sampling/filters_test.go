@@ -0,0 +1,98 @@
+package sampling_test
+
+import (
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoxFilterCoversHalfOpenPixel(t *testing.T) {
+	// Scenario: A box filter weights samples inside the pixel equally
+	f := sampling.NewBox()
+	assert.InDelta(t, 0.5, f.Radius(), 0.00001)
+	assert.InDelta(t, 1.0, f.Evaluate(0, 0), 0.00001)
+	assert.InDelta(t, 1.0, f.Evaluate(-0.5, 0.49), 0.00001)
+	assert.InDelta(t, 0.0, f.Evaluate(0.5, 0), 0.00001)
+	assert.InDelta(t, 0.0, f.Evaluate(0, -0.6), 0.00001)
+}
+
+func TestTentFilterFallsOffLinearly(t *testing.T) {
+	// Scenario: A tent filter weights samples less the further they are from the center
+	f := sampling.NewTent()
+	assert.InDelta(t, 1.0, f.Evaluate(0, 0), 0.00001)
+	assert.InDelta(t, 0.5, f.Evaluate(0.5, 0), 0.00001)
+	assert.InDelta(t, 0.25, f.Evaluate(0.5, -0.5), 0.00001)
+	assert.InDelta(t, 0.0, f.Evaluate(1, 0), 0.00001)
+}
+
+func TestGaussianFilterFallsToZeroAtRadius(t *testing.T) {
+	// Scenario: A Gaussian filter is highest at the center and zero at its radius
+	f := sampling.NewGaussian()
+	assert.Greater(t, f.Evaluate(0, 0), f.Evaluate(0.5, 0))
+	assert.Greater(t, f.Evaluate(0.5, 0), 0.0)
+	assert.InDelta(t, 0.0, f.Evaluate(f.Radius(), 0), 0.00001)
+	assert.InDelta(t, 0.0, f.Evaluate(0, 2), 0.00001)
+}
+
+func TestMitchellFilter(t *testing.T) {
+	// Scenario: The Mitchell filter has a positive center and small negative lobes
+	f := sampling.NewMitchell()
+	assert.InDelta(t, 2.0, f.Radius(), 0.00001)
+	assert.InDelta(t, 8.0/9*8.0/9, f.Evaluate(0, 0), 0.00001)
+	assert.InDelta(t, 1.0/18*8.0/9, f.Evaluate(1, 0), 0.00001)
+	assert.Less(t, f.Evaluate(1.5, 0), 0.0)
+	assert.InDelta(t, 0.0, f.Evaluate(2, 0), 0.00001)
+}
+
+func TestFilmWithBoxFilterAveragesSamplesInPixel(t *testing.T) {
+	// Scenario: A film with a box filter averages the samples inside each pixel
+	film := sampling.NewFilm(2, 1, sampling.NewBox())
+	film.AddSample(sampling.Sample{X: 0.25, Y: 0.5, Color: tuples.NewColor(1, 0, 0)})
+	film.AddSample(sampling.Sample{X: 0.75, Y: 0.5, Color: tuples.NewColor(0, 0, 1)})
+	film.AddSample(sampling.Sample{X: 1.5, Y: 0.5, Color: tuples.NewColor(0, 1, 0)})
+	image := film.Canvas()
+	assert.True(t, tuples.NewColor(0.5, 0, 0.5).Equals(image.PixelAt(0, 0).Tuple))
+	assert.True(t, tuples.NewColor(0, 1, 0).Equals(image.PixelAt(1, 0).Tuple))
+}
+
+func TestFilmSpreadsSamplesOverNeighbors(t *testing.T) {
+	// Scenario: A wide filter spreads a sample over the neighboring pixels
+	// Given a 3x1 film with a tent filter
+	// When a white sample is added to the middle pixel, a quarter pixel right of its center
+	// And a black sample is added at the center of each pixel
+	// Then the right pixel receives more of the white sample than the left one
+	film := sampling.NewFilm(3, 1, sampling.NewTent())
+	film.AddSample(sampling.Sample{X: 1.75, Y: 0.5, Color: tuples.NewColor(1, 1, 1)})
+	for x := range 3 {
+		film.AddSample(sampling.Sample{X: float64(x) + 0.5, Y: 0.5, Color: tuples.NewColor(0, 0, 0)})
+	}
+	image := film.Canvas()
+	assert.InDelta(t, 0.0, image.PixelAt(0, 0).Red(), 0.00001)
+	assert.InDelta(t, 0.75/1.75, image.PixelAt(1, 0).Red(), 0.00001)
+	assert.InDelta(t, 0.25/1.25, image.PixelAt(2, 0).Red(), 0.00001)
+}
+
+func TestFilmLeavesUnsampledPixelsBlack(t *testing.T) {
+	// Scenario: Pixels that receive no samples are black
+	film := sampling.NewFilm(2, 2, sampling.NewBox())
+	film.AddSample(sampling.Sample{X: 0.5, Y: 0.5, Color: tuples.NewColor(1, 1, 1)})
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(film.Canvas().PixelAt(1, 1).Tuple))
+}
+
+func TestFilmBlacksOutPixelsWithoutPositiveWeight(t *testing.T) {
+	// Scenario: Pixels sampled only in a filter's negative lobes are black
+	// Given a 1x1 film with a Mitchell filter
+	// When a white sample is added 1.5 pixels from its center, beyond its edge
+	// And a black sample is added 1.1 pixels from its center
+	// Then their weights sum to less than zero
+	// And the pixel is black rather than brighter than white
+	f := sampling.NewMitchell()
+	require.Negative(t, f.Evaluate(-1.5, 0)+f.Evaluate(-1.1, 0))
+	film := sampling.NewFilm(1, 1, f)
+	film.AddSample(sampling.Sample{X: 2, Y: 0.5, Color: tuples.NewColor(1, 1, 1)})
+	film.AddSample(sampling.Sample{X: 1.6, Y: 0.5, Color: tuples.NewColor(0, 0, 0)})
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(film.Canvas().PixelAt(0, 0).Tuple))
+}
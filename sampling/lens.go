@@ -0,0 +1,38 @@
+package sampling
+
+import "math"
+
+// lensSeed seeds the shuffle of lens samples.
+const lensSeed = 0x6c656e73
+
+// Shuffled returns a copy of points in an order that depends only on the
+// pixel. Pairing each sample of a pixel with a shuffled sample of the same
+// pixel gives a second set of evenly spread positions, such as points on a
+// lens, that are independent of the first.
+func Shuffled(points []Point, px, py int) []Point {
+	shuffled := make([]Point, len(points))
+	copy(shuffled, points)
+	rng := pixelRand(lensSeed, px, py)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// ConcentricDisk maps p from the unit square onto the disk of radius 1
+// centered on the origin, keeping evenly spread points evenly spread. The
+// center of the square maps to the center of the disk.
+func ConcentricDisk(p Point) (float64, float64) {
+	const quarter = math.Pi / 4
+	x, y := 2*p.X-1, 2*p.Y-1 // nolint: mnd // maps [0, 1) onto [-1, 1)
+	if x == 0 && y == 0 {
+		return 0, 0
+	}
+	var r, theta float64
+	if math.Abs(x) > math.Abs(y) {
+		r, theta = x, quarter*(y/x)
+	} else {
+		r, theta = y, math.Pi/2-quarter*(x/y) // nolint: mnd // a quarter turn
+	}
+	return r * math.Cos(theta), r * math.Sin(theta)
+}
@@ -0,0 +1,56 @@
+package sampling_test
+
+import (
+	"math"
+	"raytracer-vibe/sampling"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcentricDisk(t *testing.T) {
+	// Scenario Outline: Mapping points of the unit square onto the unit disk
+	// Then concentric_disk(<point>) = <x>, <y>
+	tests := []struct {
+		point sampling.Point
+		x, y  float64
+	}{
+		{sampling.Point{X: 0.5, Y: 0.5}, 0, 0},
+		{sampling.Point{X: 1, Y: 0.5}, 1, 0},
+		{sampling.Point{X: 0.5, Y: 0}, 0, -1},
+		{sampling.Point{X: 0, Y: 0.5}, -1, 0},
+		{sampling.Point{X: 1, Y: 1}, math.Sqrt2 / 2, math.Sqrt2 / 2},
+		{sampling.Point{X: 0.75, Y: 0.5}, 0.5, 0},
+	}
+	for _, tt := range tests {
+		x, y := sampling.ConcentricDisk(tt.point)
+		assert.InDelta(t, tt.x, x, 1e-9, "x of %v", tt.point)
+		assert.InDelta(t, tt.y, y, 1e-9, "y of %v", tt.point)
+	}
+}
+
+func TestConcentricDiskStaysInsideDisk(t *testing.T) {
+	// Scenario: Every sample of the square lands inside the disk
+	for _, p := range sampling.NewRegular().Samples(0, 0, 100) {
+		x, y := sampling.ConcentricDisk(p)
+		assert.LessOrEqual(t, math.Hypot(x, y), 1.0)
+	}
+}
+
+func TestShuffledSamples(t *testing.T) {
+	// Scenario: Shuffling a pixel's samples keeps them all, in an order that only depends on the pixel
+	// Given points ← samples(regular, 0, 0, 16)
+	// When a ← shuffled(points, 3, 4) and b ← shuffled(points, 3, 4) and c ← shuffled(points, 4, 3)
+	// Then a = b
+	// And a holds the same points as points, in another order
+	// And c is in another order than a
+	// And points is unchanged
+	points := sampling.NewRegular().Samples(0, 0, 16)
+	original := append([]sampling.Point(nil), points...)
+	a := sampling.Shuffled(points, 3, 4)
+	assert.Equal(t, a, sampling.Shuffled(points, 3, 4))
+	assert.ElementsMatch(t, points, a)
+	assert.NotEqual(t, points, a)
+	assert.NotEqual(t, a, sampling.Shuffled(points, 4, 3))
+	assert.Equal(t, original, points)
+}
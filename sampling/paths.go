@@ -0,0 +1,25 @@
+package sampling
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// pathSeed seeds the random numbers of traced paths, offset by the sample.
+const pathSeed = 0x70617468
+
+// PathRand returns the random source for the path traced by sample i of a
+// pixel. It only depends on the pixel and the sample, so renders are
+// repeatable however their rows are shared between workers.
+func PathRand(px, py, i int) *rand.Rand {
+	return pixelRand(pathSeed+uint64(i), px, py) // #nosec G115 -- only used as a seed
+}
+
+// CosineHemisphere maps p from the unit square onto the hemisphere of unit
+// vectors with z ≥ 0, placing more directions near the pole in proportion to
+// the cosine of their angle from it. The probability density of a direction
+// is z/π.
+func CosineHemisphere(p Point) (float64, float64, float64) {
+	x, y := ConcentricDisk(p)
+	return x, y, math.Sqrt(max(0, 1-x*x-y*y))
+}
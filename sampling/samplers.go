@@ -0,0 +1,170 @@
+// Package sampling chooses where rays are cast inside each pixel and how the
+// colors they return are combined into the final image.
+package sampling
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Point is a position inside a pixel, with both coordinates in [0, 1).
+type Point struct {
+	X, Y float64
+}
+
+// Sampler generates the positions of the samples taken in a pixel. Samplers
+// are deterministic: the same pixel always gets the same positions.
+type Sampler interface {
+	Samples(px, py, n int) []Point
+}
+
+// Regular places samples at the centers of a grid of equal cells.
+type Regular struct{}
+
+func NewRegular() Regular {
+	return Regular{}
+}
+
+// Samples returns at least n samples, arranged in a grid with as many rows
+// as columns, or one fewer.
+func (Regular) Samples(_, _, n int) []Point {
+	const half = 0.5
+	cols, rows := gridSize(n)
+	points := make([]Point, 0, cols*rows)
+	for j := range rows {
+		for i := range cols {
+			points = append(points, Point{
+				X: (float64(i) + half) / float64(cols),
+				Y: (float64(j) + half) / float64(rows),
+			})
+		}
+	}
+	return points
+}
+
+// Jittered places one sample at a random position in each cell of a grid,
+// which avoids the aliasing of a regular grid while keeping samples evenly
+// spread.
+type Jittered struct {
+	Seed uint64
+}
+
+func NewJittered(seed uint64) Jittered {
+	return Jittered{Seed: seed}
+}
+
+// Samples returns at least n samples, arranged like those of Regular.
+func (s Jittered) Samples(px, py, n int) []Point {
+	rng := pixelRand(s.Seed, px, py)
+	cols, rows := gridSize(n)
+	points := make([]Point, 0, cols*rows)
+	for j := range rows {
+		for i := range cols {
+			points = append(points, Point{
+				X: (float64(i) + rng.Float64()) / float64(cols),
+				Y: (float64(j) + rng.Float64()) / float64(rows),
+			})
+		}
+	}
+	return points
+}
+
+// Halton takes samples from the low-discrepancy Halton sequence in bases 2
+// and 3, shifted by a random offset per pixel so neighboring pixels do not
+// share the same pattern.
+type Halton struct {
+	Seed uint64
+}
+
+func NewHalton(seed uint64) Halton {
+	return Halton{Seed: seed}
+}
+
+func (s Halton) Samples(px, py, n int) []Point {
+	const baseX, baseY = 2, 3
+	rng := pixelRand(s.Seed, px, py)
+	offsetX, offsetY := rng.Float64(), rng.Float64()
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{
+			X: wrap(RadicalInverse(baseX, i) + offsetX),
+			Y: wrap(RadicalInverse(baseY, i) + offsetY),
+		}
+	}
+	return points
+}
+
+// Sobol takes samples from the two-dimensional Sobol (0,2)-sequence, which is
+// evenly stratified for every power of two samples. Each pixel scrambles the
+// sequence with its own random bits.
+type Sobol struct {
+	Seed uint64
+}
+
+func NewSobol(seed uint64) Sobol {
+	return Sobol{Seed: seed}
+}
+
+func (s Sobol) Samples(px, py, n int) []Point {
+	rng := pixelRand(s.Seed, px, py)
+	scrambleX, scrambleY := rng.Uint32(), rng.Uint32()
+	points := make([]Point, n)
+	for i := range points {
+		index := uint32(i) // #nosec G115 -- sample counts are small
+		points[i] = Point{
+			X: toUnit(bits.Reverse32(index) ^ scrambleX),
+			Y: toUnit(sobolSecondDimension(index) ^ scrambleY),
+		}
+	}
+	return points
+}
+
+// RadicalInverse mirrors the digits of i in the given base around the
+// decimal point, giving the i-th element of the van der Corput sequence.
+func RadicalInverse(base, i int) float64 {
+	inverse := 0.0
+	scale := 1.0 / float64(base)
+	for ; i > 0; i /= base {
+		inverse += float64(i%base) * scale
+		scale /= float64(base)
+	}
+	return inverse
+}
+
+func sobolSecondDimension(i uint32) uint32 {
+	const highBit = 1 << 31
+	var result uint32
+	for v := uint32(highBit); i != 0; i >>= 1 {
+		if i&1 != 0 {
+			result ^= v
+		}
+		v ^= v >> 1
+	}
+	return result
+}
+
+func toUnit(v uint32) float64 {
+	return float64(v) / (math.MaxUint32 + 1)
+}
+
+func wrap(v float64) float64 {
+	return v - math.Floor(v)
+}
+
+func gridSize(n int) (int, int) {
+	cols := max(1, int(math.Ceil(math.Sqrt(float64(n)))))
+	rows := max(1, (n+cols-1)/cols)
+	return cols, rows
+}
+
+// pixelRand returns a random source that depends only on the seed and the
+// pixel, so that images are reproducible whatever order pixels are rendered in.
+// Each kind of random number drawn for a pixel has a seed of its own: the
+// samplers use their Seed, while times, lens shuffles and paths use constants
+// that spell their names in ASCII, so no two kinds repeat the same numbers.
+func pixelRand(seed uint64, px, py int) *rand.Rand {
+	const shift = 32
+	pixel := uint64(uint32(px))<<shift | uint64(uint32(py)) // #nosec G115 -- only used as a seed
+	return rand.New(rand.NewPCG(seed, pixel))               // #nosec G404 -- sampling needs no cryptographic randomness
+}
@@ -0,0 +1,125 @@
+package sampling_test
+
+import (
+	"raytracer-vibe/sampling"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func allSamplers() map[string]sampling.Sampler {
+	return map[string]sampling.Sampler{
+		"regular":  sampling.NewRegular(),
+		"jittered": sampling.NewJittered(7),
+		"halton":   sampling.NewHalton(7),
+		"sobol":    sampling.NewSobol(7),
+	}
+}
+
+func TestRegularSamplerPlacesSamplesOnGrid(t *testing.T) {
+	// Scenario: A regular sampler places samples at the centers of a grid
+	points := sampling.NewRegular().Samples(0, 0, 4)
+	expected := []sampling.Point{{X: 0.25, Y: 0.25}, {X: 0.75, Y: 0.25}, {X: 0.25, Y: 0.75}, {X: 0.75, Y: 0.75}}
+	assert.Equal(t, expected, points)
+}
+
+func TestSingleRegularSampleIsPixelCenter(t *testing.T) {
+	// Scenario: A single regular sample is the center of the pixel
+	assert.Equal(t, []sampling.Point{{X: 0.5, Y: 0.5}}, sampling.NewRegular().Samples(3, 4, 1))
+}
+
+func TestGridSamplersRoundUpToFillGrid(t *testing.T) {
+	// Scenario: Grid samplers take at least the requested number of samples
+	assert.Len(t, sampling.NewRegular().Samples(0, 0, 2), 2)
+	assert.Len(t, sampling.NewRegular().Samples(0, 0, 5), 6)
+	assert.Len(t, sampling.NewJittered(1).Samples(0, 0, 9), 9)
+}
+
+func TestSamplersStayInsidePixel(t *testing.T) {
+	// Scenario: Every sampler places its samples inside the pixel
+	for name, s := range allSamplers() {
+		t.Run(name, func(t *testing.T) {
+			points := s.Samples(12, 34, 16)
+			require.Len(t, points, 16)
+			for _, p := range points {
+				assert.GreaterOrEqual(t, p.X, 0.0)
+				assert.Less(t, p.X, 1.0)
+				assert.GreaterOrEqual(t, p.Y, 0.0)
+				assert.Less(t, p.Y, 1.0)
+			}
+		})
+	}
+}
+
+func TestSamplersAreDeterministic(t *testing.T) {
+	// Scenario: Samplers give the same samples for the same seed and pixel
+	for name, s := range allSamplers() {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, s.Samples(5, 6, 8), s.Samples(5, 6, 8))
+		})
+	}
+}
+
+func TestRandomizedSamplersVaryBetweenPixelsAndSeeds(t *testing.T) {
+	// Scenario: Randomized samplers use different samples in each pixel and for each seed
+	tests := map[string]func(seed uint64) sampling.Sampler{
+		"jittered": func(seed uint64) sampling.Sampler { return sampling.NewJittered(seed) },
+		"halton":   func(seed uint64) sampling.Sampler { return sampling.NewHalton(seed) },
+		"sobol":    func(seed uint64) sampling.Sampler { return sampling.NewSobol(seed) },
+	}
+	for name, newSampler := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := newSampler(1)
+			assert.NotEqual(t, s.Samples(0, 0, 4), s.Samples(1, 0, 4))
+			assert.NotEqual(t, s.Samples(0, 0, 4), s.Samples(0, 1, 4))
+			assert.NotEqual(t, s.Samples(0, 0, 4), newSampler(2).Samples(0, 0, 4))
+		})
+	}
+}
+
+func TestJitteredSamplerKeepsOneSamplePerCell(t *testing.T) {
+	// Scenario: A jittered sampler places one sample in each cell of its grid
+	points := sampling.NewJittered(3).Samples(0, 0, 9)
+	for i, p := range points {
+		assert.Equal(t, i%3, int(p.X*3))
+		assert.Equal(t, i/3, int(p.Y*3))
+	}
+}
+
+func TestSobolSamplerIsStratified(t *testing.T) {
+	// Scenario: 16 Sobol samples cover every cell of a 4x4 grid and every row and column of a 16x16 grid
+	points := sampling.NewSobol(11).Samples(2, 3, 16)
+	cells := map[[2]int]bool{}
+	columns := map[int]bool{}
+	rows := map[int]bool{}
+	for _, p := range points {
+		cells[[2]int{int(p.X * 4), int(p.Y * 4)}] = true
+		columns[int(p.X*16)] = true
+		rows[int(p.Y*16)] = true
+	}
+	assert.Len(t, cells, 16)
+	assert.Len(t, columns, 16)
+	assert.Len(t, rows, 16)
+}
+
+func TestRadicalInverse(t *testing.T) {
+	// Scenario: The radical inverse mirrors the digits of an index around the decimal point
+	tests := []struct {
+		base, i  int
+		expected float64
+	}{
+		{2, 0, 0},
+		{2, 1, 0.5},
+		{2, 2, 0.25},
+		{2, 3, 0.75},
+		{2, 6, 0.375},
+		{3, 1, 1.0 / 3},
+		{3, 2, 2.0 / 3},
+		{3, 3, 1.0 / 9},
+		{3, 5, 2.0/3 + 1.0/9},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, sampling.RadicalInverse(tt.base, tt.i), 0.00001)
+	}
+}
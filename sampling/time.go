@@ -0,0 +1,17 @@
+package sampling
+
+// timeSeed seeds the times of a pixel's samples.
+const timeSeed = 0x74696d65
+
+// Times returns n moments in [0, 1) for the samples of a pixel, one at a
+// random point of each of n equal intervals, in an order that depends only
+// on the pixel. Pairing them with the pixel's samples spreads the samples
+// evenly over time as well as over the pixel.
+func Times(px, py, n int) []float64 {
+	rng := pixelRand(timeSeed, px, py)
+	times := make([]float64, n)
+	for i, stratum := range rng.Perm(n) {
+		times[i] = (float64(stratum) + rng.Float64()) / float64(n)
+	}
+	return times
+}
@@ -0,0 +1,28 @@
+package sampling_test
+
+import (
+	"raytracer-vibe/sampling"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimesAreStratified(t *testing.T) {
+	// Scenario: A pixel's sample times fall one in each equal interval, in a shuffled order
+	// Given times ← times(3, 4, 8)
+	// Then each eighth of [0, 1) holds exactly one time
+	// And times(3, 4, 8) gives the same times again
+	// And the times are not in increasing order
+	times := sampling.Times(3, 4, 8)
+	counts := make([]int, 8)
+	increasing := true
+	for i, time := range times {
+		assert.GreaterOrEqual(t, time, 0.0)
+		assert.Less(t, time, 1.0)
+		counts[int(time*8)]++
+		increasing = increasing && (i == 0 || times[i-1] < time)
+	}
+	assert.Equal(t, []int{1, 1, 1, 1, 1, 1, 1, 1}, counts)
+	assert.Equal(t, times, sampling.Times(3, 4, 8))
+	assert.False(t, increasing)
+}
@@ -0,0 +1,79 @@
+package scene
+
+import (
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+)
+
+// parseBackground parses the background seen where rays hit nothing: a solid
+// color, a vertical gradient, a cube map of six images or an equirectangular
+// image.
+func (p *parser) parseBackground(item *mapping) error {
+	if p.backgroundNode != nil {
+		return newError(item.node, item.path, "duplicate background, first added on line %d",
+			p.backgroundNode.Line)
+	}
+	kind, kindNode, err := item.str("type")
+	if err != nil {
+		return err
+	}
+	var background backgrounds.Background
+	switch kind {
+	case "solid":
+		var color tuples.Color
+		if color, err = item.color("color"); err != nil {
+			return err
+		}
+		background = backgrounds.NewSolid(color)
+	case "gradient":
+		background, err = parseGradient(item)
+	case "cube-map":
+		background, err = p.parseCubeMap(item)
+	case "equirectangular":
+		var texture patterns.ImageTexture
+		texture, err = p.imageTexture(item, "file", patterns.FilterBilinear, patterns.WrapRepeat)
+		background = backgrounds.NewEquirectangular(texture)
+	default:
+		return newError(kindNode, item.child("type"),
+			"unknown background %q, expected one of solid, gradient, cube-map, equirectangular", kind)
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := item.values["reflections"]; ok {
+		if p.scene.World.ReflectBackground, err = item.boolean("reflections"); err != nil {
+			return err
+		}
+	}
+	p.scene.World.Background = background
+	p.backgroundNode = item.node
+	return nil
+}
+
+func parseGradient(item *mapping) (backgrounds.Gradient, error) {
+	bottom, err := item.color("bottom")
+	if err != nil {
+		return backgrounds.Gradient{}, err
+	}
+	top, err := item.color("top")
+	if err != nil {
+		return backgrounds.Gradient{}, err
+	}
+	return backgrounds.NewGradient(bottom, top), nil
+}
+
+// parseCubeMap loads an image for each face of the cube from the keys left,
+// front, right, back, up and down.
+func (p *parser) parseCubeMap(item *mapping) (backgrounds.CubeMap, error) {
+	keys := []string{"left", "front", "right", "back", "up", "down"}
+	faces := make([]patterns.UVPattern, len(keys))
+	for i, key := range keys {
+		texture, err := p.imageTexture(item, key, patterns.FilterBilinear, patterns.WrapClamp)
+		if err != nil {
+			return backgrounds.CubeMap{}, err
+		}
+		faces[i] = texture
+	}
+	return backgrounds.NewCubeMap(faces[0], faces[1], faces[2], faces[3], faces[4], faces[5]), nil
+}
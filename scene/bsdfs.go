@@ -0,0 +1,127 @@
+package scene
+
+import (
+	"raytracer-vibe/bsdfs"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIOR is the index of refraction of glass.
+const defaultIOR = 1.5
+
+// parseBSDF parses the BSDF of a physically based material: lambertian,
+// conductor, dielectric or principled.
+func parseBSDF(node *yaml.Node, path string) (bsdfs.BSDF, error) {
+	m, err := newMapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+	kind, kindNode, err := m.str("type")
+	if err != nil {
+		return nil, err
+	}
+	var bsdf bsdfs.BSDF
+	switch kind {
+	case "lambertian":
+		bsdf = bsdfs.Lambertian{}
+	case "conductor":
+		bsdf, err = parseConductor(m)
+	case "dielectric":
+		bsdf, err = parseDielectric(m)
+	case "principled":
+		bsdf, err = parsePrincipled(m)
+	default:
+		return nil, newError(kindNode, m.child("type"),
+			"unknown bsdf %q, expected one of lambertian, conductor, dielectric, principled", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = m.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return bsdf, nil
+}
+
+// parseConductor parses a metal, either a named "metal" or one with the
+// complex index of refraction given by "eta" and "k".
+func parseConductor(m *mapping) (bsdfs.Conductor, error) {
+	var conductor bsdfs.Conductor
+	if _, ok := m.values["metal"]; ok {
+		metal, err := choice(m, "metal", []option[func() bsdfs.Conductor]{
+			{"gold", bsdfs.Gold}, {"silver", bsdfs.Silver}, {"copper", bsdfs.Copper}, {"aluminium", bsdfs.Aluminium},
+		})
+		if err != nil {
+			return conductor, err
+		}
+		if _, ok = m.values["eta"]; ok {
+			return conductor, newError(m.values["eta"], m.child("eta"), "a named metal has its own eta and k")
+		}
+		conductor = metal()
+	} else {
+		eta, err := m.color("eta")
+		if err != nil {
+			return conductor, err
+		}
+		k, err := m.color("k")
+		if err != nil {
+			return conductor, err
+		}
+		conductor = bsdfs.NewConductor(0, eta, k)
+	}
+	roughness, err := fraction(m, "roughness", 0)
+	if err != nil {
+		return conductor, err
+	}
+	conductor.Roughness = roughness
+	return conductor, nil
+}
+
+// parseDielectric parses a transparent material with an optional "ior",
+// which defaults to that of glass.
+func parseDielectric(m *mapping) (bsdfs.Dielectric, error) {
+	roughness, err := fraction(m, "roughness", 0)
+	if err != nil {
+		return bsdfs.Dielectric{}, err
+	}
+	ior := defaultIOR
+	if _, ok := m.values["ior"]; ok {
+		if ior, err = m.float("ior"); err != nil {
+			return bsdfs.Dielectric{}, err
+		}
+		if ior <= 0 {
+			return bsdfs.Dielectric{}, newError(m.values["ior"], m.child("ior"), "must be positive")
+		}
+	}
+	return bsdfs.NewDielectric(roughness, ior), nil
+}
+
+// parsePrincipled parses a metallic/roughness material. Both default to 0,
+// for polished plastic.
+func parsePrincipled(m *mapping) (bsdfs.Principled, error) {
+	metallic, err := fraction(m, "metallic", 0)
+	if err != nil {
+		return bsdfs.Principled{}, err
+	}
+	roughness, err := fraction(m, "roughness", 0)
+	if err != nil {
+		return bsdfs.Principled{}, err
+	}
+	return bsdfs.NewPrincipled(metallic, roughness), nil
+}
+
+// fraction returns the optional value of key, which must be between 0 and 1,
+// or fallback if it is missing.
+func fraction(m *mapping, key string, fallback float64) (float64, error) {
+	if _, ok := m.values[key]; !ok {
+		return fallback, nil
+	}
+	value, err := m.float(key)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 || value > 1 {
+		return 0, newError(m.values[key], m.child(key), "must be between 0 and 1, got %g", value)
+	}
+	return value, nil
+}
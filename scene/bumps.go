@@ -0,0 +1,97 @@
+package scene
+
+import (
+	"raytracer-vibe/bumps"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/patterns"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseBump parses a bump: noise, summed over octaves of fBm when there are
+// several, a height map taken from the brightness of a pattern, or a normal
+// map taken from the colors of a texture map.
+func (p *parser) parseBump(node *yaml.Node, path string) (bumps.Bump, error) {
+	m, err := newMapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+	kind, kindNode, err := m.str("type")
+	if err != nil {
+		return nil, err
+	}
+	var bump bumps.Bump
+	switch kind {
+	case "noise":
+		bump, err = parseNoiseBump(m)
+	case "height-map":
+		bump, err = p.parseHeightMap(m)
+	case "normal-map":
+		bump, err = p.parseNormalMap(m)
+	default:
+		return nil, newError(kindNode, m.child("type"),
+			"unknown bump %q, expected one of noise, height-map, normal-map", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = m.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return bump, nil
+}
+
+func parseNoiseBump(m *mapping) (bumps.Noise, error) {
+	frequency, err := m.float("frequency")
+	if err != nil {
+		return bumps.Noise{}, err
+	}
+	if frequency <= 0 {
+		return bumps.Noise{}, newError(m.values["frequency"], m.child("frequency"), "must be positive")
+	}
+	strength, err := m.float("strength")
+	if err != nil {
+		return bumps.Noise{}, err
+	}
+	source, octaves, err := parseNoise(m, 1)
+	if err != nil {
+		return bumps.Noise{}, err
+	}
+	if octaves > 1 {
+		source = noise.NewFBM(source, octaves)
+	}
+	return bumps.NewNoise(source, frequency, strength), nil
+}
+
+func (p *parser) parseHeightMap(m *mapping) (bumps.HeightMap, error) {
+	node, err := m.require("pattern")
+	if err != nil {
+		return bumps.HeightMap{}, err
+	}
+	height, err := p.parsePattern(node, m.child("pattern"))
+	if err != nil {
+		return bumps.HeightMap{}, err
+	}
+	strength, err := m.float("strength")
+	if err != nil {
+		return bumps.HeightMap{}, err
+	}
+	return bumps.NewHeightMap(height, strength), nil
+}
+
+func (p *parser) parseNormalMap(m *mapping) (bumps.NormalMap, error) {
+	node, err := m.require("pattern")
+	if err != nil {
+		return bumps.NormalMap{}, err
+	}
+	pattern, err := p.parsePattern(node, m.child("pattern"))
+	if err != nil {
+		return bumps.NormalMap{}, err
+	}
+	texture, ok := pattern.(*patterns.TextureMap)
+	if !ok {
+		return bumps.NormalMap{}, newError(node, m.child("pattern"),
+			"a normal map needs a spherical, planar or cylindrical mapping")
+	}
+	return bumps.NewNormalMap(texture), nil
+}
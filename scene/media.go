@@ -0,0 +1,79 @@
+package scene
+
+import (
+	"raytracer-vibe/media"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseFog parses the fog that fades distant objects into its color.
+func (p *parser) parseFog(item *mapping) error {
+	if p.fogNode != nil {
+		return newError(item.node, item.path, "duplicate fog, first added on line %d", p.fogNode.Line)
+	}
+	color, err := item.color("color")
+	if err != nil {
+		return err
+	}
+	density, err := item.float("density")
+	if err != nil {
+		return err
+	}
+	if density < 0 {
+		return newError(item.values["density"], item.child("density"), "must not be negative")
+	}
+	fog := media.NewFog(color, density)
+	p.scene.World.Fog = &fog
+	p.fogNode = item.node
+	return nil
+}
+
+// parseMedium parses the medium filling a shape: how much it absorbs and
+// scatters per unit of distance, both 0 by default, the color it tints
+// scattered light, white by default, and its anisotropy, the g of its phase
+// function, 0 by default.
+func parseMedium(node *yaml.Node, path string) (*media.Homogeneous, error) {
+	m, err := newMapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+	medium := media.NewHomogeneous(0, 0)
+	fields := []struct {
+		key   string
+		value *float64
+	}{
+		{"absorption", &medium.Absorption},
+		{"scattering", &medium.Scattering},
+	}
+	for _, f := range fields {
+		if _, ok := m.values[f.key]; !ok {
+			continue
+		}
+		if *f.value, err = m.float(f.key); err != nil {
+			return nil, err
+		}
+		if *f.value < 0 {
+			return nil, newError(m.values[f.key], m.child(f.key), "must not be negative")
+		}
+	}
+	if _, ok := m.values["color"]; ok {
+		if medium.Color, err = m.color("color"); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := m.values["anisotropy"]; ok {
+		var g float64
+		if g, err = m.float("anisotropy"); err != nil {
+			return nil, err
+		}
+		if g <= -1 || g >= 1 {
+			return nil, newError(m.values["anisotropy"], m.child("anisotropy"),
+				"must be greater than -1 and less than 1, got %g", g)
+		}
+		medium.Phase = media.NewHenyeyGreenstein(g)
+	}
+	if err = m.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return &medium, nil
+}
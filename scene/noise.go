@@ -0,0 +1,32 @@
+package scene
+
+import (
+	"raytracer-vibe/noise"
+)
+
+// parseNoise parses the kind of noise in "noise" (perlin, the default,
+// simplex or worley), seeded by "seed", and the number of fractal octaves
+// in "octaves", which the caller sums as it needs.
+func parseNoise(m *mapping, defaultOctaves int) (noise.Source, int, error) {
+	newSource, err := choice(m, "noise", []option[func(uint64) noise.Source]{
+		{"perlin", func(seed uint64) noise.Source { return noise.NewPerlin(seed) }},
+		{"simplex", func(seed uint64) noise.Source { return noise.NewSimplex(seed) }},
+		{"worley", func(seed uint64) noise.Source { return noise.NewWorley(seed) }},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	var seed uint64
+	if _, ok := m.values["seed"]; ok {
+		if seed, err = m.unsigned("seed"); err != nil {
+			return nil, 0, err
+		}
+	}
+	octaves := defaultOctaves
+	if _, ok := m.values["octaves"]; ok {
+		if octaves, err = m.positiveInt("octaves"); err != nil {
+			return nil, 0, err
+		}
+	}
+	return newSource(seed), octaves, nil
+}
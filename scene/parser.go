@@ -0,0 +1,804 @@
+package scene
+
+import (
+	"math"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// definition is a named material or transform created by a "define" item.
+type definition struct {
+	node       *yaml.Node
+	isMaterial bool
+	material   materials.Material
+	transform  matrices.Matrix
+}
+
+type parser struct {
+	scene          *Scene
+	cameraNode     *yaml.Node
+	backgroundNode *yaml.Node
+	fogNode        *yaml.Node
+	defines        map[string]definition
+	// dir is the directory that file names in the scene are relative to.
+	dir string
+	// textures caches the image textures loaded by file name, so their
+	// mipmaps are only built once.
+	textures map[string]patterns.ImageTexture
+}
+
+func newParser(dir string) *parser {
+	return &parser{
+		scene:    &Scene{World: world.New()},
+		defines:  map[string]definition{},
+		dir:      dir,
+		textures: map[string]patterns.ImageTexture{},
+	}
+}
+
+func (p *parser) parseItems(root *yaml.Node) error {
+	if root.Kind != yaml.SequenceNode {
+		return newError(root, "", "a scene must be a list of items, got %s", describe(root))
+	}
+	for i, node := range root.Content {
+		item, err := newMapping(node, "")
+		if err != nil {
+			return newError(node, "", "item %d must be a mapping, got %s", i+1, describe(node))
+		}
+		if err = p.parseItem(item); err != nil {
+			return err
+		}
+		if err = item.checkUnknown(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseItem(item *mapping) error {
+	if _, ok := item.values["define"]; ok {
+		return p.parseDefine(item)
+	}
+	if _, ok := item.values["add"]; !ok {
+		return newError(item.node, "", "an item must have an \"add\" or \"define\" key")
+	}
+	kind, kindNode, err := item.str("add")
+	if err != nil {
+		return err
+	}
+	item.path = kind
+	switch kind {
+	case "camera":
+		return p.parseCamera(item)
+	case "light":
+		return p.parseLight(item)
+	case "directional-light":
+		return p.parseDirectionalLight(item)
+	case "spot-light":
+		return p.parseSpotLight(item)
+	case "background":
+		return p.parseBackground(item)
+	case "fog":
+		return p.parseFog(item)
+	}
+	shape, err := p.parseShape(item, kind, kindNode)
+	if err != nil {
+		return err
+	}
+	p.scene.World.Objects = append(p.scene.World.Objects, shape)
+	return nil
+}
+
+func (p *parser) parseCamera(item *mapping) error {
+	if p.cameraNode != nil {
+		return newError(item.node, "camera", "duplicate camera, first added on line %d", p.cameraNode.Line)
+	}
+	width, err := item.positiveInt("width")
+	if err != nil {
+		return err
+	}
+	height, err := item.positiveInt("height")
+	if err != nil {
+		return err
+	}
+	fov, err := item.float("field-of-view")
+	if err != nil {
+		return err
+	}
+	from, err := item.point("from")
+	if err != nil {
+		return err
+	}
+	to, err := item.point("to")
+	if err != nil {
+		return err
+	}
+	up, err := item.vector("up")
+	if err != nil {
+		return err
+	}
+	if err = checkView(item, from, to, up); err != nil {
+		return err
+	}
+	c := camera.New(width, height, fov)
+	c.SetTransform(matrices.ViewTransform(from, to, up))
+	c.Projection, err = choice(item, "projection", []option[camera.Projection]{
+		{"perspective", camera.Perspective},
+		{"orthographic", camera.Orthographic},
+		{"fisheye", camera.Fisheye},
+		{"equirectangular", camera.Equirectangular},
+	})
+	if err != nil {
+		return err
+	}
+	if err = checkFieldOfView(item, fov, c.Projection); err != nil {
+		return err
+	}
+	if err = parseLens(item, c, tuples.Magnitude(to.Subtract(from))); err != nil {
+		return err
+	}
+	if err = parseShutter(item, c); err != nil {
+		return err
+	}
+	if err = parseIntegrator(item, c); err != nil {
+		return err
+	}
+	if err = p.parseStereo(item, c); err != nil {
+		return err
+	}
+	p.scene.Camera = c
+	p.cameraNode = item.node
+	return nil
+}
+
+// checkView rejects camera orientations that do not fix a view: looking from
+// a point at itself, or with up along the line of sight.
+func checkView(item *mapping, from, to, up tuples.Tuple) error {
+	zero := tuples.Vector(0, 0, 0)
+	forward := to.Subtract(from)
+	if forward.Equals(zero) {
+		return newError(item.values["to"], item.child("to"), "must not be the same point as from")
+	}
+	if up.Equals(zero) || tuples.Cross(tuples.Normalize(forward), tuples.Normalize(up)).Equals(zero) {
+		return newError(item.values["up"], item.child("up"), "must not be zero or parallel to the view direction")
+	}
+	return nil
+}
+
+// checkFieldOfView requires a field of view less than pi, beyond which a
+// perspective view cannot reach, except for fisheye cameras, whose view can
+// wrap all the way around.
+func checkFieldOfView(item *mapping, fov float64, projection camera.Projection) error {
+	node, path := item.values["field-of-view"], item.child("field-of-view")
+	if projection == camera.Fisheye {
+		if fov <= 0 || fov > 2*math.Pi {
+			return newError(node, path, "must be greater than 0 and at most 2 pi for a fisheye camera, got %g", fov)
+		}
+		return nil
+	}
+	if fov <= 0 || fov >= math.Pi {
+		return newError(node, path, "must be greater than 0 and less than pi, got %g", fov)
+	}
+	return nil
+}
+
+// parseLens parses the optional "aperture" of the camera's lens and the
+// "focal-distance" it focuses at, which defaults to the distance to the
+// point the camera looks at.
+func parseLens(item *mapping, c *camera.Camera, distance float64) error {
+	c.FocalDistance = distance
+	if node, ok := item.get("aperture"); ok {
+		aperture, err := item.float("aperture")
+		if err != nil {
+			return err
+		}
+		if aperture < 0 {
+			return newError(node, item.child("aperture"), "must not be negative")
+		}
+		c.Aperture = aperture
+	}
+	if node, ok := item.get("focal-distance"); ok {
+		focalDistance, err := item.float("focal-distance")
+		if err != nil {
+			return err
+		}
+		if focalDistance <= 0 {
+			return newError(node, item.child("focal-distance"), "must be positive")
+		}
+		c.FocalDistance = focalDistance
+	}
+	return nil
+}
+
+// parseShutter parses the optional "shutter", the times [open, close] between
+// which the camera casts rays.
+func parseShutter(item *mapping, c *camera.Camera) error {
+	node, ok := item.get("shutter")
+	if !ok {
+		return nil
+	}
+	const count = 2
+	path := item.child("shutter")
+	if node.Kind != yaml.SequenceNode || len(node.Content) != count {
+		return newError(node, path, "expected a list of 2 times, got %s", describe(node))
+	}
+	open, err := parseFloat(node.Content[0], path)
+	if err != nil {
+		return err
+	}
+	closing, err := parseFloat(node.Content[1], path)
+	if err != nil {
+		return err
+	}
+	if closing < open {
+		return newError(node, path, "the shutter must not close before it opens")
+	}
+	c.ShutterOpen, c.ShutterClose = open, closing
+	return nil
+}
+
+// parseIntegrator parses the "integrator" that shades the camera's rays,
+// whitted or path, and the number of "samples" it casts per pixel.
+func parseIntegrator(item *mapping, c *camera.Camera) error {
+	integrator, err := choice(item, "integrator", []option[world.Integrator]{
+		{"whitted", world.Whitted{}},
+		{"path", world.NewPathTracer()},
+	})
+	if err != nil {
+		return err
+	}
+	c.Integrator = integrator
+	if _, ok := item.values["samples"]; ok {
+		if c.Samples, err = item.positiveInt("samples"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseStereo parses the optional "stereo" rig of the camera: the
+// "interpupillary-distance" between its eyes, the "convergence" distance at
+// which their views line up and the "layout" of the two images.
+func (p *parser) parseStereo(item *mapping, c *camera.Camera) error {
+	node, ok := item.get("stereo")
+	if !ok {
+		return nil
+	}
+	m, err := newMapping(node, item.child("stereo"))
+	if err != nil {
+		return err
+	}
+	distanceNode, err := m.require("interpupillary-distance")
+	if err != nil {
+		return err
+	}
+	distance, err := m.float("interpupillary-distance")
+	if err != nil {
+		return err
+	}
+	if distance <= 0 {
+		return newError(distanceNode, m.child("interpupillary-distance"), "must be positive")
+	}
+	var convergence float64
+	if node, ok = m.get("convergence"); ok {
+		if convergence, err = m.float("convergence"); err != nil {
+			return err
+		}
+		if convergence < 0 {
+			return newError(node, m.child("convergence"), "must not be negative")
+		}
+	}
+	layout, err := choice(m, "layout", []option[camera.Layout]{
+		{"side-by-side", camera.SideBySide},
+		{"top-bottom", camera.TopBottom},
+	})
+	if err != nil {
+		return err
+	}
+	p.scene.Stereo = camera.NewStereo(c, distance, convergence, layout)
+	return m.checkUnknown()
+}
+
+// parseLight parses a point light, positioned with "at", or an area light,
+// spanned by "corner", "uvec" and "vvec".
+func (p *parser) parseLight(item *mapping) error {
+	if _, ok := item.values["corner"]; ok {
+		return p.parseAreaLight(item)
+	}
+	if _, ok := item.values["at"]; !ok {
+		return newError(item.node, item.path, "a light must have an \"at\" key, or \"corner\" for an area light")
+	}
+	at, err := item.point("at")
+	if err != nil {
+		return err
+	}
+	intensity, err := item.color("intensity")
+	if err != nil {
+		return err
+	}
+	light := lights.NewPointLight(at, intensity)
+	if light.Attenuation, err = parseAttenuation(item); err != nil {
+		return err
+	}
+	p.scene.World.Lights = append(p.scene.World.Lights, light)
+	return nil
+}
+
+func (p *parser) parseAreaLight(item *mapping) error {
+	corner, err := item.point("corner")
+	if err != nil {
+		return err
+	}
+	uvec, err := item.vector("uvec")
+	if err != nil {
+		return err
+	}
+	usteps, err := item.positiveInt("usteps")
+	if err != nil {
+		return err
+	}
+	vvec, err := item.vector("vvec")
+	if err != nil {
+		return err
+	}
+	vsteps, err := item.positiveInt("vsteps")
+	if err != nil {
+		return err
+	}
+	intensity, err := item.color("intensity")
+	if err != nil {
+		return err
+	}
+	light := lights.NewAreaLight(corner, uvec, usteps, vvec, vsteps, intensity)
+	if _, ok := item.values["jitter"]; ok {
+		if light.Jitter, err = item.boolean("jitter"); err != nil {
+			return err
+		}
+	}
+	p.scene.World.Lights = append(p.scene.World.Lights, light)
+	return nil
+}
+
+func (p *parser) parseDirectionalLight(item *mapping) error {
+	direction, err := item.vector("direction")
+	if err != nil {
+		return err
+	}
+	if tuples.Magnitude(direction) == 0 {
+		return newError(item.values["direction"], item.child("direction"), "must not be zero")
+	}
+	intensity, err := item.color("intensity")
+	if err != nil {
+		return err
+	}
+	p.scene.World.Lights = append(p.scene.World.Lights, lights.NewDirectionalLight(direction, intensity))
+	return nil
+}
+
+func (p *parser) parseSpotLight(item *mapping) error {
+	at, err := item.point("at")
+	if err != nil {
+		return err
+	}
+	direction, err := item.vector("direction")
+	if err != nil {
+		return err
+	}
+	if tuples.Magnitude(direction) == 0 {
+		return newError(item.values["direction"], item.child("direction"), "must not be zero")
+	}
+	inner, err := item.float("inner-angle")
+	if err != nil {
+		return err
+	}
+	outer, err := item.float("outer-angle")
+	if err != nil {
+		return err
+	}
+	switch {
+	case inner < 0:
+		return newError(item.values["inner-angle"], item.child("inner-angle"), "must not be negative")
+	case outer < inner:
+		return newError(item.values["outer-angle"], item.child("outer-angle"),
+			"must not be smaller than inner-angle")
+	case outer > math.Pi:
+		return newError(item.values["outer-angle"], item.child("outer-angle"), "must be at most pi")
+	}
+	intensity, err := item.color("intensity")
+	if err != nil {
+		return err
+	}
+	light := lights.NewSpotLight(at, direction, inner, outer, intensity)
+	if light.Attenuation, err = parseAttenuation(item); err != nil {
+		return err
+	}
+	p.scene.World.Lights = append(p.scene.World.Lights, light)
+	return nil
+}
+
+// parseAttenuation parses the optional "attenuation" key of a light: either
+// "inverse-square", a mapping of type inverse-square and an optional cutoff
+// radius, or a mapping of constant, linear and quadratic coefficients and a
+// cutoff radius.
+func parseAttenuation(item *mapping) (lights.Attenuation, error) {
+	node, ok := item.get("attenuation")
+	if !ok {
+		return lights.Attenuation{}, nil
+	}
+	path := item.child("attenuation")
+	if node.Kind == yaml.ScalarNode {
+		if node.Value != "inverse-square" {
+			return lights.Attenuation{}, newError(node, path,
+				"expected inverse-square or a mapping of coefficients, got %s", describe(node))
+		}
+		return lights.InverseSquare(0), nil
+	}
+
+	m, err := newMapping(node, path)
+	if err != nil {
+		return lights.Attenuation{}, err
+	}
+	var a lights.Attenuation
+	fields := []struct {
+		key   string
+		value *float64
+	}{
+		{"constant", &a.Constant},
+		{"linear", &a.Linear},
+		{"quadratic", &a.Quadratic},
+		{"radius", &a.Radius},
+	}
+	if _, ok = m.values["type"]; ok {
+		var kind string
+		var kindNode *yaml.Node
+		if kind, kindNode, err = m.str("type"); err != nil {
+			return a, err
+		}
+		if kind != "inverse-square" {
+			return a, newError(kindNode, m.child("type"), "unknown attenuation %q, expected inverse-square", kind)
+		}
+		// The coefficients are fixed, so only the radius may be given.
+		a = lights.InverseSquare(0)
+		fields = fields[len(fields)-1:]
+	}
+	for _, f := range fields {
+		if _, ok = m.values[f.key]; !ok {
+			continue
+		}
+		if *f.value, err = m.float(f.key); err != nil {
+			return a, err
+		}
+		if *f.value < 0 {
+			return a, newError(m.values[f.key], m.child(f.key), "must not be negative")
+		}
+	}
+	return a, m.checkUnknown()
+}
+
+// parseDefine records a named material (a mapping, optionally extending an
+// earlier material) or transform (a list of operations).
+func (p *parser) parseDefine(item *mapping) error {
+	name, nameNode, err := item.str("define")
+	if err != nil {
+		return err
+	}
+	item.path = "define " + name
+	if previous, ok := p.defines[name]; ok {
+		return newError(nameNode, item.path, "already defined on line %d", previous.node.Line)
+	}
+	value, err := item.require("value")
+	if err != nil {
+		return err
+	}
+	def := definition{node: nameNode}
+	extendNode, extends := item.get("extend")
+
+	switch value.Kind {
+	case yaml.MappingNode:
+		def.isMaterial = true
+		def.material = materials.NewMaterial()
+		if extends {
+			if def.material, err = p.lookupMaterial(extendNode, item.child("extend")); err != nil {
+				return err
+			}
+		}
+		if def.material, err = p.parseMaterialFields(value, item.child("value"), def.material); err != nil {
+			return err
+		}
+		def.material.Name = name
+	case yaml.SequenceNode:
+		if extends {
+			return newError(extendNode, item.child("extend"), "only materials can extend another definition")
+		}
+		if def.transform, err = p.parseTransform(value, item.child("value")); err != nil {
+			return err
+		}
+	case yaml.DocumentNode, yaml.ScalarNode, yaml.AliasNode:
+		return newError(value, item.child("value"),
+			"expected a material mapping or a transform list, got %s", describe(value))
+	}
+
+	p.defines[name] = def
+	return nil
+}
+
+func (p *parser) parseShape(m *mapping, kind string, kindNode *yaml.Node) (shapes.Shape, error) {
+	var shape shapes.Shape
+	switch kind {
+	case "sphere":
+		shape = spheres.NewSphere()
+	case "csg":
+		c, err := p.parseCSG(m)
+		if err != nil {
+			return nil, err
+		}
+		shape = c
+	case "sdf":
+		s, err := parseSDF(m)
+		if err != nil {
+			return nil, err
+		}
+		shape = s
+	default:
+		return nil, newError(kindNode, m.path, "unknown shape %q, expected one of sphere, csg, sdf", kind)
+	}
+
+	if node, ok := m.get("material"); ok {
+		material, err := p.parseMaterial(node, m.child("material"))
+		if err != nil {
+			return nil, err
+		}
+		shape.SetMaterial(material)
+	}
+	if node, ok := m.get("transform"); ok {
+		transform, err := p.parseTransform(node, m.child("transform"))
+		if err != nil {
+			return nil, err
+		}
+		shape.SetTransform(transform)
+	}
+	if node, ok := m.get("end-transform"); ok {
+		end, err := p.parseTransform(node, m.child("end-transform"))
+		if err != nil {
+			return nil, err
+		}
+		shape.SetMotion(matrices.NewMotion(shape.GetTransform(), end))
+	}
+	return shape, nil
+}
+
+func (p *parser) parseCSG(m *mapping) (*csg.CSG, error) {
+	opName, opNode, err := m.str("operation")
+	if err != nil {
+		return nil, err
+	}
+	var op csg.Operation
+	switch opName {
+	case "union":
+		op = csg.Union
+	case "intersection":
+		op = csg.Intersection
+	case "difference":
+		op = csg.Difference
+	default:
+		return nil, newError(opNode, m.child("operation"),
+			"unknown operation %q, expected one of union, intersection, difference", opName)
+	}
+	left, err := p.parseChild(m, "left")
+	if err != nil {
+		return nil, err
+	}
+	right, err := p.parseChild(m, "right")
+	if err != nil {
+		return nil, err
+	}
+	return csg.New(op, left, right), nil
+}
+
+// parseChild parses a shape nested inside another shape, whose kind is given
+// by a "type" key rather than "add".
+func (p *parser) parseChild(parent *mapping, key string) (shapes.Shape, error) {
+	node, err := parent.require(key)
+	if err != nil {
+		return nil, err
+	}
+	child, err := newMapping(node, parent.child(key))
+	if err != nil {
+		return nil, err
+	}
+	kind, kindNode, err := child.str("type")
+	if err != nil {
+		return nil, err
+	}
+	shape, err := p.parseShape(child, kind, kindNode)
+	if err != nil {
+		return nil, err
+	}
+	if err = child.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return shape, nil
+}
+
+// parseMaterial parses either the name of a defined material or an inline
+// mapping of material fields.
+func (p *parser) parseMaterial(node *yaml.Node, path string) (materials.Material, error) {
+	if node.Kind == yaml.ScalarNode {
+		return p.lookupMaterial(node, path)
+	}
+	return p.parseMaterialFields(node, path, materials.NewMaterial())
+}
+
+func (p *parser) lookupMaterial(node *yaml.Node, path string) (materials.Material, error) {
+	def, ok := p.defines[node.Value]
+	if node.Kind != yaml.ScalarNode || !ok {
+		return materials.Material{}, newError(node, path, "undefined material %s", describe(node))
+	}
+	if !def.isMaterial {
+		return materials.Material{}, newError(node, path, "%q is a transform, not a material", node.Value)
+	}
+	return def.material, nil
+}
+
+// parseMaterialFields overrides the fields of base with those in node.
+func (p *parser) parseMaterialFields(node *yaml.Node, path string, base materials.Material) (materials.Material, error) {
+	m, err := newMapping(node, path)
+	if err != nil {
+		return base, err
+	}
+	material := base
+	if _, ok := m.values["color"]; ok {
+		if material.Color, err = m.color("color"); err != nil {
+			return base, err
+		}
+	}
+	if _, ok := m.values["emission"]; ok {
+		if material.Emission, err = m.color("emission"); err != nil {
+			return base, err
+		}
+	}
+	if strengthNode, ok := m.get("emission-strength"); ok {
+		if material.EmissionStrength, err = m.float("emission-strength"); err != nil {
+			return base, err
+		}
+		if material.EmissionStrength < 0 {
+			return base, newError(strengthNode, m.child("emission-strength"), "must not be negative")
+		}
+	}
+	if patternNode, ok := m.get("pattern"); ok {
+		if material.Pattern, err = p.parsePattern(patternNode, m.child("pattern")); err != nil {
+			return base, err
+		}
+	}
+	if bumpNode, ok := m.get("bump"); ok {
+		if material.Bump, err = p.parseBump(bumpNode, m.child("bump")); err != nil {
+			return base, err
+		}
+	}
+	if bsdfNode, ok := m.get("bsdf"); ok {
+		if material.BSDF, err = parseBSDF(bsdfNode, m.child("bsdf")); err != nil {
+			return base, err
+		}
+	}
+	if mediumNode, ok := m.get("medium"); ok {
+		if material.Medium, err = parseMedium(mediumNode, m.child("medium")); err != nil {
+			return base, err
+		}
+	}
+	fields := []struct {
+		key   string
+		value *float64
+	}{
+		{"ambient", &material.Ambient},
+		{"diffuse", &material.Diffuse},
+		{"specular", &material.Specular},
+		{"shininess", &material.Shininess},
+		{"reflective", &material.Reflective},
+	}
+	for _, f := range fields {
+		if _, ok := m.values[f.key]; !ok {
+			continue
+		}
+		if *f.value, err = m.float(f.key); err != nil {
+			return base, err
+		}
+		if *f.value < 0 {
+			return base, newError(m.values[f.key], m.child(f.key), "must not be negative")
+		}
+	}
+	if err = m.checkUnknown(); err != nil {
+		return base, err
+	}
+	return material, nil
+}
+
+// parseTransform combines a list of operations, each either a list such as
+// [translate, 1, 2, 3] or the name of a defined transform, into one matrix.
+// Operations are applied in the order they are listed. The combined matrix
+// must be invertible, as shapes and patterns invert their transform to
+// place rays and points in their own space.
+func (p *parser) parseTransform(node *yaml.Node, path string) (matrices.Matrix, error) {
+	transform := matrices.Identity(matrices.DefaultMatrixSize)
+	if node.Kind != yaml.SequenceNode {
+		return transform, newError(node, path, "expected a list of transforms, got %s", describe(node))
+	}
+	for i, op := range node.Content {
+		opPath := path + "[" + strconv.Itoa(i) + "]"
+		var m matrices.Matrix
+		var err error
+		if op.Kind == yaml.ScalarNode {
+			m, err = p.lookupTransform(op, opPath)
+		} else {
+			m, err = parseOperation(op, opPath)
+		}
+		if err != nil {
+			return transform, err
+		}
+		transform = m.Multiply(transform)
+	}
+	if !transform.IsInvertible() {
+		return transform, newError(node, path, "not invertible, as it flattens space onto a plane, line or point")
+	}
+	return transform, nil
+}
+
+func (p *parser) lookupTransform(node *yaml.Node, path string) (matrices.Matrix, error) {
+	def, ok := p.defines[node.Value]
+	if !ok {
+		return matrices.Matrix{}, newError(node, path, "undefined transform %q", node.Value)
+	}
+	if def.isMaterial {
+		return matrices.Matrix{}, newError(node, path, "%q is a material, not a transform", node.Value)
+	}
+	return def.transform, nil
+}
+
+func parseOperation(node *yaml.Node, path string) (matrices.Matrix, error) {
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return matrices.Matrix{}, newError(node, path, "expected a transform such as [translate, x, y, z], got %s",
+			describe(node))
+	}
+	name := node.Content[0].Value
+	args := make([]float64, 0, len(node.Content)-1)
+	for _, arg := range node.Content[1:] {
+		f, err := parseFloat(arg, path)
+		if err != nil {
+			return matrices.Matrix{}, err
+		}
+		args = append(args, f)
+	}
+
+	operations := map[string]struct {
+		arity int
+		build func(a []float64) matrices.Matrix
+	}{
+		"translate": {tupleSize, func(a []float64) matrices.Matrix { return matrices.Translation(a[0], a[1], a[2]) }},
+		"scale":     {tupleSize, func(a []float64) matrices.Matrix { return matrices.Scaling(a[0], a[1], a[2]) }},
+		"rotate-x":  {1, func(a []float64) matrices.Matrix { return matrices.RotationX(a[0]) }},
+		"rotate-y":  {1, func(a []float64) matrices.Matrix { return matrices.RotationY(a[0]) }},
+		"rotate-z":  {1, func(a []float64) matrices.Matrix { return matrices.RotationZ(a[0]) }},
+		"shear": {2 * tupleSize, func(a []float64) matrices.Matrix {
+			return matrices.Shearing(a[0], a[1], a[2], a[3], a[4], a[5])
+		}},
+	}
+	op, ok := operations[name]
+	if !ok {
+		return matrices.Matrix{}, newError(node.Content[0], path,
+			"unknown transform %q, expected one of translate, scale, rotate-x, rotate-y, rotate-z, shear", name)
+	}
+	if len(args) != op.arity {
+		return matrices.Matrix{}, newError(node, path, "%s takes %d numbers, got %d", name, op.arity, len(args))
+	}
+	return op.build(args), nil
+}
@@ -0,0 +1,291 @@
+package scene
+
+import (
+	"path/filepath"
+	"raytracer-vibe/canvas"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/tuples"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parsePattern parses a pattern: a texture map, which wraps a UV pattern
+// around the object by a mapping, or one UV pattern per face for the cube
+// mapping, or marble or wood colored by noise.
+func (p *parser) parsePattern(node *yaml.Node, path string) (patterns.Pattern, error) {
+	m, err := newMapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+	kind, kindNode, err := m.str("type")
+	if err != nil {
+		return nil, err
+	}
+	var pattern patterns.Pattern
+	switch kind {
+	case "map":
+		pattern, err = p.parseMap(m)
+	case "marble":
+		pattern, err = parseMarble(m)
+	case "wood":
+		pattern, err = parseWood(m)
+	default:
+		return nil, newError(kindNode, m.child("type"), "unknown pattern %q, expected one of map, marble, wood", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if transformNode, ok := m.get("transform"); ok {
+		var transform matrices.Matrix
+		if transform, err = p.parseTransform(transformNode, m.child("transform")); err != nil {
+			return nil, err
+		}
+		pattern.SetTransform(transform)
+	}
+	if err = m.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+func (p *parser) parseMap(m *mapping) (patterns.Pattern, error) {
+	name, nameNode, err := m.str("mapping")
+	if err != nil {
+		return nil, err
+	}
+	if name == "cube" {
+		return p.parseCubeTexture(m)
+	}
+	return p.parseTextureMap(m, name, nameNode)
+}
+
+// parseMarble parses marble veins between two colors, bent by turbulence.
+func parseMarble(m *mapping) (*patterns.Marble, error) {
+	const defaultOctaves = 4
+	a, b, distortion, err := parseNoisePattern(m)
+	if err != nil {
+		return nil, err
+	}
+	source, octaves, err := parseNoise(m, defaultOctaves)
+	if err != nil {
+		return nil, err
+	}
+	return patterns.NewMarble(a, b, noise.NewTurbulence(source, octaves), distortion), nil
+}
+
+// parseWood parses wood rings between two colors, warped by fBm.
+func parseWood(m *mapping) (*patterns.Wood, error) {
+	a, b, distortion, err := parseNoisePattern(m)
+	if err != nil {
+		return nil, err
+	}
+	source, octaves, err := parseNoise(m, 1)
+	if err != nil {
+		return nil, err
+	}
+	if octaves > 1 {
+		source = noise.NewFBM(source, octaves)
+	}
+	return patterns.NewWood(a, b, source, distortion), nil
+}
+
+// parseNoisePattern parses the two colors of a procedural pattern and how
+// far noise distorts it.
+func parseNoisePattern(m *mapping) (tuples.Color, tuples.Color, float64, error) {
+	a, b, err := parseColorPair(m, "colors")
+	if err != nil {
+		return a, b, 0, err
+	}
+	distortion, err := m.float("distortion")
+	return a, b, distortion, err
+}
+
+func (p *parser) parseTextureMap(m *mapping, name string, nameNode *yaml.Node) (*patterns.TextureMap, error) {
+	mappings := map[string]patterns.Mapping{
+		"spherical":   patterns.SphericalMap,
+		"planar":      patterns.PlanarMap,
+		"cylindrical": patterns.CylindricalMap,
+	}
+	mapping, ok := mappings[name]
+	if !ok {
+		return nil, newError(nameNode, m.child("mapping"),
+			"unknown mapping %q, expected one of spherical, planar, cylindrical, cube", name)
+	}
+	uvPattern, err := p.parseUVPattern(m, "uv-pattern")
+	if err != nil {
+		return nil, err
+	}
+	return patterns.NewTextureMap(uvPattern, mapping), nil
+}
+
+// parseCubeTexture parses a UV pattern for each face of the cube.
+func (p *parser) parseCubeTexture(m *mapping) (*patterns.CubeTexture, error) {
+	keys := []string{"left", "front", "right", "back", "up", "down"}
+	faces := make([]patterns.UVPattern, len(keys))
+	for i, face := range keys {
+		var err error
+		if faces[i], err = p.parseUVPattern(m, face); err != nil {
+			return nil, err
+		}
+	}
+	return patterns.NewCubeTexture(faces[0], faces[1], faces[2], faces[3], faces[4], faces[5]), nil
+}
+
+// parseUVPattern parses the UV pattern under key: checkers, an align check
+// or an image.
+func (p *parser) parseUVPattern(parent *mapping, key string) (patterns.UVPattern, error) {
+	node, err := parent.require(key)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMapping(node, parent.child(key))
+	if err != nil {
+		return nil, err
+	}
+	kind, kindNode, err := m.str("type")
+	if err != nil {
+		return nil, err
+	}
+	var pattern patterns.UVPattern
+	switch kind {
+	case "checkers":
+		pattern, err = parseUVCheckers(m)
+	case "align-check":
+		pattern, err = parseUVAlignCheck(m)
+	case "image":
+		pattern, err = p.parseUVImage(m)
+	default:
+		return nil, newError(kindNode, m.child("type"),
+			"unknown UV pattern %q, expected one of checkers, align-check, image", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = m.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+func parseUVCheckers(m *mapping) (patterns.UVCheckers, error) {
+	width, err := m.positiveInt("width")
+	if err != nil {
+		return patterns.UVCheckers{}, err
+	}
+	height, err := m.positiveInt("height")
+	if err != nil {
+		return patterns.UVCheckers{}, err
+	}
+	a, b, err := parseColorPair(m, "colors")
+	if err != nil {
+		return patterns.UVCheckers{}, err
+	}
+	return patterns.NewUVCheckers(float64(width), float64(height), a, b), nil
+}
+
+// parseColorPair parses a list of two colors under key.
+func parseColorPair(m *mapping, key string) (tuples.Color, tuples.Color, error) {
+	var black tuples.Color
+	node, err := m.require(key)
+	if err != nil {
+		return black, black, err
+	}
+	const count = 2
+	if node.Kind != yaml.SequenceNode || len(node.Content) != count {
+		return black, black, newError(node, m.child(key), "expected a list of 2 colors, got %s", describe(node))
+	}
+	a, err := parseColor(node.Content[0], m.child(key))
+	if err != nil {
+		return black, black, err
+	}
+	b, err := parseColor(node.Content[1], m.child(key))
+	if err != nil {
+		return black, black, err
+	}
+	return a, b, nil
+}
+
+// parseUVAlignCheck parses a main color and the colors of the upper left,
+// upper right, bottom left and bottom right corners.
+func parseUVAlignCheck(m *mapping) (patterns.UVAlignCheck, error) {
+	node, err := m.require("colors")
+	if err != nil {
+		return patterns.UVAlignCheck{}, err
+	}
+	colors, err := newMapping(node, m.child("colors"))
+	if err != nil {
+		return patterns.UVAlignCheck{}, err
+	}
+	var p patterns.UVAlignCheck
+	fields := []struct {
+		key   string
+		value *tuples.Color
+	}{
+		{"main", &p.Main},
+		{"ul", &p.UpperLeft},
+		{"ur", &p.UpperRight},
+		{"bl", &p.BottomLeft},
+		{"br", &p.BottomRight},
+	}
+	for _, f := range fields {
+		if *f.value, err = colors.color(f.key); err != nil {
+			return patterns.UVAlignCheck{}, err
+		}
+	}
+	if err = colors.checkUnknown(); err != nil {
+		return patterns.UVAlignCheck{}, err
+	}
+	return p, nil
+}
+
+// parseUVImage loads the image in "file", a PPM, PNG or JPEG, filtered by
+// "filter" (nearest, bilinear or trilinear) and extended beyond its edges by
+// "wrap" (repeat, clamp or mirror).
+func (p *parser) parseUVImage(m *mapping) (patterns.ImageTexture, error) {
+	filter, err := choice(m, "filter", []option[patterns.TextureFilter]{
+		{"nearest", patterns.FilterNearest},
+		{"bilinear", patterns.FilterBilinear},
+		{"trilinear", patterns.FilterTrilinear},
+	})
+	if err != nil {
+		return patterns.ImageTexture{}, err
+	}
+	wrap, err := choice(m, "wrap", []option[patterns.Wrap]{
+		{"repeat", patterns.WrapRepeat},
+		{"clamp", patterns.WrapClamp},
+		{"mirror", patterns.WrapMirror},
+	})
+	if err != nil {
+		return patterns.ImageTexture{}, err
+	}
+	return p.imageTexture(m, "file", filter, wrap)
+}
+
+// imageTexture loads the image named by key, relative to the scene file.
+// Images used more than once are only loaded once.
+func (p *parser) imageTexture(
+	m *mapping, key string, filter patterns.TextureFilter, wrap patterns.Wrap,
+) (patterns.ImageTexture, error) {
+	file, fileNode, err := m.str(key)
+	if err != nil {
+		return patterns.ImageTexture{}, err
+	}
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, path)
+	}
+	texture, ok := p.textures[path]
+	if !ok {
+		image, err := canvas.Load(path)
+		if err != nil {
+			return patterns.ImageTexture{}, newError(fileNode, m.child(key), "%v", err)
+		}
+		texture = patterns.NewImageTexture(image, filter, wrap)
+		p.textures[path] = texture
+	}
+	texture.Filter = filter
+	texture.Wrap = wrap
+	return texture, nil
+}
@@ -0,0 +1,355 @@
+// Package scene loads scenes described in YAML.
+//
+// A scene file is a list of items. Each item either adds something to the
+// scene or defines a named value that later items can reuse:
+//
+//	# A camera, exactly one per scene. An optional aperture, the diameter of
+//	# its lens, blurs whatever is not at the focal distance, which defaults
+//	# to the distance from "from" to "to". An optional shutter, open from
+//	# time 0 to 1 here, blurs whatever moves while it is open. The
+//	# projection is perspective (the default), orthographic, fisheye or
+//	# equirectangular; only perspective cameras have a lens. A stereo rig
+//	# renders the view once for each eye, side-by-side or top-bottom, with
+//	# the eyes' views lining up at the convergence distance (by default
+//	# they stay parallel). The integrator shades rays with the Phong model
+//	# (whitted, the default) or by path tracing (path), casting samples rays
+//	# per pixel. The field of view, in radians, is less than pi, or at most
+//	# 2 pi for a fisheye.
+//	- add: camera
+//	  width: 100
+//	  height: 50
+//	  field-of-view: 1.0472
+//	  from: [0, 1.5, -5]
+//	  to: [0, 1, 0]
+//	  up: [0, 1, 0]
+//	  aperture: 0.1
+//	  focal-distance: 5
+//	  shutter: [0, 1]
+//	  projection: perspective
+//	  stereo:
+//	    interpupillary-distance: 0.064
+//	    convergence: 5
+//	    layout: side-by-side
+//	  integrator: whitted
+//	  samples: 1
+//
+//	- add: light
+//	  at: [-10, 10, -10]
+//	  intensity: [1, 1, 1]
+//
+//	# A lamp that dims with distance, as 1 / (constant + linear*d +
+//	# quadratic*d²), and goes out 20 units away. Point and spot lights may
+//	# also use "attenuation: inverse-square", or with a cutoff radius,
+//	# "attenuation: {type: inverse-square, radius: 20}".
+//	- add: light
+//	  at: [2, 3, -2]
+//	  intensity: [0.8, 0.8, 0.6]
+//	  attenuation:
+//	    constant: 1
+//	    linear: 0.09
+//	    quadratic: 0.032
+//	    radius: 20
+//
+//	# An area light casting soft shadows, sampled on a 4x4 grid of cells.
+//	- add: light
+//	  corner: [-1, 2, -4]
+//	  uvec: [2, 0, 0]
+//	  vvec: [0, 2, 0]
+//	  usteps: 4
+//	  vsteps: 4
+//	  jitter: true
+//	  intensity: [1, 1, 1]
+//
+//	# A light infinitely far away, shining along direction.
+//	- add: directional-light
+//	  direction: [1, -1, 1]
+//	  intensity: [0.5, 0.5, 0.5]
+//
+//	# A light shining within a cone around direction, fading out between
+//	# the inner and outer angles (radians).
+//	- add: spot-light
+//	  at: [0, 5, 0]
+//	  direction: [0, -1, 0]
+//	  inner-angle: 0.3
+//	  outer-angle: 0.5
+//	  intensity: [1, 1, 1]
+//
+//	# What rays that hit nothing see, at most one per scene: a solid color,
+//	# a gradient from bottom to top, an equirectangular (latitude-longitude)
+//	# image, or a cube map with an image for each of left, front, right,
+//	# back, up and down. With "reflections: true" the background also shows
+//	# in reflective materials.
+//	- add: background
+//	  type: gradient
+//	  bottom: [1, 1, 1]
+//	  top: [0.4, 0.6, 1]
+//	  reflections: true
+//
+//	# Fog fading distant objects and the background into its color, at
+//	# most one per scene. Half the view is lost every ln(2) / density
+//	# units. Only the whitted integrator draws fog.
+//	- add: fog
+//	  color: [0.7, 0.75, 0.8]
+//	  density: 0.05
+//
+//	# Named materials, optionally extending an earlier one.
+//	- define: red
+//	  value:
+//	    color: [1, 0.2, 0.2]
+//	    specular: 0.3
+//	- define: dark-red
+//	  extend: red
+//	  value:
+//	    diffuse: 0.5
+//	    reflective: 0.2
+//
+//	# A material that glows, lighting its surroundings when path traced.
+//	# The emission-strength, 1 by default, scales the emission color.
+//	- define: lamp
+//	  value:
+//	    color: [0, 0, 0]
+//	    emission: [1, 1, 0.75]
+//	    emission-strength: 4
+//
+//	# A physically based material, shaded by a BSDF instead of the Phong
+//	# attributes: lambertian; conductor, a metal given by name (gold,
+//	# silver, copper or aluminium) or by eta and k; dielectric, glass with
+//	# an ior of 1.5 by default; or principled, with metallic. All but
+//	# lambertian take a roughness from 0, polished, to 1, matte. The color
+//	# tints all but conductors.
+//	- define: brushed-gold
+//	  value:
+//	    bsdf:
+//	      type: conductor
+//	      metal: gold
+//	      roughness: 0.3
+//	- define: frosted-glass
+//	  value:
+//	    bsdf:
+//	      type: dielectric
+//	      ior: 1.5
+//	      roughness: 0.2
+//
+//	# A medium fills the shape, whose surface is then invisible, with smoke
+//	# or murky water. It absorbs and scatters light per unit of distance,
+//	# tints what it scatters by its color and scatters forwards for a
+//	# positive anisotropy, between -1 and 1. The path tracer shows light
+//	# scattered in it; the whitted integrator only dims what is behind it.
+//	# Media must be top-level shapes that do not overlap.
+//	- define: smoke
+//	  value:
+//	    medium:
+//	      absorption: 0.1
+//	      scattering: 0.8
+//	      color: [0.9, 0.9, 0.9]
+//	      anisotropy: 0.6
+//
+//	# Materials may be colored by a texture map: a UV pattern (checkers,
+//	# align-check or an image file, found relative to the scene file)
+//	# wrapped around the object with a spherical, planar or cylindrical
+//	# mapping, and placed on it by an optional transform. Images are
+//	# filtered with nearest (the default), bilinear or trilinear, which
+//	# blurs distant texture instead of letting it shimmer, and wrap beyond
+//	# their edges with repeat (the default), clamp or mirror.
+//	- define: earth
+//	  value:
+//	    pattern:
+//	      type: map
+//	      mapping: spherical
+//	      uv-pattern:
+//	        type: image
+//	        file: earth.png
+//	        filter: trilinear
+//	        wrap: repeat
+//	      transform:
+//	        - [rotate-y, 1.5]
+//	- define: checkered
+//	  value:
+//	    pattern:
+//	      type: map
+//	      mapping: planar
+//	      uv-pattern:
+//	        type: checkers
+//	        width: 2
+//	        height: 2
+//	        colors: [[1, 1, 1], [0, 0, 0]]
+//
+//	# Procedural marble (veins across x) and wood (rings around y) blend
+//	# two colors, distorted by noise: perlin (the default), simplex or
+//	# worley, with an optional seed and number of fractal octaves. Marble
+//	# sums the octaves as turbulence, wood as fBm.
+//	- define: marble
+//	  value:
+//	    pattern:
+//	      type: marble
+//	      colors: [[0.95, 0.95, 0.92], [0.25, 0.28, 0.35]]
+//	      noise: simplex
+//	      seed: 3
+//	      octaves: 5
+//	      distortion: 1.5
+//
+//	# The cube mapping takes a UV pattern for each of the left, front,
+//	# right, back, up and down faces, such as:
+//	#   front:
+//	#     type: align-check
+//	#     colors:
+//	#       main: [1, 1, 1]
+//	#       ul: [1, 0, 0]
+//	#       ur: [1, 1, 0]
+//	#       bl: [0, 1, 0]
+//	#       br: [0, 0, 1]
+//
+//	# Materials may be bumped, tilting their normals for fine detail
+//	# without extra geometry: by noise, chosen and summed over octaves of
+//	# fBm as for marble, by a height map taken from the brightness of a
+//	# pattern, or by a normal map taken from the colors of a spherical,
+//	# planar or cylindrical texture map.
+//	- define: rippled
+//	  value:
+//	    bump:
+//	      type: noise
+//	      frequency: 4
+//	      strength: 0.2
+//	      seed: 1
+//
+//	# Named transforms.
+//	- define: lifted
+//	  value:
+//	    - [translate, 0, 1, 0]
+//
+//	- add: sphere
+//	  material: dark-red
+//	  transform:
+//	    - [scale, 0.5, 0.5, 0.5]
+//	    - lifted
+//
+//	# A shape with an end-transform moves from its transform at time 0 to
+//	# the end transform at time 1.
+//	- add: sphere
+//	  transform:
+//	    - [translate, -1, 1, 0]
+//	  end-transform:
+//	    - [translate, 1, 1, 0]
+//
+//	# Shapes nested in a CSG name their kind with "type".
+//	- add: csg
+//	  operation: difference
+//	  left:
+//	    type: sphere
+//	  right:
+//	    type: sphere
+//	    transform:
+//	      - [translate, 0, 0, -0.5]
+//
+//	# An sdf is the surface where a signed distance function is 0: a sphere,
+//	# box, torus or capsule, or a smooth-union or smooth-subtraction of two
+//	# others, each optionally translated. Rays march to it in at most
+//	# max-steps steps, hitting it once within epsilon of it.
+//	- add: sdf
+//	  distance:
+//	    type: smooth-union
+//	    smoothness: 0.3
+//	    left:
+//	      type: torus
+//	      major-radius: 1
+//	      minor-radius: 0.25
+//	    right:
+//	      type: capsule
+//	      from: [0, -1, 0]
+//	      to: [0, 1, 0]
+//	      radius: 0.2
+//	  max-steps: 512
+//	  epsilon: 0.0001
+//
+// Transforms are applied in the order they are listed. The supported
+// operations are translate, scale, rotate-x, rotate-y, rotate-z (radians) and
+// shear.
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/world"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoCamera is returned when a scene file does not add a camera.
+var ErrNoCamera = errors.New("scene has no camera")
+
+// Scene is a world and the camera looking at it.
+type Scene struct {
+	Camera *camera.Camera
+	// Stereo is the camera's stereo rig, or nil when the scene is seen
+	// through a single eye.
+	Stereo *camera.Stereo
+	World  *world.World
+}
+
+// ParseError reports a problem with a scene file, positioned at the
+// offending YAML node.
+type ParseError struct {
+	Line   int
+	Column int
+	// Key is the dotted path to the offending key, e.g. "sphere.material.diffuse".
+	Key string
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Column, e.Key, e.Msg)
+}
+
+// Load reads and parses the scene file at path.
+func Load(path string) (*Scene, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading scene: %w", err)
+	}
+	s, err := parse(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Parse parses a scene from YAML. Files the scene refers to, such as
+// texture images, are found relative to the working directory.
+func Parse(data []byte) (*Scene, error) {
+	return parse(data, "")
+}
+
+// parse parses a scene whose files are found relative to dir.
+func parse(data []byte, dir string) (*Scene, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, ErrNoCamera
+	}
+
+	p := newParser(dir)
+	if err := p.parseItems(doc.Content[0]); err != nil {
+		return nil, err
+	}
+	if p.scene.Camera == nil {
+		return nil, ErrNoCamera
+	}
+	return p.scene, nil
+}
+
+func newError(node *yaml.Node, key, format string, args ...any) *ParseError {
+	return &ParseError{
+		Line:   node.Line,
+		Column: node.Column,
+		Key:    key,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,1671 @@
+package scene_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/bumps"
+	"raytracer-vibe/camera"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/media"
+	"raytracer-vibe/noise"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/scene"
+	"raytracer-vibe/sdf"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cameraYAML = `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0.785
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+`
+
+func TestParsingCamera(t *testing.T) {
+	// Scenario: Parsing a camera
+	// Given a scene with a camera of 100x50 pixels looking from (0, 1.5, -5) to (0, 1, 0)
+	// When the scene is parsed
+	// Then the camera has the given size, field of view and view transform
+	s, err := scene.Parse([]byte(cameraYAML))
+	require.NoError(t, err)
+	assert.Equal(t, 100, s.Camera.HSize)
+	assert.Equal(t, 50, s.Camera.VSize)
+	assert.InEpsilon(t, 0.785, s.Camera.FieldOfView, 0.00001)
+	expected := matrices.ViewTransform(tuples.Point(0, 1.5, -5), tuples.Point(0, 1, 0), tuples.Vector(0, 1, 0))
+	assert.True(t, s.Camera.Transform.Equals(expected))
+	assert.Empty(t, s.World.Objects)
+}
+
+func TestParsingCameraLens(t *testing.T) {
+	// Scenario: Parsing the lens of a camera
+	// Given a camera without a lens
+	// And a camera with aperture 0.2 and focal distance 3
+	// When the scenes are parsed
+	// Then the first is a pinhole focused on the point it looks at
+	// And the second has the given aperture and focal distance
+	s, err := scene.Parse([]byte(cameraYAML))
+	require.NoError(t, err)
+	assert.Zero(t, s.Camera.Aperture)
+	assert.InDelta(t, math.Sqrt(25.25), s.Camera.FocalDistance, 1e-9)
+	s, err = scene.Parse([]byte(cameraYAML + "  aperture: 0.2\n  focal-distance: 3\n"))
+	require.NoError(t, err)
+	assert.InDelta(t, 0.2, s.Camera.Aperture, 1e-9)
+	assert.InDelta(t, 3, s.Camera.FocalDistance, 1e-9)
+}
+
+func TestParsingCameraShutter(t *testing.T) {
+	// Scenario: Parsing the shutter of a camera
+	// Given a camera without a shutter
+	// And a camera with shutter [0.25, 0.75]
+	// When the scenes are parsed
+	// Then the first captures a single instant
+	// And the second is open from 0.25 to 0.75
+	s, err := scene.Parse([]byte(cameraYAML))
+	require.NoError(t, err)
+	assert.Zero(t, s.Camera.ShutterOpen)
+	assert.Zero(t, s.Camera.ShutterClose)
+	s, err = scene.Parse([]byte(cameraYAML + "  shutter: [0.25, 0.75]\n"))
+	require.NoError(t, err)
+	assert.InDelta(t, 0.25, s.Camera.ShutterOpen, 1e-9)
+	assert.InDelta(t, 0.75, s.Camera.ShutterClose, 1e-9)
+}
+
+func TestParsingCameraProjection(t *testing.T) {
+	// Scenario: Parsing the projection of a camera
+	// Given a camera without a projection
+	// And cameras with each projection
+	// When the scenes are parsed
+	// Then the first has a perspective projection
+	// And the others have the projection named
+	// And a fisheye camera may see all the way around
+	s, err := scene.Parse([]byte(cameraYAML))
+	require.NoError(t, err)
+	assert.Equal(t, camera.Perspective, s.Camera.Projection)
+	for name, projection := range map[string]camera.Projection{
+		"perspective":     camera.Perspective,
+		"orthographic":    camera.Orthographic,
+		"fisheye":         camera.Fisheye,
+		"equirectangular": camera.Equirectangular,
+	} {
+		s, err = scene.Parse([]byte(cameraYAML + "  projection: " + name + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, projection, s.Camera.Projection, name)
+	}
+	s, err = scene.Parse([]byte(`
+- add: camera
+  width: 100
+  height: 100
+  field-of-view: 6.28
+  from: [0, 0, 0]
+  to: [0, 1, 0]
+  up: [0, 0, 1]
+  projection: fisheye
+`))
+	require.NoError(t, err)
+	assert.InDelta(t, 6.28, s.Camera.FieldOfView, 1e-12)
+}
+
+func TestParsingStereo(t *testing.T) {
+	// Scenario: Parsing a stereo rig
+	// Given a camera without a stereo rig
+	// And a camera with eyes 0.064 apart converging at 3, top and bottom
+	// And a camera with eyes 0.1 apart and no other settings
+	// When the scenes are parsed
+	// Then the first has no stereo rig
+	// And the second has the rig described, around the scene's camera
+	// And the third has parallel eyes side by side
+	s, err := scene.Parse([]byte(cameraYAML))
+	require.NoError(t, err)
+	assert.Nil(t, s.Stereo)
+	s, err = scene.Parse([]byte(cameraYAML + `  stereo:
+    interpupillary-distance: 0.064
+    convergence: 3
+    layout: top-bottom
+`))
+	require.NoError(t, err)
+	require.NotNil(t, s.Stereo)
+	assert.Same(t, s.Camera, s.Stereo.Camera)
+	assert.InDelta(t, 0.064, s.Stereo.InterpupillaryDistance, 1e-9)
+	assert.InDelta(t, 3, s.Stereo.Convergence, 1e-9)
+	assert.Equal(t, camera.TopBottom, s.Stereo.Layout)
+	s, err = scene.Parse([]byte(cameraYAML + "  stereo:\n    interpupillary-distance: 0.1\n"))
+	require.NoError(t, err)
+	assert.Zero(t, s.Stereo.Convergence)
+	assert.Equal(t, camera.SideBySide, s.Stereo.Layout)
+}
+
+func TestParsingIntegrator(t *testing.T) {
+	// Scenario: Parsing the integrator and samples of a camera
+	// Given a camera without an integrator
+	// And a camera with the path integrator and 64 samples
+	// When the scenes are parsed
+	// Then the first uses the Whitted integrator with a single sample
+	// And the second path traces 64 samples per pixel
+	s, err := scene.Parse([]byte(cameraYAML))
+	require.NoError(t, err)
+	assert.Equal(t, world.Whitted{}, s.Camera.Integrator)
+	assert.Zero(t, s.Camera.Samples)
+	s, err = scene.Parse([]byte(cameraYAML + "  integrator: path\n  samples: 64\n"))
+	require.NoError(t, err)
+	assert.Equal(t, world.NewPathTracer(), s.Camera.Integrator)
+	assert.Equal(t, 64, s.Camera.Samples)
+}
+
+func TestParsingEmission(t *testing.T) {
+	// Scenario: Parsing a glowing material
+	// Given a sphere with emission [4, 3, 2]
+	// And a sphere with emission [1, 0.5, 0] at strength 8
+	// When the scene is parsed
+	// Then the first sphere's material gives off color(4, 3, 2)
+	// And the second's gives off color(8, 4, 0)
+	// And other materials give off nothing
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    emission: [4, 3, 2]
+- add: sphere
+  material:
+    emission: [1, 0.5, 0]
+    emission-strength: 8
+- add: sphere
+`))
+	require.NoError(t, err)
+	glowing, ok := s.World.Objects[0].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(4, 3, 2).Equals(glowing.Material.Emitted().Tuple))
+	strong, ok := s.World.Objects[1].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(8, 4, 0).Equals(strong.Material.Emitted().Tuple))
+	plain, ok := s.World.Objects[2].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(plain.Material.Emitted().Tuple))
+}
+
+func TestParsingExtendedEmission(t *testing.T) {
+	// Scenario: Extending a glowing material keeps its color and strength apart
+	// Given a material "lamp" with emission [1, 0.5, 0] at strength 4
+	// And a material "bright-lamp" extending it at strength 2
+	// And a material "red-lamp" extending it with emission [1, 0, 0]
+	// When the scene is parsed
+	// Then "bright-lamp" gives off color(2, 1, 0)
+	// And "red-lamp" gives off color(4, 0, 0)
+	s, err := scene.Parse([]byte(cameraYAML + `
+- define: lamp
+  value:
+    emission: [1, 0.5, 0]
+    emission-strength: 4
+- define: bright-lamp
+  extend: lamp
+  value:
+    emission-strength: 2
+- define: red-lamp
+  extend: lamp
+  value:
+    emission: [1, 0, 0]
+- add: sphere
+  material: bright-lamp
+- add: sphere
+  material: red-lamp
+`))
+	require.NoError(t, err)
+	bright, ok := s.World.Objects[0].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(2, 1, 0).Equals(bright.Material.Emitted().Tuple))
+	red, ok := s.World.Objects[1].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(4, 0, 0).Equals(red.Material.Emitted().Tuple))
+}
+
+func TestParsingBSDFs(t *testing.T) {
+	// Scenario: Parsing physically based materials
+	// Given spheres with lambertian, conductor, dielectric and principled BSDFs
+	// When the scene is parsed
+	// Then each sphere's material has its BSDF
+	// And named metals take their eta and k from the metal
+	// And a sphere without a BSDF has none
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    bsdf:
+      type: lambertian
+- add: sphere
+  material:
+    bsdf:
+      type: conductor
+      metal: gold
+      roughness: 0.2
+- add: sphere
+  material:
+    bsdf:
+      type: conductor
+      eta: [0.2, 0.9, 1.1]
+      k: [3.9, 2.4, 2.1]
+- add: sphere
+  material:
+    bsdf:
+      type: dielectric
+      roughness: 0.3
+- add: sphere
+  material:
+    bsdf:
+      type: principled
+      metallic: 1
+      roughness: 0.5
+- add: sphere
+`))
+	require.NoError(t, err)
+	gold := bsdfs.Gold()
+	gold.Roughness = 0.2
+	expected := []bsdfs.BSDF{
+		bsdfs.Lambertian{},
+		gold,
+		bsdfs.NewConductor(0, tuples.NewColor(0.2, 0.9, 1.1), tuples.NewColor(3.9, 2.4, 2.1)),
+		bsdfs.NewDielectric(0.3, 1.5),
+		bsdfs.NewPrincipled(1, 0.5),
+		nil,
+	}
+	require.Len(t, s.World.Objects, len(expected))
+	for i, bsdf := range expected {
+		assert.Equal(t, bsdf, s.World.Objects[i].GetMaterial().BSDF, "sphere %d", i)
+	}
+}
+
+func TestParsingMedia(t *testing.T) {
+	// Scenario: Parsing fog and media
+	// Given a scene with fog of color [0.7, 0.75, 0.8] and density 0.05
+	// And a sphere filled with smoke that scatters forwards
+	// And a sphere filled with a medium that only absorbs
+	// When the scene is parsed
+	// Then the world has the fog
+	// And each sphere's material has its medium, white and isotropic unless given
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: fog
+  color: [0.7, 0.75, 0.8]
+  density: 0.05
+- add: sphere
+  material:
+    medium:
+      absorption: 0.1
+      scattering: 0.8
+      color: [0.9, 0.8, 0.7]
+      anisotropy: 0.6
+- add: sphere
+  material:
+    medium:
+      absorption: 2
+- add: sphere
+`))
+	require.NoError(t, err)
+	fog := media.NewFog(tuples.NewColor(0.7, 0.75, 0.8), 0.05)
+	assert.Equal(t, &fog, s.World.Fog)
+	smoke := media.NewHomogeneous(0.1, 0.8)
+	smoke.Color = tuples.NewColor(0.9, 0.8, 0.7)
+	smoke.Phase = media.NewHenyeyGreenstein(0.6)
+	ink := media.NewHomogeneous(2, 0)
+	expected := []*media.Homogeneous{&smoke, &ink, nil}
+	require.Len(t, s.World.Objects, len(expected))
+	for i, medium := range expected {
+		assert.Equal(t, medium, s.World.Objects[i].GetMaterial().Medium, "sphere %d", i)
+	}
+}
+
+func TestParsingLights(t *testing.T) {
+	// Scenario: Parsing point lights
+	// Given a scene with two lights
+	// When the scene is parsed
+	// Then the world contains both lights in order
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: light
+  at: [-10, 10, -10]
+  intensity: [1, 1, 1]
+- add: light
+  at: [5, 5, 5]
+  intensity: [0.2, 0.3, 0.4]
+`))
+	require.NoError(t, err)
+	require.Len(t, s.World.Lights, 2)
+	first, ok := s.World.Lights[0].(lights.PointLight)
+	require.True(t, ok)
+	assert.True(t, tuples.Point(-10, 10, -10).Equals(first.Position))
+	second, ok := s.World.Lights[1].(lights.PointLight)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(0.2, 0.3, 0.4).Equals(second.Intensity.Tuple))
+}
+
+func TestParsingAreaLight(t *testing.T) {
+	// Scenario: Parsing an area light
+	// Given a light with a corner, two edges split into 4 and 2 cells, and jitter
+	// When the scene is parsed
+	// Then the world contains an area light with cells a quarter and a half of the edges
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: light
+  corner: [-1, 2, 4]
+  uvec: [2, 0, 0]
+  vvec: [0, 2, 0]
+  usteps: 4
+  vsteps: 2
+  jitter: true
+  intensity: [1.5, 1.5, 1.5]
+`))
+	require.NoError(t, err)
+	light, ok := s.World.Lights[0].(lights.AreaLight)
+	require.True(t, ok)
+	assert.True(t, tuples.Point(-1, 2, 4).Equals(light.Corner))
+	assert.True(t, tuples.Vector(0.5, 0, 0).Equals(light.UVec))
+	assert.True(t, tuples.Vector(0, 1, 0).Equals(light.VVec))
+	assert.Equal(t, 4, light.USteps)
+	assert.Equal(t, 2, light.VSteps)
+	assert.True(t, light.Jitter)
+	assert.True(t, tuples.NewColor(1.5, 1.5, 1.5).Equals(light.Intensity.Tuple))
+}
+
+func TestParsingDirectionalAndSpotLights(t *testing.T) {
+	// Scenario: Parsing directional and spot lights
+	// Given a directional light and a spot light
+	// When the scene is parsed
+	// Then the world contains both lights with normalized directions
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: directional-light
+  direction: [0, -2, 0]
+  intensity: [0.5, 0.5, 0.5]
+- add: spot-light
+  at: [0, 5, 0]
+  direction: [0, 0, 3]
+  inner-angle: 0.3
+  outer-angle: 0.5
+  intensity: [1, 1, 1]
+`))
+	require.NoError(t, err)
+	require.Len(t, s.World.Lights, 2)
+	sun, ok := s.World.Lights[0].(lights.DirectionalLight)
+	require.True(t, ok)
+	assert.True(t, tuples.Vector(0, -1, 0).Equals(sun.Direction))
+	assert.True(t, tuples.NewColor(0.5, 0.5, 0.5).Equals(sun.Intensity.Tuple))
+	spot, ok := s.World.Lights[1].(lights.SpotLight)
+	require.True(t, ok)
+	assert.True(t, tuples.Point(0, 5, 0).Equals(spot.Position))
+	assert.True(t, tuples.Vector(0, 0, 1).Equals(spot.Direction))
+	assert.InEpsilon(t, 0.3, spot.InnerAngle, 0.00001)
+	assert.InEpsilon(t, 0.5, spot.OuterAngle, 0.00001)
+}
+
+func TestParsingLightAttenuation(t *testing.T) {
+	// Scenario: Parsing light attenuation
+	// Given a point light with attenuation coefficients and a radius
+	// And a spot light with inverse-square attenuation
+	// And a point light with inverse-square attenuation cut off at radius 15
+	// When the scene is parsed
+	// Then all three lights carry their attenuation
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: light
+  at: [0, 3, 0]
+  intensity: [1, 1, 1]
+  attenuation:
+    constant: 1
+    linear: 0.09
+    quadratic: 0.032
+    radius: 20
+- add: spot-light
+  at: [0, 5, 0]
+  direction: [0, -1, 0]
+  inner-angle: 0.3
+  outer-angle: 0.5
+  intensity: [10, 10, 10]
+  attenuation: inverse-square
+- add: light
+  at: [0, 2, 0]
+  intensity: [5, 5, 5]
+  attenuation:
+    type: inverse-square
+    radius: 15
+`))
+	require.NoError(t, err)
+	lamp, ok := s.World.Lights[0].(lights.PointLight)
+	require.True(t, ok)
+	assert.Equal(t, lights.Attenuation{Constant: 1, Linear: 0.09, Quadratic: 0.032, Radius: 20}, lamp.Attenuation)
+	spot, ok := s.World.Lights[1].(lights.SpotLight)
+	require.True(t, ok)
+	assert.Equal(t, lights.InverseSquare(0), spot.Attenuation)
+	bulb, ok := s.World.Lights[2].(lights.PointLight)
+	require.True(t, ok)
+	assert.Equal(t, lights.InverseSquare(15), bulb.Attenuation)
+}
+
+func TestParsingSphereWithInlineMaterialAndTransform(t *testing.T) {
+	// Scenario: Parsing a sphere with an inline material and transform
+	// Given a sphere with material color (1, 0, 0), diffuse 0.5
+	// And transform [scale 2], then [translate 1, 0, 0]
+	// When the scene is parsed
+	// Then the sphere's material overrides only the given fields
+	// And its transform is translation(1, 0, 0) * scaling(2, 2, 2)
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    color: [1, 0, 0]
+    diffuse: 0.5
+  transform:
+    - [scale, 2, 2, 2]
+    - [translate, 1, 0, 0]
+`))
+	require.NoError(t, err)
+	require.Len(t, s.World.Objects, 1)
+	sphere, ok := s.World.Objects[0].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(1, 0, 0).Equals(sphere.Material.Color.Tuple))
+	assert.InEpsilon(t, 0.5, sphere.Material.Diffuse, 0.00001)
+	assert.InEpsilon(t, 0.9, sphere.Material.Specular, 0.00001)
+	expected := matrices.Translation(1, 0, 0).Multiply(matrices.Scaling(2, 2, 2))
+	assert.True(t, sphere.Transform.Equals(expected))
+}
+
+func TestParsingMovingSphere(t *testing.T) {
+	// Scenario: Parsing a sphere that moves while the shutter is open
+	// Given a sphere with transform [translate 0, 0, 0] and end-transform [translate 2, 0, 0]
+	// When the scene is parsed
+	// Then the sphere moves from the transform to the end transform
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  transform:
+    - [translate, 0, 0, 0]
+  end-transform:
+    - [translate, 2, 0, 0]
+`))
+	require.NoError(t, err)
+	sphere, ok := s.World.Objects[0].(*spheres.Sphere)
+	require.True(t, ok)
+	require.NotNil(t, sphere.Motion)
+	assert.True(t, sphere.Motion.Start.Equals(matrices.Identity(4)))
+	assert.True(t, sphere.Motion.End.Equals(matrices.Translation(2, 0, 0)))
+	assert.True(t, sphere.Motion.At(0.5).Equals(matrices.Translation(1, 0, 0)))
+}
+
+func TestParsingDefinesAndReuse(t *testing.T) {
+	// Scenario: Reusing defined materials and transforms
+	// Given a material "base", a material "shiny" extending it
+	// And a transform "lift" and a transform "lift-and-turn" that reuses it
+	// When a sphere uses "shiny" and "lift-and-turn"
+	// Then the sphere combines both materials and both transforms
+	// And its material is named shiny
+	s, err := scene.Parse([]byte(cameraYAML + `
+- define: base
+  value:
+    color: [0, 0, 1]
+    ambient: 0.3
+- define: shiny
+  extend: base
+  value:
+    shininess: 10
+- define: lift
+  value:
+    - [translate, 0, 1, 0]
+- define: lift-and-turn
+  value:
+    - lift
+    - [rotate-y, 1.5707963267948966]
+- add: sphere
+  material: shiny
+  transform:
+    - lift-and-turn
+`))
+	require.NoError(t, err)
+	sphere, ok := s.World.Objects[0].(*spheres.Sphere)
+	require.True(t, ok)
+	assert.True(t, tuples.NewColor(0, 0, 1).Equals(sphere.Material.Color.Tuple))
+	assert.InEpsilon(t, 0.3, sphere.Material.Ambient, 0.00001)
+	assert.InEpsilon(t, 10.0, sphere.Material.Shininess, 0.00001)
+	assert.Equal(t, "shiny", sphere.Material.Name)
+	expected := matrices.RotationY(math.Pi / 2).Multiply(matrices.Translation(0, 1, 0))
+	assert.True(t, sphere.Transform.Equals(expected))
+}
+
+func TestParsingCSG(t *testing.T) {
+	// Scenario: Parsing a CSG shape
+	// Given a csg difference of two spheres, the right one translated
+	// When the scene is parsed
+	// Then the world contains a CSG whose operands are the two spheres
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: csg
+  operation: difference
+  left:
+    type: sphere
+    material:
+      color: [1, 1, 0]
+  right:
+    type: sphere
+    transform:
+      - [translate, 0, 0, -0.5]
+  transform:
+    - [scale, 2, 2, 2]
+`))
+	require.NoError(t, err)
+	c, ok := s.World.Objects[0].(*csg.CSG)
+	require.True(t, ok)
+	assert.Equal(t, csg.Difference, c.Operation)
+	assert.True(t, c.Transform.Equals(matrices.Scaling(2, 2, 2)))
+	assert.True(t, tuples.NewColor(1, 1, 0).Equals(c.Left.GetMaterial().Color.Tuple))
+	assert.True(t, c.Right.GetTransform().Equals(matrices.Translation(0, 0, -0.5)))
+}
+
+func TestParsingSDF(t *testing.T) {
+	// Scenario: Parsing a signed distance function shape
+	// Given an sdf with a smooth union of a sphere and a translated box
+	// And its own step limit, epsilon and transform
+	// When the scene is parsed
+	// Then the world contains an SDF with that distance and those limits
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sdf
+  max-steps: 64
+  epsilon: 0.001
+  distance:
+    type: smooth-union
+    smoothness: 0.25
+    left:
+      type: sphere
+      radius: 1
+    right:
+      type: box
+      size: [0.5, 0.5, 0.5]
+      translate: [3, 0, 0]
+  transform:
+    - [translate, 0, 1, 0]
+`))
+	require.NoError(t, err)
+	d, ok := s.World.Objects[0].(*sdf.SDF)
+	require.True(t, ok)
+	assert.Equal(t, 64, d.MaxSteps)
+	assert.InDelta(t, 0.001, d.Epsilon, 1e-12)
+	assert.InDelta(t, sdf.DefaultMaxDistance, d.MaxDistance, 1e-12)
+	assert.True(t, d.Transform.Equals(matrices.Translation(0, 1, 0)))
+	box := sdf.Translate(sdf.Box(tuples.Vector(0.5, 0.5, 0.5)), tuples.Vector(3, 0, 0))
+	expected := sdf.SmoothUnion(sdf.Sphere(1), box, 0.25)
+	for _, p := range []tuples.Tuple{tuples.Point(0, 0, 0), tuples.Point(1.75, 0, 0), tuples.Point(3, 2, 0)} {
+		assert.InDelta(t, expected(p), d.Distance(p), 1e-12)
+	}
+}
+
+func TestParsingTextureMap(t *testing.T) {
+	// Scenario: Parsing a material with a texture map
+	// Given a sphere whose material wraps 16x8 checkers with a spherical map
+	// And the pattern is scaled by 2
+	// When the scene is parsed
+	// Then the sphere's material is colored by the texture map
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: spherical
+      uv-pattern:
+        type: checkers
+        width: 16
+        height: 8
+        colors: [[0, 0, 0], [1, 1, 1]]
+      transform:
+        - [scale, 2, 2, 2]
+`))
+	require.NoError(t, err)
+	material := s.World.Objects[0].GetMaterial()
+	texture, ok := material.Pattern.(*patterns.TextureMap)
+	require.True(t, ok)
+	assert.Equal(t, patterns.NewUVCheckers(16, 8, tuples.NewColor(0, 0, 0), tuples.NewColor(1, 1, 1)),
+		texture.UVPattern)
+	assert.True(t, texture.Transform.Equals(matrices.Scaling(2, 2, 2)))
+	assert.Equal(t, tuples.NewColor(1, 1, 1), material.ColorAt(tuples.Point(0.863, 0.934, 1.5438)))
+}
+
+func TestParsingCubeTexture(t *testing.T) {
+	// Scenario: Parsing a cube mapped pattern
+	// Given a pattern with the cube mapping and an align check on every face
+	// When the scene is parsed
+	// Then each face of the pattern has its own main color
+	faces := ""
+	for i, face := range []string{"left", "front", "right", "back", "up", "down"} {
+		faces += fmt.Sprintf(`
+      %s:
+        type: align-check
+        colors:
+          main: [%d, 0, 0]
+          ul: [0, 1, 0]
+          ur: [0, 1, 0]
+          bl: [0, 1, 0]
+          br: [0, 1, 0]`, face, i)
+	}
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: cube` + faces + "\n"))
+	require.NoError(t, err)
+	material := s.World.Objects[0].GetMaterial()
+	assert.Equal(t, tuples.NewColor(0, 0, 0), material.ColorAt(tuples.Point(-1, 0, 0)))
+	assert.Equal(t, tuples.NewColor(1, 0, 0), material.ColorAt(tuples.Point(0, 0, 1)))
+	assert.Equal(t, tuples.NewColor(5, 0, 0), material.ColorAt(tuples.Point(0, -1, 0)))
+}
+
+func TestLoadingSceneWithImageTexture(t *testing.T) {
+	// Scenario: Image textures are found relative to the scene file
+	// Given a scene file and a 2x1 PPM image in the same directory
+	// When the scene is loaded
+	// Then the sphere's texture samples the image
+	dir := t.TempDir()
+	ppm := "P3\n2 1\n255\n255 0 0  0 0 255\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "texture.ppm"), []byte(ppm), 0600))
+	path := filepath.Join(dir, "textured.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(cameraYAML+`
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: planar
+      uv-pattern:
+        type: image
+        file: texture.ppm
+`), 0600))
+	s, err := scene.Load(path)
+	require.NoError(t, err)
+	material := s.World.Objects[0].GetMaterial()
+	assert.Equal(t, tuples.NewColor(1, 0, 0), material.ColorAt(tuples.Point(0.1, 0, 0)))
+	assert.Equal(t, tuples.NewColor(0, 0, 1), material.ColorAt(tuples.Point(0.9, 0, 0)))
+}
+
+func TestParsingImageTextureFiltering(t *testing.T) {
+	// Scenario: Parsing the filter and wrap mode of an image texture
+	// Given a 2x2 PPM image used by two spheres
+	// And the first sphere's texture has no filter or wrap mode
+	// And the second sphere's texture is trilinear and mirrored
+	// When the scene is loaded
+	// Then the first texture is nearest and repeated
+	// And the second texture is trilinear and mirrored
+	// And both share the same mipmaps
+	dir := t.TempDir()
+	ppm := "P3\n2 2\n255\n255 0 0  0 255 0\n0 0 255  255 255 255\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "texture.ppm"), []byte(ppm), 0600))
+	path := filepath.Join(dir, "filtered.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(cameraYAML+`
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: planar
+      uv-pattern:
+        type: image
+        file: texture.ppm
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: planar
+      uv-pattern:
+        type: image
+        file: texture.ppm
+        filter: trilinear
+        wrap: mirror
+`), 0600))
+	s, err := scene.Load(path)
+	require.NoError(t, err)
+	textures := make([]patterns.ImageTexture, len(s.World.Objects))
+	for i, object := range s.World.Objects {
+		texture, ok := object.GetMaterial().Pattern.(*patterns.TextureMap)
+		require.True(t, ok)
+		textures[i], ok = texture.UVPattern.(patterns.ImageTexture)
+		require.True(t, ok)
+	}
+	assert.Equal(t, patterns.FilterNearest, textures[0].Filter)
+	assert.Equal(t, patterns.WrapRepeat, textures[0].Wrap)
+	assert.Equal(t, patterns.FilterTrilinear, textures[1].Filter)
+	assert.Equal(t, patterns.WrapMirror, textures[1].Wrap)
+	require.Len(t, textures[1].Levels, 2)
+	assert.Same(t, textures[0].Levels[1], textures[1].Levels[1])
+}
+
+func TestParsingGradientBackground(t *testing.T) {
+	// Scenario: Parsing a background and a reflective material
+	// Given a gradient background that shows in reflections
+	// And a sphere with a reflective material
+	// When the scene is parsed
+	// Then the world has the background and reflects it
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: background
+  type: gradient
+  bottom: [1, 1, 1]
+  top: [0, 0, 1]
+  reflections: true
+- add: sphere
+  material:
+    reflective: 0.5
+`))
+	require.NoError(t, err)
+	assert.Equal(t, backgrounds.NewGradient(tuples.NewColor(1, 1, 1), tuples.NewColor(0, 0, 1)), s.World.Background)
+	assert.True(t, s.World.ReflectBackground)
+	assert.InEpsilon(t, 0.5, s.World.Objects[0].GetMaterial().Reflective, 0.00001)
+}
+
+func TestLoadingCubeMapBackground(t *testing.T) {
+	// Scenario: Loading a skybox of six images
+	// Given six 1x1 PPM images, each a different color
+	// And a scene with a cube map background using them
+	// When the scene is loaded
+	// Then looking along each axis sees the image for that face
+	dir := t.TempDir()
+	faces := map[string]tuples.Color{
+		"left":  tuples.NewColor(1, 0, 0),
+		"front": tuples.NewColor(0, 1, 0),
+		"right": tuples.NewColor(0, 0, 1),
+		"back":  tuples.NewColor(1, 1, 0),
+		"up":    tuples.NewColor(0, 1, 1),
+		"down":  tuples.NewColor(1, 0, 1),
+	}
+	yaml := cameraYAML + "- add: background\n  type: cube-map\n"
+	for face, color := range faces {
+		ppm := fmt.Sprintf("P3\n1 1\n1\n%.0f %.0f %.0f\n", color.Red(), color.Green(), color.Blue())
+		require.NoError(t, os.WriteFile(filepath.Join(dir, face+".ppm"), []byte(ppm), 0600))
+		yaml += fmt.Sprintf("  %s: %s.ppm\n", face, face)
+	}
+	path := filepath.Join(dir, "skybox.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0600))
+	s, err := scene.Load(path)
+	require.NoError(t, err)
+	require.NotNil(t, s.World.Background)
+	assert.False(t, s.World.ReflectBackground)
+	directions := map[string]tuples.Tuple{
+		"left":  tuples.Vector(-1, 0, 0),
+		"front": tuples.Vector(0, 0, 1),
+		"right": tuples.Vector(1, 0, 0),
+		"back":  tuples.Vector(0, 0, -1),
+		"up":    tuples.Vector(0, 1, 0),
+		"down":  tuples.Vector(0, -1, 0),
+	}
+	for face, direction := range directions {
+		assert.True(t, faces[face].Equals(s.World.Background.ColorAt(direction).Tuple), face)
+	}
+}
+
+func TestParsingBumps(t *testing.T) {
+	// Scenario: Parsing the bumps of materials
+	// Given a sphere bumped by seeded noise
+	// And a sphere bumped by a height map of checkers
+	// And a sphere bumped by a normal map
+	// When the scene is parsed
+	// Then each sphere's material has the bump described
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    bump:
+      type: noise
+      frequency: 4
+      strength: 0.3
+      seed: 7
+- add: sphere
+  material:
+    bump:
+      type: height-map
+      strength: 0.1
+      pattern:
+        type: map
+        mapping: planar
+        uv-pattern:
+          type: checkers
+          width: 2
+          height: 2
+          colors: [[0, 0, 0], [1, 1, 1]]
+- add: sphere
+  material:
+    bump:
+      type: normal-map
+      pattern:
+        type: map
+        mapping: spherical
+        uv-pattern:
+          type: checkers
+          width: 2
+          height: 2
+          colors: [[0.5, 0.5, 1], [0.5, 0.5, 1]]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, bumps.NewNoise(noise.NewPerlin(7), 4, 0.3), s.World.Objects[0].GetMaterial().Bump)
+	heightMap, ok := s.World.Objects[1].GetMaterial().Bump.(bumps.HeightMap)
+	require.True(t, ok)
+	assert.InDelta(t, 0.1, heightMap.Strength, 1e-9)
+	assert.IsType(t, &patterns.TextureMap{}, heightMap.Height)
+	normalMap, ok := s.World.Objects[2].GetMaterial().Bump.(bumps.NormalMap)
+	require.True(t, ok)
+	normal := normalMap.Perturb(tuples.Point(0, 0, -1), tuples.Vector(0, 0, -1))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(normal))
+}
+
+func TestParsingNoisePatterns(t *testing.T) {
+	// Scenario: Parsing marble, wood and fractal noise bumps
+	// Given a sphere of marble using 3 octaves of seeded simplex turbulence
+	// And a sphere of wood warped by Worley noise
+	// And a sphere bumped by 2 octaves of Perlin fBm
+	// When the scene is parsed
+	// Then each material is built from the noise described
+	s, err := scene.Parse([]byte(cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: marble
+      colors: [[1, 1, 1], [0, 0, 0]]
+      noise: simplex
+      seed: 5
+      octaves: 3
+      distortion: 2
+- add: sphere
+  material:
+    pattern:
+      type: wood
+      colors: [[0.6, 0.4, 0.2], [0.3, 0.2, 0.1]]
+      noise: worley
+      distortion: 0.1
+      transform:
+        - [scale, 0.1, 0.1, 0.1]
+- add: sphere
+  material:
+    bump:
+      type: noise
+      frequency: 2
+      strength: 0.5
+      octaves: 2
+`))
+	require.NoError(t, err)
+	white, black := tuples.NewColor(1, 1, 1), tuples.NewColor(0, 0, 0)
+	assert.Equal(t, patterns.NewMarble(white, black, noise.NewTurbulence(noise.NewSimplex(5), 3), 2),
+		s.World.Objects[0].GetMaterial().Pattern)
+	wood := patterns.NewWood(tuples.NewColor(0.6, 0.4, 0.2), tuples.NewColor(0.3, 0.2, 0.1), noise.NewWorley(0), 0.1)
+	wood.SetTransform(matrices.Scaling(0.1, 0.1, 0.1))
+	assert.Equal(t, wood, s.World.Objects[1].GetMaterial().Pattern)
+	assert.Equal(t, bumps.NewNoise(noise.NewFBM(noise.NewPerlin(0), 2), 2, 0.5), s.World.Objects[2].GetMaterial().Bump)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{
+			name:     "not a list",
+			yaml:     "add: camera",
+			expected: "line 1, column 1: a scene must be a list of items, got a mapping",
+		},
+		{
+			name:     "item without add or define",
+			yaml:     "- at: [1, 2, 3]",
+			expected: `line 1, column 3: an item must have an "add" or "define" key`,
+		},
+		{
+			name: "bad number",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    diffuse: shiny
+`,
+			expected: `line 12, column 14: sphere.material.diffuse: expected a number, got "shiny"`,
+		},
+		{
+			name: "unknown key",
+			yaml: cameraYAML + `
+- add: light
+  at: [0, 0, 0]
+  intensity: [1, 1, 1]
+  color: [1, 1, 1]
+`,
+			expected: "line 13, column 3: light.color: unknown key",
+		},
+		{
+			name: "missing key",
+			yaml: `
+- add: camera
+  width: 100
+`,
+			expected: `line 2, column 3: camera: missing required key "height"`,
+		},
+		{
+			name: "wrong tuple size",
+			yaml: cameraYAML + `
+- add: light
+  at: [0, 0]
+  intensity: [1, 1, 1]
+`,
+			expected: "line 11, column 7: light.at: expected a list of 3 numbers, got a list of 2",
+		},
+		{
+			name: "light without position",
+			yaml: cameraYAML + `
+- add: light
+  intensity: [1, 1, 1]
+`,
+			expected: `line 10, column 3: light: a light must have an "at" key, or "corner" for an area light`,
+		},
+		{
+			name: "bad jitter",
+			yaml: cameraYAML + `
+- add: light
+  corner: [0, 0, 0]
+  uvec: [1, 0, 0]
+  vvec: [0, 1, 0]
+  usteps: 2
+  vsteps: 2
+  jitter: sometimes
+  intensity: [1, 1, 1]
+`,
+			expected: `line 16, column 11: light.jitter: expected true or false, got "sometimes"`,
+		},
+		{
+			name: "spot light cones in the wrong order",
+			yaml: cameraYAML + `
+- add: spot-light
+  at: [0, 5, 0]
+  direction: [0, -1, 0]
+  inner-angle: 0.5
+  outer-angle: 0.3
+  intensity: [1, 1, 1]
+`,
+			expected: "line 14, column 16: spot-light.outer-angle: must not be smaller than inner-angle",
+		},
+		{
+			name: "zero light direction",
+			yaml: cameraYAML + `
+- add: directional-light
+  direction: [0, 0, 0]
+  intensity: [1, 1, 1]
+`,
+			expected: "line 11, column 14: directional-light.direction: must not be zero",
+		},
+		{
+			name: "unknown attenuation",
+			yaml: cameraYAML + `
+- add: light
+  at: [0, 0, 0]
+  intensity: [1, 1, 1]
+  attenuation: linear
+`,
+			expected: `line 13, column 16: light.attenuation: expected inverse-square or a mapping of coefficients, ` +
+				`got "linear"`,
+		},
+		{
+			name: "unknown attenuation type",
+			yaml: cameraYAML + `
+- add: light
+  at: [0, 0, 0]
+  intensity: [1, 1, 1]
+  attenuation:
+    type: linear
+`,
+			expected: `line 14, column 11: light.attenuation.type: unknown attenuation "linear", expected inverse-square`,
+		},
+		{
+			name: "coefficients of inverse-square attenuation",
+			yaml: cameraYAML + `
+- add: light
+  at: [0, 0, 0]
+  intensity: [1, 1, 1]
+  attenuation:
+    type: inverse-square
+    linear: 0.5
+`,
+			expected: "line 15, column 5: light.attenuation.linear: unknown key",
+		},
+		{
+			name: "negative attenuation radius",
+			yaml: cameraYAML + `
+- add: light
+  at: [0, 0, 0]
+  intensity: [1, 1, 1]
+  attenuation:
+    radius: -5
+`,
+			expected: "line 14, column 13: light.attenuation.radius: must not be negative",
+		},
+		{
+			name: "unknown shape",
+			yaml: cameraYAML + `
+- add: teapot
+`,
+			expected: `line 10, column 8: teapot: unknown shape "teapot", expected one of sphere, csg, sdf`,
+		},
+		{
+			name: "unknown transform",
+			yaml: cameraYAML + `
+- add: sphere
+  transform:
+    - [twist, 1]
+`,
+			expected: `line 12, column 8: sphere.transform[0]: unknown transform "twist", expected one of translate, ` +
+				`scale, rotate-x, rotate-y, rotate-z, shear`,
+		},
+		{
+			name: "singular transform",
+			yaml: cameraYAML + `
+- add: sphere
+  transform:
+    - [translate, 0, 1, 0]
+    - [scale, 0, 1, 1]
+`,
+			expected: "line 12, column 5: sphere.transform: not invertible, as it flattens space onto a plane, " +
+				"line or point",
+		},
+		{
+			name: "singular end transform",
+			yaml: cameraYAML + `
+- add: sphere
+  end-transform:
+    - [scale, 1, 0, 1]
+`,
+			expected: "line 12, column 5: sphere.end-transform: not invertible, as it flattens space onto a " +
+				"plane, line or point",
+		},
+		{
+			name: "wrong arity",
+			yaml: cameraYAML + `
+- add: sphere
+  transform:
+    - [translate, 1, 2]
+`,
+			expected: "line 12, column 7: sphere.transform[0]: translate takes 3 numbers, got 2",
+		},
+		{
+			name: "undefined material",
+			yaml: cameraYAML + `
+- add: sphere
+  material: glass
+`,
+			expected: `line 11, column 13: sphere.material: undefined material "glass"`,
+		},
+		{
+			name: "transform used as material",
+			yaml: cameraYAML + `
+- define: lift
+  value:
+    - [translate, 0, 1, 0]
+- add: sphere
+  material: lift
+`,
+			expected: `line 14, column 13: sphere.material: "lift" is a transform, not a material`,
+		},
+		{
+			name: "nested csg error",
+			yaml: cameraYAML + `
+- add: csg
+  operation: union
+  left:
+    type: sphere
+  right:
+    type: sphere
+    material:
+      ambient: -1
+`,
+			expected: "line 17, column 16: csg.right.material.ambient: must not be negative",
+		},
+		{
+			name: "unknown distance",
+			yaml: cameraYAML + `
+- add: sdf
+  distance:
+    type: teapot
+`,
+			expected: `line 12, column 11: sdf.distance.type: unknown distance "teapot", expected one of sphere, box, ` +
+				`torus, capsule, smooth-union, smooth-subtraction`,
+		},
+		{
+			name: "negative distance radius",
+			yaml: cameraYAML + `
+- add: sdf
+  distance:
+    type: smooth-union
+    left:
+      type: sphere
+      radius: 1
+    right:
+      type: sphere
+      radius: -1
+`,
+			expected: "line 18, column 15: sdf.distance.right.radius: must be positive, got -1",
+		},
+		{
+			name: "negative smoothness",
+			yaml: cameraYAML + `
+- add: sdf
+  distance:
+    type: smooth-subtraction
+    smoothness: -0.5
+    left:
+      type: sphere
+      radius: 1
+    right:
+      type: sphere
+      radius: 0.5
+`,
+			expected: "line 13, column 17: sdf.distance.smoothness: must not be negative",
+		},
+		{
+			name: "flat box",
+			yaml: cameraYAML + `
+- add: sdf
+  distance:
+    type: box
+    size: [1, 0, 1]
+`,
+			expected: "line 13, column 11: sdf.distance.size: must be positive along every axis",
+		},
+		{
+			name: "zero max steps",
+			yaml: cameraYAML + `
+- add: sdf
+  max-steps: 0
+  distance:
+    type: sphere
+    radius: 1
+`,
+			expected: "line 11, column 14: sdf.max-steps: must be positive, got 0",
+		},
+		{
+			name: "duplicate fog",
+			yaml: cameraYAML + `
+- add: fog
+  color: [1, 1, 1]
+  density: 0.1
+- add: fog
+  color: [1, 1, 1]
+  density: 0.2
+`,
+			expected: "line 13, column 3: fog: duplicate fog, first added on line 10",
+		},
+		{
+			name: "negative fog density",
+			yaml: cameraYAML + `
+- add: fog
+  color: [1, 1, 1]
+  density: -0.1
+`,
+			expected: "line 12, column 12: fog.density: must not be negative",
+		},
+		{
+			name: "negative scattering",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    medium:
+      scattering: -1
+`,
+			expected: "line 13, column 19: sphere.material.medium.scattering: must not be negative",
+		},
+		{
+			name: "anisotropy out of range",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    medium:
+      anisotropy: 1
+`,
+			expected: "line 13, column 19: sphere.material.medium.anisotropy: must be greater than -1 and less than 1, got 1",
+		},
+		{
+			name: "negative emission strength",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    emission: [1, 1, 1]
+    emission-strength: -1
+`,
+			expected: "line 13, column 24: sphere.material.emission-strength: must not be negative",
+		},
+		{
+			name: "unknown mapping",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: toroidal
+`,
+			expected: `line 14, column 16: sphere.material.pattern.mapping: unknown mapping "toroidal", expected one ` +
+				`of spherical, planar, cylindrical, cube`,
+		},
+		{
+			name: "checkers with one color",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: planar
+      uv-pattern:
+        type: checkers
+        width: 2
+        height: 2
+        colors: [[1, 1, 1]]
+`,
+			expected: "line 19, column 17: sphere.material.pattern.uv-pattern.colors: expected a list of 2 colors, " +
+				"got a list of 1",
+		},
+		{
+			name: "missing texture image",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: planar
+      uv-pattern:
+        type: image
+        file: does-not-exist.png
+`,
+			expected: "line 17, column 15: sphere.material.pattern.uv-pattern.file: opening image: " +
+				"open does-not-exist.png: no such file or directory",
+		},
+		{
+			name: "unknown texture filter",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: map
+      mapping: planar
+      uv-pattern:
+        type: image
+        file: earth.png
+        filter: anisotropic
+`,
+			expected: `line 18, column 17: sphere.material.pattern.uv-pattern.filter: unknown filter "anisotropic", ` +
+				`expected one of nearest, bilinear, trilinear`,
+		},
+		{
+			name: "unknown background",
+			yaml: cameraYAML + `
+- add: background
+  type: starfield
+`,
+			expected: `line 11, column 9: background.type: unknown background "starfield", expected one of solid, ` +
+				`gradient, cube-map, equirectangular`,
+		},
+		{
+			name: "duplicate background",
+			yaml: cameraYAML + `
+- add: background
+  type: solid
+  color: [0, 0, 0]
+- add: background
+  type: solid
+  color: [1, 1, 1]
+`,
+			expected: "line 13, column 3: background: duplicate background, first added on line 10",
+		},
+		{
+			name: "unknown bump",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bump:
+      type: dimples
+`,
+			expected: `line 13, column 13: sphere.material.bump.type: unknown bump "dimples", expected one of noise, ` +
+				`height-map, normal-map`,
+		},
+		{
+			name: "unknown bsdf",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bsdf:
+      type: velvet
+`,
+			expected: `line 13, column 13: sphere.material.bsdf.type: unknown bsdf "velvet", expected one of lambertian, ` +
+				`conductor, dielectric, principled`,
+		},
+		{
+			name: "unknown metal",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bsdf:
+      type: conductor
+      metal: tin
+`,
+			expected: `line 14, column 14: sphere.material.bsdf.metal: unknown metal "tin", expected one of gold, ` +
+				`silver, copper, aluminium`,
+		},
+		{
+			name: "named metal with eta",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bsdf:
+      type: conductor
+      metal: gold
+      eta: [1, 1, 1]
+`,
+			expected: "line 15, column 12: sphere.material.bsdf.eta: a named metal has its own eta and k",
+		},
+		{
+			name: "roughness out of range",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bsdf:
+      type: principled
+      roughness: 1.5
+`,
+			expected: "line 14, column 18: sphere.material.bsdf.roughness: must be between 0 and 1, got 1.5",
+		},
+		{
+			name: "no ior",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bsdf:
+      type: dielectric
+      ior: 0
+`,
+			expected: "line 14, column 12: sphere.material.bsdf.ior: must be positive",
+		},
+		{
+			name: "negative noise seed",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bump:
+      type: noise
+      frequency: 1
+      strength: 1
+      seed: -1
+`,
+			expected: `line 16, column 13: sphere.material.bump.seed: expected a whole number that is not negative, ` +
+				`got "-1"`,
+		},
+		{
+			name: "normal map with cube mapping",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    bump:
+      type: normal-map
+      pattern:
+        type: map
+        mapping: cube
+        left: {type: checkers, width: 1, height: 1, colors: [[0, 0, 1], [0, 0, 1]]}
+        front: {type: checkers, width: 1, height: 1, colors: [[0, 0, 1], [0, 0, 1]]}
+        right: {type: checkers, width: 1, height: 1, colors: [[0, 0, 1], [0, 0, 1]]}
+        back: {type: checkers, width: 1, height: 1, colors: [[0, 0, 1], [0, 0, 1]]}
+        up: {type: checkers, width: 1, height: 1, colors: [[0, 0, 1], [0, 0, 1]]}
+        down: {type: checkers, width: 1, height: 1, colors: [[0, 0, 1], [0, 0, 1]]}
+`,
+			expected: "line 15, column 9: sphere.material.bump.pattern: a normal map needs a spherical, planar or " +
+				"cylindrical mapping",
+		},
+		{
+			name: "unknown pattern",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: granite
+`,
+			expected: `line 13, column 13: sphere.material.pattern.type: unknown pattern "granite", expected one of ` +
+				`map, marble, wood`,
+		},
+		{
+			name: "unknown noise",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: marble
+      colors: [[1, 1, 1], [0, 0, 0]]
+      distortion: 1
+      noise: value
+`,
+			expected: `line 16, column 14: sphere.material.pattern.noise: unknown noise "value", expected one of ` +
+				`perlin, simplex, worley`,
+		},
+		{
+			name: "marble without distortion",
+			yaml: cameraYAML + `
+- add: sphere
+  material:
+    pattern:
+      type: marble
+      colors: [[1, 1, 1], [0, 0, 0]]
+`,
+			expected: `line 13, column 7: sphere.material.pattern: missing required key "distortion"`,
+		},
+		{
+			name:     "negative aperture",
+			yaml:     cameraYAML + "  aperture: -1\n",
+			expected: "line 9, column 13: camera.aperture: must not be negative",
+		},
+		{
+			name:     "zero focal distance",
+			yaml:     cameraYAML + "  focal-distance: 0\n",
+			expected: "line 9, column 19: camera.focal-distance: must be positive",
+		},
+		{
+			name:     "shutter with one time",
+			yaml:     cameraYAML + "  shutter: [1]\n",
+			expected: "line 9, column 12: camera.shutter: expected a list of 2 times, got a list of 1",
+		},
+		{
+			name:     "shutter closing before it opens",
+			yaml:     cameraYAML + "  shutter: [1, 0]\n",
+			expected: "line 9, column 12: camera.shutter: the shutter must not close before it opens",
+		},
+		{
+			name: "bad end transform",
+			yaml: cameraYAML + `
+- add: sphere
+  end-transform:
+    - [spin, 1]
+`,
+			expected: `line 12, column 8: sphere.end-transform[0]: unknown transform "spin", ` +
+				`expected one of translate, scale, rotate-x, rotate-y, rotate-z, shear`,
+		},
+		{
+			name: "unknown projection",
+			yaml: cameraYAML + "  projection: cylindrical\n",
+			expected: `line 9, column 15: camera.projection: unknown projection "cylindrical", ` +
+				`expected one of perspective, orthographic, fisheye, equirectangular`,
+		},
+		{
+			name:     "stereo without eyes",
+			yaml:     cameraYAML + "  stereo:\n    convergence: 2\n",
+			expected: `line 10, column 5: camera.stereo: missing required key "interpupillary-distance"`,
+		},
+		{
+			name:     "stereo eyes together",
+			yaml:     cameraYAML + "  stereo:\n    interpupillary-distance: 0\n",
+			expected: "line 10, column 30: camera.stereo.interpupillary-distance: must be positive",
+		},
+		{
+			name:     "negative convergence",
+			yaml:     cameraYAML + "  stereo:\n    interpupillary-distance: 1\n    convergence: -1\n",
+			expected: "line 11, column 18: camera.stereo.convergence: must not be negative",
+		},
+		{
+			name: "unknown stereo layout",
+			yaml: cameraYAML + "  stereo:\n    interpupillary-distance: 1\n    layout: interlaced\n",
+			expected: `line 11, column 13: camera.stereo.layout: unknown layout "interlaced", ` +
+				`expected one of side-by-side, top-bottom`,
+		},
+		{
+			name:     "unknown stereo key",
+			yaml:     cameraYAML + "  stereo:\n    interpupillary-distance: 1\n    separation: 1\n",
+			expected: `line 11, column 5: camera.stereo.separation: unknown key`,
+		},
+		{
+			name: "unknown integrator",
+			yaml: cameraYAML + "  integrator: photon\n",
+			expected: `line 9, column 15: camera.integrator: unknown integrator "photon", ` +
+				`expected one of whitted, path`,
+		},
+		{
+			name:     "no samples",
+			yaml:     cameraYAML + "  samples: 0\n",
+			expected: "line 9, column 12: camera.samples: must be positive, got 0",
+		},
+		{
+			name: "camera looking at itself",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0.785
+  from: [0, 1.5, -5]
+  to: [0, 1.5, -5]
+  up: [0, 1, 0]
+`,
+			expected: "line 7, column 7: camera.to: must not be the same point as from",
+		},
+		{
+			name: "camera up along the view",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0.785
+  from: [0, 0, -5]
+  to: [0, 5, -5]
+  up: [0, 1, 0]
+`,
+			expected: "line 8, column 7: camera.up: must not be zero or parallel to the view direction",
+		},
+		{
+			name: "zero camera up",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0.785
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 0, 0]
+`,
+			expected: "line 8, column 7: camera.up: must not be zero or parallel to the view direction",
+		},
+		{
+			name: "zero field of view",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+`,
+			expected: "line 5, column 18: camera.field-of-view: must be greater than 0 and less than pi, got 0",
+		},
+		{
+			name: "field of view of pi",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 3.1416
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+`,
+			expected: "line 5, column 18: camera.field-of-view: must be greater than 0 and less than pi, " +
+				"got 3.1416",
+		},
+		{
+			name: "fisheye field of view past a full turn",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 7
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+  projection: fisheye
+`,
+			expected: "line 5, column 18: camera.field-of-view: must be greater than 0 and at most 2 pi for a " +
+				"fisheye camera, got 7",
+		},
+		{
+			name: "field of view that is not a number",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: nan
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+`,
+			expected: `line 5, column 18: camera.field-of-view: expected a finite number, got "nan"`,
+		},
+		{
+			name: "infinite field of view",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: +Inf
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+`,
+			expected: `line 5, column 18: camera.field-of-view: expected a finite number, got "+Inf"`,
+		},
+		{
+			name: "field of view in YAML's not a number",
+			yaml: `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: .nan
+  from: [0, 1.5, -5]
+  to: [0, 1, 0]
+  up: [0, 1, 0]
+`,
+			expected: `line 5, column 18: camera.field-of-view: expected a number, got ".nan"`,
+		},
+		{
+			name:     "duplicate camera",
+			yaml:     cameraYAML + cameraYAML,
+			expected: "line 10, column 3: camera: duplicate camera, first added on line 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scene.Parse([]byte(tt.yaml))
+			var parseErr *scene.ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestParsingSceneWithoutCamera(t *testing.T) {
+	// Scenario: A scene must contain a camera
+	_, err := scene.Parse([]byte("- add: sphere\n"))
+	assert.ErrorIs(t, err, scene.ErrNoCamera)
+}
+
+func TestLoadingSceneFile(t *testing.T) {
+	// Scenario: Loading a scene from a file reports errors with the file name
+	dir := t.TempDir()
+	path := filepath.Join(dir, "broken.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- add: sphere\n  radius: 2\n"), 0600))
+	_, err := scene.Load(path)
+	require.Error(t, err)
+	assert.Equal(t, path+": line 2, column 3: sphere.radius: unknown key", err.Error())
+}
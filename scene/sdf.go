@@ -0,0 +1,168 @@
+package scene
+
+import (
+	"raytracer-vibe/sdf"
+	"raytracer-vibe/tuples"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseSDF parses an implicit surface: its distance function and, optionally,
+// the most steps rays take to find it, how close they must come to hit it
+// and how far they are followed.
+func parseSDF(m *mapping) (*sdf.SDF, error) {
+	node, err := m.require("distance")
+	if err != nil {
+		return nil, err
+	}
+	d, err := parseDistance(node, m.child("distance"))
+	if err != nil {
+		return nil, err
+	}
+	s := sdf.New(d)
+	if _, ok := m.values["max-steps"]; ok {
+		if s.MaxSteps, err = m.positiveInt("max-steps"); err != nil {
+			return nil, err
+		}
+	}
+	fields := []struct {
+		key   string
+		value *float64
+	}{
+		{"epsilon", &s.Epsilon},
+		{"max-distance", &s.MaxDistance},
+	}
+	for _, f := range fields {
+		if _, ok := m.values[f.key]; !ok {
+			continue
+		}
+		if *f.value, err = m.positiveFloat(f.key); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parseDistance parses a signed distance function: a primitive, or a smooth
+// union or subtraction of two others, chosen by its "type" and moved by an
+// optional "translate".
+func parseDistance(node *yaml.Node, path string) (sdf.Distance, error) {
+	m, err := newMapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+	kind, kindNode, err := m.str("type")
+	if err != nil {
+		return nil, err
+	}
+	var d sdf.Distance
+	switch kind {
+	case "sphere":
+		d, err = parseSphereDistance(m)
+	case "box":
+		d, err = parseBoxDistance(m)
+	case "torus":
+		d, err = parseTorusDistance(m)
+	case "capsule":
+		d, err = parseCapsuleDistance(m)
+	case "smooth-union", "smooth-subtraction":
+		d, err = parseSmoothDistance(m, kind)
+	default:
+		return nil, newError(kindNode, m.child("type"),
+			"unknown distance %q, expected one of sphere, box, torus, capsule, smooth-union, smooth-subtraction",
+			kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := m.values["translate"]; ok {
+		var offset tuples.Tuple
+		if offset, err = m.vector("translate"); err != nil {
+			return nil, err
+		}
+		d = sdf.Translate(d, offset)
+	}
+	if err = m.checkUnknown(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func parseSphereDistance(m *mapping) (sdf.Distance, error) {
+	radius, err := m.positiveFloat("radius")
+	if err != nil {
+		return nil, err
+	}
+	return sdf.Sphere(radius), nil
+}
+
+// parseBoxDistance parses a box reaching from -size to size along each
+// axis.
+func parseBoxDistance(m *mapping) (sdf.Distance, error) {
+	size, err := m.vector("size")
+	if err != nil {
+		return nil, err
+	}
+	if size.X <= 0 || size.Y <= 0 || size.Z <= 0 {
+		return nil, newError(m.values["size"], m.child("size"), "must be positive along every axis")
+	}
+	return sdf.Box(size), nil
+}
+
+func parseTorusDistance(m *mapping) (sdf.Distance, error) {
+	major, err := m.positiveFloat("major-radius")
+	if err != nil {
+		return nil, err
+	}
+	minor, err := m.positiveFloat("minor-radius")
+	if err != nil {
+		return nil, err
+	}
+	return sdf.Torus(major, minor), nil
+}
+
+func parseCapsuleDistance(m *mapping) (sdf.Distance, error) {
+	from, err := m.point("from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := m.point("to")
+	if err != nil {
+		return nil, err
+	}
+	radius, err := m.positiveFloat("radius")
+	if err != nil {
+		return nil, err
+	}
+	return sdf.Capsule(from, to, radius), nil
+}
+
+// parseSmoothDistance parses the smooth union of the left and right
+// distances, or the left with the right subtracted, blended over their
+// smoothness, 0 by default.
+func parseSmoothDistance(m *mapping, kind string) (sdf.Distance, error) {
+	var operands [2]sdf.Distance
+	for i, key := range []string{"left", "right"} {
+		node, err := m.require(key)
+		if err != nil {
+			return nil, err
+		}
+		if operands[i], err = parseDistance(node, m.child(key)); err != nil {
+			return nil, err
+		}
+	}
+	smoothness := 0.0
+	if _, ok := m.values["smoothness"]; ok {
+		var err error
+		if smoothness, err = m.float("smoothness"); err != nil {
+			return nil, err
+		}
+		if smoothness < 0 {
+			return nil, newError(m.values["smoothness"], m.child("smoothness"), "must not be negative")
+		}
+	}
+	if kind == "smooth-subtraction" {
+		return sdf.SmoothSubtraction(operands[0], operands[1], smoothness), nil
+	}
+	return sdf.SmoothUnion(operands[0], operands[1], smoothness), nil
+}
@@ -0,0 +1,250 @@
+package scene
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const tupleSize = 3
+
+// mapping gives checked access to the keys of a YAML mapping and remembers
+// which keys were read, so that unknown keys can be reported.
+type mapping struct {
+	node   *yaml.Node
+	path   string
+	keys   map[string]*yaml.Node
+	values map[string]*yaml.Node
+	order  []string
+	used   map[string]bool
+}
+
+func newMapping(node *yaml.Node, path string) (*mapping, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, newError(node, path, "expected a mapping, got %s", describe(node))
+	}
+	m := &mapping{
+		node:   node,
+		path:   path,
+		keys:   map[string]*yaml.Node{},
+		values: map[string]*yaml.Node{},
+		used:   map[string]bool{},
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if previous, ok := m.keys[key.Value]; ok {
+			return nil, newError(key, m.child(key.Value), "duplicate key, first defined on line %d", previous.Line)
+		}
+		m.keys[key.Value] = key
+		m.values[key.Value] = value
+		m.order = append(m.order, key.Value)
+	}
+	return m, nil
+}
+
+func (m *mapping) child(key string) string {
+	if m.path == "" {
+		return key
+	}
+	return m.path + "." + key
+}
+
+func (m *mapping) get(key string) (*yaml.Node, bool) {
+	value, ok := m.values[key]
+	if ok {
+		m.used[key] = true
+	}
+	return value, ok
+}
+
+func (m *mapping) require(key string) (*yaml.Node, error) {
+	value, ok := m.get(key)
+	if !ok {
+		return nil, newError(m.node, m.path, "missing required key %q", key)
+	}
+	return value, nil
+}
+
+// checkUnknown reports the first key that was never read.
+func (m *mapping) checkUnknown() error {
+	for _, key := range m.order {
+		if !m.used[key] {
+			return newError(m.keys[key], m.child(key), "unknown key")
+		}
+	}
+	return nil
+}
+
+func (m *mapping) float(key string) (float64, error) {
+	node, err := m.require(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseFloat(node, m.child(key))
+}
+
+func (m *mapping) positiveInt(key string) (int, error) {
+	node, err := m.require(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(node.Value)
+	if node.Kind != yaml.ScalarNode || err != nil {
+		return 0, newError(node, m.child(key), "expected an integer, got %s", describe(node))
+	}
+	if n <= 0 {
+		return 0, newError(node, m.child(key), "must be positive, got %d", n)
+	}
+	return n, nil
+}
+
+// positiveFloat returns the value of key, which must be a number greater
+// than 0.
+func (m *mapping) positiveFloat(key string) (float64, error) {
+	v, err := m.float(key)
+	if err != nil {
+		return 0, err
+	}
+	if v <= 0 {
+		return 0, newError(m.values[key], m.child(key), "must be positive, got %g", v)
+	}
+	return v, nil
+}
+
+// unsigned returns the value of key, which must be a whole number that is
+// not negative.
+func (m *mapping) unsigned(key string) (uint64, error) {
+	node, err := m.require(key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(node.Value, 10, 64)
+	if node.Kind != yaml.ScalarNode || err != nil {
+		return 0, newError(node, m.child(key), "expected a whole number that is not negative, got %s", describe(node))
+	}
+	return n, nil
+}
+
+func (m *mapping) point(key string) (tuples.Tuple, error) {
+	x, y, z, err := m.triple(key)
+	return tuples.Point(x, y, z), err
+}
+
+func (m *mapping) vector(key string) (tuples.Tuple, error) {
+	x, y, z, err := m.triple(key)
+	return tuples.Vector(x, y, z), err
+}
+
+func (m *mapping) color(key string) (tuples.Color, error) {
+	r, g, b, err := m.triple(key)
+	return tuples.NewColor(r, g, b), err
+}
+
+func (m *mapping) triple(key string) (float64, float64, float64, error) {
+	node, err := m.require(key)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return parseTriple(node, m.child(key))
+}
+
+func (m *mapping) boolean(key string) (bool, error) {
+	node, err := m.require(key)
+	if err != nil {
+		return false, err
+	}
+	var b bool
+	if node.Kind != yaml.ScalarNode || node.Decode(&b) != nil {
+		return false, newError(node, m.child(key), "expected true or false, got %s", describe(node))
+	}
+	return b, nil
+}
+
+// str returns the value of key, which must be a plain string.
+func (m *mapping) str(key string) (string, *yaml.Node, error) {
+	node, err := m.require(key)
+	if err != nil {
+		return "", nil, err
+	}
+	if node.Kind != yaml.ScalarNode {
+		return "", nil, newError(node, m.child(key), "expected a string, got %s", describe(node))
+	}
+	return node.Value, node, nil
+}
+
+// option is one of the names accepted by choice and the value it stands for.
+type option[T any] struct {
+	name  string
+	value T
+}
+
+// choice returns the value of the option named by key, or of the first
+// option when key is missing.
+func choice[T any](m *mapping, key string, options []option[T]) (T, error) {
+	if _, ok := m.values[key]; !ok {
+		return options[0].value, nil
+	}
+	name, node, err := m.str(key)
+	if err != nil {
+		return options[0].value, err
+	}
+	names := make([]string, len(options))
+	for i, o := range options {
+		if o.name == name {
+			return o.value, nil
+		}
+		names[i] = o.name
+	}
+	return options[0].value, newError(node, m.child(key), "unknown %s %q, expected one of %s",
+		key, name, strings.Join(names, ", "))
+}
+
+func parseColor(node *yaml.Node, path string) (tuples.Color, error) {
+	r, g, b, err := parseTriple(node, path)
+	return tuples.NewColor(r, g, b), err
+}
+
+func parseTriple(node *yaml.Node, path string) (float64, float64, float64, error) {
+	if node.Kind != yaml.SequenceNode || len(node.Content) != tupleSize {
+		return 0, 0, 0, newError(node, path, "expected a list of 3 numbers, got %s", describe(node))
+	}
+	var values [tupleSize]float64
+	for i, n := range node.Content {
+		var err error
+		if values[i], err = parseFloat(n, path); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return values[0], values[1], values[2], nil
+}
+
+func parseFloat(node *yaml.Node, path string) (float64, error) {
+	if node.Kind != yaml.ScalarNode {
+		return 0, newError(node, path, "expected a number, got %s", describe(node))
+	}
+	f, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil {
+		return 0, newError(node, path, "expected a number, got %s", describe(node))
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, newError(node, path, "expected a finite number, got %s", describe(node))
+	}
+	return f, nil
+}
+
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		return "a list of " + strconv.Itoa(len(node.Content))
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.DocumentNode, yaml.AliasNode:
+		return "an unsupported YAML node"
+	case yaml.ScalarNode:
+		return strconv.Quote(node.Value)
+	}
+	return "an unsupported YAML node"
+}
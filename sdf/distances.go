@@ -0,0 +1,104 @@
+package sdf
+
+import (
+	"math"
+	"raytracer-vibe/tuples"
+)
+
+// half is the midpoint of the blends of the smooth combinators.
+const half = 0.5
+
+// Distance is a signed distance function: the distance from a point in
+// object space to the nearest point of a surface, negative inside it. It may
+// underestimate the distance, which only slows rays down, but must never
+// overestimate it, or rays step through the surface.
+type Distance func(p tuples.Tuple) float64
+
+// Sphere returns the distance to a sphere of radius about the origin.
+func Sphere(radius float64) Distance {
+	return func(p tuples.Tuple) float64 {
+		return length(p.X, p.Y, p.Z) - radius
+	}
+}
+
+// Box returns the distance to a box about the origin, reaching from -size to
+// size along each axis, as the unit cube reaches from -1 to 1.
+func Box(size tuples.Tuple) Distance {
+	return func(p tuples.Tuple) float64 {
+		qx, qy, qz := math.Abs(p.X)-size.X, math.Abs(p.Y)-size.Y, math.Abs(p.Z)-size.Z
+		outside := length(max(qx, 0), max(qy, 0), max(qz, 0))
+		inside := min(max(qx, qy, qz), 0)
+		return outside + inside
+	}
+}
+
+// Torus returns the distance to a torus about the y axis, whose tube of
+// radius minor circles the origin at radius major.
+func Torus(major, minor float64) Distance {
+	return func(p tuples.Tuple) float64 {
+		return math.Hypot(math.Hypot(p.X, p.Z)-major, p.Y) - minor
+	}
+}
+
+// Capsule returns the distance to a capsule: the points within radius of the
+// line segment from one point to another.
+func Capsule(from, to tuples.Tuple, radius float64) Distance {
+	axis := to.Subtract(from)
+	lengthSquared := axis.Dot(axis)
+	return func(p tuples.Tuple) float64 {
+		offset := p.Subtract(from)
+		along := 0.0
+		if lengthSquared > 0 {
+			along = clamp(offset.Dot(axis) / lengthSquared)
+		}
+		return tuples.Magnitude(offset.Subtract(axis.Multiply(along))) - radius
+	}
+}
+
+// Translate returns the distance to the surface of d moved by offset, for
+// placing shapes relative to each other before combining them.
+func Translate(d Distance, offset tuples.Tuple) Distance {
+	return func(p tuples.Tuple) float64 {
+		return d(p.Subtract(offset))
+	}
+}
+
+// SmoothUnion returns the distance to the surfaces of a and b joined
+// together, with a fillet blending them where they come within smoothness of
+// each other. A smoothness of 0 gives their plain union.
+func SmoothUnion(a, b Distance, smoothness float64) Distance {
+	return func(p tuples.Tuple) float64 {
+		da, db := a(p), b(p)
+		if smoothness <= 0 {
+			return min(da, db)
+		}
+		h := clamp(half + half*(db-da)/smoothness)
+		return lerp(db, da, h) - smoothness*h*(1-h)
+	}
+}
+
+// SmoothSubtraction returns the distance to the surface of a with b carved
+// out of it, with the edges of the cut rounded over smoothness. A smoothness
+// of 0 gives a sharp cut.
+func SmoothSubtraction(a, b Distance, smoothness float64) Distance {
+	return func(p tuples.Tuple) float64 {
+		da, db := a(p), b(p)
+		if smoothness <= 0 {
+			return max(da, -db)
+		}
+		h := clamp(half - half*(da+db)/smoothness)
+		return lerp(da, -db, h) + smoothness*h*(1-h)
+	}
+}
+
+func length(x, y, z float64) float64 {
+	return math.Sqrt(x*x + y*y + z*z)
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,77 @@
+package sdf_test
+
+import (
+	"math"
+	"raytracer-vibe/sdf"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimitiveDistances(t *testing.T) {
+	// Scenario Outline: The signed distance from a point to a primitive
+	// Given the distance function <distance>
+	// Then its value at <point> is <expected>, negative inside
+	capsule := sdf.Capsule(tuples.Point(0, 0, 0), tuples.Point(0, 2, 0), 0.5)
+	tests := []struct {
+		name     string
+		distance sdf.Distance
+		point    tuples.Tuple
+		expected float64
+	}{
+		{"outside a sphere", sdf.Sphere(1), tuples.Point(0, 0, 2), 1},
+		{"at the center of a sphere", sdf.Sphere(1), tuples.Point(0, 0, 0), -1},
+		{"beside a box", sdf.Box(tuples.Vector(1, 2, 3)), tuples.Point(2, 0, 0), 1},
+		{"off the edge of a box", sdf.Box(tuples.Vector(1, 2, 3)), tuples.Point(2, 3, 0), math.Sqrt2},
+		{"at the center of a box", sdf.Box(tuples.Vector(1, 2, 3)), tuples.Point(0, 0, 0), -1},
+		{"inside the tube of a torus", sdf.Torus(2, 0.5), tuples.Point(2, 0, 0), -0.5},
+		{"in the hole of a torus", sdf.Torus(2, 0.5), tuples.Point(0, 0, 0), 1.5},
+		{"beside a capsule", capsule, tuples.Point(1, 1, 0), 0.5},
+		{"past the end of a capsule", capsule, tuples.Point(0, 3, 0), 0.5},
+		{"before the start of a capsule", capsule, tuples.Point(0, -1, 0), 0.5},
+		{"a translated sphere", sdf.Translate(sdf.Sphere(1), tuples.Vector(0, 0, 2)), tuples.Point(0, 0, 2), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.distance(tt.point), 1e-9)
+		})
+	}
+}
+
+func TestSharpCombinations(t *testing.T) {
+	// Scenario: Combinations with no smoothness are sharp
+	// Given a ← sphere(1) and b ← sphere(1) translated by vector(1.5, 0, 0)
+	// Then smooth_union(a, b, 0) is the smaller of their distances
+	// And smooth_subtraction(a, b, 0) is the larger of a's and b's negated
+	a := sdf.Sphere(1)
+	b := sdf.Translate(sdf.Sphere(1), tuples.Vector(1.5, 0, 0))
+	union := sdf.SmoothUnion(a, b, 0)
+	subtraction := sdf.SmoothSubtraction(a, b, 0)
+	for _, p := range []tuples.Tuple{tuples.Point(0, 0, 0), tuples.Point(0.75, 0, 0), tuples.Point(3, 1, 0)} {
+		assert.InDelta(t, min(a(p), b(p)), union(p), 1e-9)
+		assert.InDelta(t, max(a(p), -b(p)), subtraction(p), 1e-9)
+	}
+}
+
+func TestSmoothCombinations(t *testing.T) {
+	// Scenario: Smoothness fills in between shapes and rounds off cuts
+	// Given a ← sphere(1) and b ← sphere(1) translated by vector(2.2, 0, 0)
+	// When they are combined with a smoothness of 0.5
+	// Then the smooth union reaches across the gap between them at point(1.1, 0, 0)
+	// And far from the gap it is as before
+	// And the smooth subtraction of b from a removes more of a where b is near
+	a := sdf.Sphere(1)
+	b := sdf.Translate(sdf.Sphere(1), tuples.Vector(2.2, 0, 0))
+	union := sdf.SmoothUnion(a, b, 0.5)
+	gap := tuples.Point(1.1, 0, 0)
+	assert.Positive(t, min(a(gap), b(gap)))
+	assert.Negative(t, union(gap))
+	far := tuples.Point(-3, 0, 0)
+	assert.InDelta(t, a(far), union(far), 1e-9)
+
+	subtraction := sdf.SmoothSubtraction(a, b, 0.5)
+	near := tuples.Point(0.98, 0, 0)
+	assert.Negative(t, max(a(near), -b(near)))
+	assert.Positive(t, subtraction(near))
+}
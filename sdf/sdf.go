@@ -0,0 +1,180 @@
+// Package sdf provides implicit surfaces: shapes given by a signed distance
+// function rather than by an equation that rays can be solved against, and
+// found by marching along each ray.
+package sdf
+
+import (
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/tuples"
+)
+
+const (
+	// DefaultMaxSteps is the most steps taken along a ray by default.
+	DefaultMaxSteps = 256
+	// DefaultEpsilon is how close a ray comes to the surface to hit it by
+	// default.
+	DefaultEpsilon = 0.0001
+	// DefaultMaxDistance is how far rays are followed by default.
+	DefaultMaxDistance = 100
+)
+
+// SDF is a shape whose surface is where its Distance is 0. Rays are sphere
+// traced: each step along a ray goes as far as the distance to the surface,
+// which cannot overshoot it, until the ray comes within Epsilon of it.
+type SDF struct {
+	Distance Distance
+	// MaxSteps is the most steps taken along a ray, over all the surfaces it
+	// crosses; surfaces further along are missed. Rays that skim a surface
+	// take many small steps, and leave it where their steps run out.
+	MaxSteps int
+	// Epsilon is how close a ray must come to the surface to hit it, and the
+	// spacing of the central differences that find the normal.
+	Epsilon float64
+	// MaxDistance is how far from its origin a ray is followed, in object
+	// space.
+	MaxDistance float64
+	Transform   matrices.Matrix
+	// Motion, when set, moves the shape over time in place of Transform.
+	Motion   *matrices.Motion
+	Material materials.Material
+	parent   shapes.Shape
+}
+
+// New creates a shape of the surface of d, marched with the default limits.
+func New(d Distance) *SDF {
+	return &SDF{
+		Distance:    d,
+		MaxSteps:    DefaultMaxSteps,
+		Epsilon:     DefaultEpsilon,
+		MaxDistance: DefaultMaxDistance,
+		Transform:   matrices.Identity(matrices.DefaultMatrixSize),
+		Material:    materials.NewMaterial(),
+	}
+}
+
+func (s *SDF) SetTransform(m matrices.Matrix) {
+	s.Transform = m
+}
+
+func (s *SDF) GetTransform() matrices.Matrix {
+	return s.Transform
+}
+
+func (s *SDF) GetMotion() *matrices.Motion {
+	return s.Motion
+}
+
+func (s *SDF) SetMotion(m *matrices.Motion) {
+	s.Motion = m
+}
+
+func (s *SDF) GetMaterial() materials.Material {
+	return s.Material
+}
+
+func (s *SDF) SetMaterial(m materials.Material) {
+	s.Material = m
+}
+
+func (s *SDF) Parent() shapes.Shape {
+	return s.parent
+}
+
+func (s *SDF) SetParent(p shapes.Shape) {
+	s.parent = p
+}
+
+// Intersect marches along the ray and returns every point at which it
+// crosses the surface. A ray that starts inside the shape is also marched
+// backwards to find where it entered, so that the intersections pair up into
+// entries and exits, as CSG shapes and media need.
+func (s *SDF) Intersect(r rays.Ray) intersections.Intersections {
+	local := r.Transform(shapes.TransformAt(s, r.Time).Inverse())
+	scale := tuples.Magnitude(local.Direction)
+	if scale == 0 {
+		return intersections.NewIntersections()
+	}
+	// March in units of object space, and convert back to the ray's t.
+	direction := tuples.Divide(local.Direction, scale)
+	inside := s.Distance(local.Origin) < 0
+	steps := 0
+	var xs intersections.Intersections
+	if inside {
+		for _, d := range s.march(local.Origin, tuples.Negate(direction), inside, true, &steps) {
+			xs = append(xs, intersections.NewIntersection(-d/scale, s))
+		}
+	}
+	for _, d := range s.march(local.Origin, direction, inside, false, &steps) {
+		xs = append(xs, intersections.NewIntersection(d/scale, s))
+	}
+	return intersections.NewIntersections(xs...)
+}
+
+// march sphere traces from origin along the unit vector direction, starting
+// inside the surface if inside is set, and returns the distances at which it
+// crosses the surface, or only the first when marching backwards. It stops
+// after MaxDistance, or once steps, which counts the steps of every march
+// along a ray, reaches MaxSteps.
+//
+// A ray is only counted as hitting the surface once it has been at least
+// Epsilon clear of the last surface it crossed, so that rays leaving a
+// surface do not hit it again straight away. Until then it creeps forward
+// by Epsilon. A ray that never gets clear of a surface before ending up
+// clearly on its other side sets off across the surface it started on,
+// which counts only when marching backwards, or merely grazed the surface it
+// last hit, which then does not count.
+//
+// A ray that stops while still inside the shape crosses the surface where it
+// stopped, so that every entry has an exit, even for grazing rays that use up
+// their steps inside.
+func (s *SDF) march(origin, direction tuples.Tuple, inside, backwards bool, steps *int) []float64 {
+	var crossings []float64
+	away := false
+	t := 0.0
+	for ; t < s.MaxDistance && *steps < s.MaxSteps; *steps++ {
+		if backwards && len(crossings) > 0 {
+			break
+		}
+		// d is the distance to the surface ahead, negative once past it.
+		d := s.Distance(origin.Add(direction.Multiply(t)))
+		if inside {
+			d = -d
+		}
+		switch {
+		case d >= s.Epsilon:
+			away = true
+		case !away && d <= -s.Epsilon:
+			if len(crossings) > 0 {
+				crossings = crossings[:len(crossings)-1]
+			} else if backwards {
+				crossings = append(crossings, t)
+			}
+			inside, d, away = !inside, -d, true
+		case away:
+			crossings = append(crossings, t)
+			inside, d, away = !inside, -d, false
+		}
+		t += max(d, s.Epsilon)
+	}
+	if inside {
+		crossings = append(crossings, t)
+	}
+	return crossings
+}
+
+// NormalAt estimates the normal as the gradient of the distance, from
+// central differences Epsilon either side of the point along each axis.
+func (s *SDF) NormalAt(worldPoint tuples.Tuple, time float64) tuples.Tuple {
+	p := shapes.WorldToObject(s, worldPoint, time)
+	h := s.Epsilon
+	gradient := tuples.Vector(
+		s.Distance(p.Add(tuples.Vector(h, 0, 0)))-s.Distance(p.Add(tuples.Vector(-h, 0, 0))),
+		s.Distance(p.Add(tuples.Vector(0, h, 0)))-s.Distance(p.Add(tuples.Vector(0, -h, 0))),
+		s.Distance(p.Add(tuples.Vector(0, 0, h)))-s.Distance(p.Add(tuples.Vector(0, 0, -h))),
+	)
+	return shapes.NormalToWorld(s, gradient, time)
+}
@@ -0,0 +1,191 @@
+package sdf_test
+
+import (
+	"math"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/sdf"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarchingToSphere(t *testing.T) {
+	// Scenario: A ray marched through a unit sphere crosses it twice
+	// Given s ← sdf(sphere(1))
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When xs ← intersect(s, r)
+	// Then xs is 4 and 6, to within epsilon
+	s := sdf.New(sdf.Sphere(1))
+	xs := s.Intersect(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	require.Len(t, xs, 2)
+	assert.InDelta(t, 4, xs[0].T, s.Epsilon)
+	assert.InDelta(t, 6, xs[1].T, s.Epsilon)
+	assert.Equal(t, s, xs[0].Object)
+}
+
+func TestMarchingMisses(t *testing.T) {
+	// Scenario: A ray that passes a sphere by crosses nothing
+	s := sdf.New(sdf.Sphere(1))
+	xs := s.Intersect(rays.New(tuples.Point(0, 2, -5), tuples.Vector(0, 0, 1)))
+	assert.Empty(t, xs)
+}
+
+func TestMarchingFromInside(t *testing.T) {
+	// Scenario: A ray starting inside finds where it entered, behind it
+	// Given s ← sdf(sphere(1))
+	// When xs ← intersect(s, ray(point(0, 0, 0), vector(0, 0, 1)))
+	// Then xs is -1 and 1
+	s := sdf.New(sdf.Sphere(1))
+	xs := s.Intersect(rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 0, 1)))
+	require.Len(t, xs, 2)
+	assert.InDelta(t, -1, xs[0].T, s.Epsilon)
+	assert.InDelta(t, 1, xs[1].T, s.Epsilon)
+}
+
+func TestMarchingTransformedShape(t *testing.T) {
+	// Scenario: Marching a scaled shape along a ray of any length
+	// Given s ← sdf(sphere(1)) with transform scaling(2, 2, 2)
+	// When xs ← intersect(s, ray(point(0, 0, -5), vector(0, 0, 2)))
+	// Then xs is 1.5 and 3.5, in units of the ray's direction
+	s := sdf.New(sdf.Sphere(1))
+	s.SetTransform(matrices.Scaling(2, 2, 2))
+	xs := s.Intersect(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 2)))
+	require.Len(t, xs, 2)
+	assert.InDelta(t, 1.5, xs[0].T, s.Epsilon)
+	assert.InDelta(t, 3.5, xs[1].T, s.Epsilon)
+}
+
+func TestMarchingLimits(t *testing.T) {
+	// Scenario Outline: Rays give up after too many steps or too far
+	// Given s ← sdf(sphere(1)) with <limit>
+	// When xs ← intersect(s, ray(point(0, 0, -5), vector(0, 0, 1)))
+	// Then xs is empty
+	tests := []struct {
+		name  string
+		limit func(s *sdf.SDF)
+	}{
+		{"a single step", func(s *sdf.SDF) { s.MaxSteps = 1 }},
+		{"a maximum distance of 3", func(s *sdf.SDF) { s.MaxDistance = 3 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sdf.New(sdf.Sphere(1))
+			tt.limit(s)
+			assert.Empty(t, s.Intersect(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))))
+		})
+	}
+}
+
+func TestMarchingEpsilon(t *testing.T) {
+	// Scenario: A larger epsilon stops rays short of the surface
+	// Given a ray that strikes a unit sphere at a glancing angle
+	// When it is marched with an epsilon of 0.1
+	// Then it hits before the surface, but within 0.1 of it
+	r := rays.New(tuples.Point(0, 0.6, -5), tuples.Vector(0, 0, 1))
+	exact := 5 - math.Sqrt(1-0.6*0.6)
+	s := sdf.New(sdf.Sphere(1))
+	s.Epsilon = 0.1
+	xs := s.Intersect(r)
+	require.NotEmpty(t, xs)
+	assert.Less(t, xs[0].T, exact)
+	assert.Less(t, sdf.Sphere(1)(r.Position(xs[0].T)), 0.1)
+}
+
+func TestMarchingGrazingRay(t *testing.T) {
+	// Scenario: A ray that runs out of steps inside still leaves the shape
+	// Given s ← sdf(sphere(1))
+	// And r ← ray(point(0, 0.999, -5), vector(0, 0, 1)), which grazes it
+	// When xs ← intersect(s, r)
+	// Then xs is an entry near 4.955 and an exit after it
+	s := sdf.New(sdf.Sphere(1))
+	xs := s.Intersect(rays.New(tuples.Point(0, 0.999, -5), tuples.Vector(0, 0, 1)))
+	require.Len(t, xs, 2)
+	assert.InDelta(t, 5-math.Sqrt(1-0.999*0.999), xs[0].T, 0.01)
+	assert.Greater(t, xs[1].T, xs[0].T)
+}
+
+func TestLeavingSurface(t *testing.T) {
+	// Scenario Outline: A ray leaving the surface does not hit it again there
+	// Given s ← sdf(sphere(1))
+	// When a ray sets off from point(0, 0, -1) on its surface <direction>
+	// Then it crosses <count> surfaces, the far side at 2
+	tests := []struct {
+		name      string
+		direction tuples.Tuple
+		expected  []float64
+	}{
+		{"away from the sphere", tuples.Vector(0, 0, -1), nil},
+		{"into the sphere", tuples.Vector(0, 0, 1), []float64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sdf.New(sdf.Sphere(1))
+			xs := s.Intersect(rays.New(tuples.Point(0, 0, -1), tt.direction))
+			require.Len(t, xs, len(tt.expected))
+			for i, expected := range tt.expected {
+				assert.InDelta(t, expected, xs[i].T, s.Epsilon)
+			}
+		})
+	}
+}
+
+func TestSDFInCSG(t *testing.T) {
+	// Scenario: Marched shapes combine with other shapes in CSG
+	// Given s ← sdf(sphere(1))
+	// And c ← csg("difference", s, sphere() translated by (0, 0, -1))
+	// When xs ← intersect(c, ray(point(0, 0, -5), vector(0, 0, 1)))
+	// Then xs is the sphere's exit at 5 and s's exit at 6
+	s := sdf.New(sdf.Sphere(1))
+	cut := spheres.NewSphere()
+	cut.SetTransform(matrices.Translation(0, 0, -1))
+	c := csg.New(csg.Difference, s, cut)
+	xs := c.Intersect(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	require.Len(t, xs, 2)
+	assert.Equal(t, cut, xs[0].Object)
+	assert.InDelta(t, 5, xs[0].T, 1e-9)
+	assert.Equal(t, s, xs[1].Object)
+	assert.InDelta(t, 6, xs[1].T, s.Epsilon)
+}
+
+func TestSDFNormals(t *testing.T) {
+	// Scenario Outline: Normals are the gradient of the distance
+	// Given s ← sdf(<distance>) with <transform>
+	// Then the normal at <point> is <normal>
+	tests := []struct {
+		name      string
+		distance  sdf.Distance
+		transform matrices.Matrix
+		point     tuples.Tuple
+		normal    tuples.Tuple
+	}{
+		{
+			"on a sphere", sdf.Sphere(1), matrices.Identity(4),
+			tuples.Point(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3),
+			tuples.Vector(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3),
+		},
+		{
+			"on a translated sphere", sdf.Sphere(1), matrices.Translation(0, 1, 0),
+			tuples.Point(0, 1.70711, -0.70711), tuples.Vector(0, 0.70711, -0.70711),
+		},
+		{
+			"on the face of a box", sdf.Box(tuples.Vector(1, 1, 1)), matrices.Identity(4),
+			tuples.Point(1, 0.5, -0.2), tuples.Vector(1, 0, 0),
+		},
+		{
+			"on top of a torus", sdf.Torus(2, 0.5), matrices.Identity(4),
+			tuples.Point(0, 0.5, 2), tuples.Vector(0, 1, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sdf.New(tt.distance)
+			s.SetTransform(tt.transform)
+			assert.True(t, tt.normal.Equals(s.NormalAt(tt.point, 0)), "got %v", s.NormalAt(tt.point, 0))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package shapes
+
+import (
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/objects"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/tuples"
+)
+
+// Shape is implemented by everything that can be intersected by a ray.
+// Shapes may be nested inside composite shapes, in which case the parent's
+// transform is applied on top of the shape's own transform. A shape with a
+// motion moves over time, and uses the motion in place of its transform.
+type Shape interface {
+	objects.Object
+	Intersect(r rays.Ray) intersections.Intersections
+	// NormalAt returns the normal at a point on the surface, for a ray cast
+	// at time. Composite shapes such as CSG have no surface of their own,
+	// since every intersection they return refers to one of their children,
+	// and panic; callers take normals from the intersected object.
+	NormalAt(worldPoint tuples.Tuple, time float64) tuples.Tuple
+	GetTransform() matrices.Matrix
+	SetTransform(m matrices.Matrix)
+	GetMotion() *matrices.Motion
+	SetMotion(m *matrices.Motion)
+	GetMaterial() materials.Material
+	SetMaterial(m materials.Material)
+	Parent() Shape
+	SetParent(p Shape)
+}
+
+// SurfaceSampler is implemented by shapes whose surface can be sampled, so
+// that glowing shapes can light the scene.
+type SurfaceSampler interface {
+	Shape
+	// SampleSurface maps (u, v) from the unit square onto the surface in
+	// object space, spreading points evenly over its area, and returns the
+	// point and the unit normal there.
+	SampleSurface(u, v float64) (point, normal tuples.Tuple)
+	// SurfaceArea returns the area of the surface in object space.
+	SurfaceArea() float64
+}
+
+// TransformAt returns the transform of s at time: its motion at that time
+// if it has one, and its transform otherwise.
+func TransformAt(s Shape, time float64) matrices.Matrix {
+	if motion := s.GetMotion(); motion != nil {
+		return motion.At(time)
+	}
+	return s.GetTransform()
+}
+
+// WorldToObject converts a point from world space to the object space of s
+// at time, walking up through every parent.
+func WorldToObject(s Shape, point tuples.Tuple, time float64) tuples.Tuple {
+	if s.Parent() != nil {
+		point = WorldToObject(s.Parent(), point, time)
+	}
+	return TransformAt(s, time).Inverse().MultiplyTuple(point)
+}
+
+// ObjectToWorld converts a point from the object space of s at time to world
+// space, walking up through every parent. It undoes WorldToObject.
+func ObjectToWorld(s Shape, point tuples.Tuple, time float64) tuples.Tuple {
+	point = TransformAt(s, time).MultiplyTuple(point)
+	if s.Parent() != nil {
+		point = ObjectToWorld(s.Parent(), point, time)
+	}
+	return point
+}
+
+// NormalToWorld converts a normal from the object space of s at time to
+// world space, walking up through every parent.
+func NormalToWorld(s Shape, normal tuples.Tuple, time float64) tuples.Tuple {
+	normal = TransformAt(s, time).Inverse().Transpose().MultiplyTuple(normal)
+	normal.W = 0
+	normal = tuples.Normalize(normal)
+	if s.Parent() != nil {
+		normal = NormalToWorld(s.Parent(), normal, time)
+	}
+	return normal
+}
+
+// NormalToObject converts a normal from world space to the object space of
+// s at time, walking down from the outermost parent. It undoes
+// NormalToWorld.
+func NormalToObject(s Shape, normal tuples.Tuple, time float64) tuples.Tuple {
+	if s.Parent() != nil {
+		normal = NormalToObject(s.Parent(), normal, time)
+	}
+	normal = TransformAt(s, time).Transpose().MultiplyTuple(normal)
+	normal.W = 0
+	return tuples.Normalize(normal)
+}
@@ -0,0 +1,97 @@
+package shapes_test
+
+import (
+	"math"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertingPointFromWorldToObjectSpace(t *testing.T) {
+	// Scenario: Converting a point from world to object space
+	// Given c1 ← csg("union", c2, sphere()) with transform rotation_y(π/2)
+	// And c2 ← csg("union", s, sphere()) with transform scaling(2, 2, 2)
+	// And s ← sphere() with transform translation(5, 0, 0)
+	// When p ← world_to_object(s, point(-2, 0, -10))
+	// Then p = point(0, 0, -1)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(5, 0, 0))
+	c2 := csg.New(csg.Union, s, spheres.NewSphere())
+	c2.SetTransform(matrices.Scaling(2, 2, 2))
+	c1 := csg.New(csg.Union, c2, spheres.NewSphere())
+	c1.SetTransform(matrices.RotationY(math.Pi / 2))
+	p := shapes.WorldToObject(s, tuples.Point(-2, 0, -10), 0)
+	assert.True(t, p.Equals(tuples.Point(0, 0, -1)))
+}
+
+func TestConvertingPointFromObjectToWorldSpace(t *testing.T) {
+	// Scenario: Converting a point from object to world space
+	// Given the nested shapes of the previous scenario
+	// When p ← object_to_world(s, point(0, 0, -1))
+	// Then p = point(-2, 0, -10)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(5, 0, 0))
+	c2 := csg.New(csg.Union, s, spheres.NewSphere())
+	c2.SetTransform(matrices.Scaling(2, 2, 2))
+	c1 := csg.New(csg.Union, c2, spheres.NewSphere())
+	c1.SetTransform(matrices.RotationY(math.Pi / 2))
+	p := shapes.ObjectToWorld(s, tuples.Point(0, 0, -1), 0)
+	assert.True(t, p.Equals(tuples.Point(-2, 0, -10)))
+}
+
+func TestConvertingNormalFromObjectToWorldSpace(t *testing.T) {
+	// Scenario: Converting a normal from object to world space
+	// Given c1 ← csg("union", c2, sphere()) with transform rotation_y(π/2)
+	// And c2 ← csg("union", s, sphere()) with transform scaling(1, 2, 3)
+	// And s ← sphere() with transform translation(5, 0, 0)
+	// When n ← normal_to_world(s, vector(√3/3, √3/3, √3/3))
+	// Then n = vector(0.2857, 0.4286, -0.8571)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(5, 0, 0))
+	c2 := csg.New(csg.Union, s, spheres.NewSphere())
+	c2.SetTransform(matrices.Scaling(1, 2, 3))
+	c1 := csg.New(csg.Union, c2, spheres.NewSphere())
+	c1.SetTransform(matrices.RotationY(math.Pi / 2))
+	val := math.Sqrt(3) / 3
+	n := shapes.NormalToWorld(s, tuples.Vector(val, val, val), 0)
+	expected := tuples.Vector(0.2857, 0.4286, -0.8571)
+	assert.InDelta(t, expected.X, n.X, 0.0001)
+	assert.InDelta(t, expected.Y, n.Y, 0.0001)
+	assert.InDelta(t, expected.Z, n.Z, 0.0001)
+}
+
+func TestConvertingNormalFromWorldToObjectSpace(t *testing.T) {
+	// Scenario: Converting a normal from world to object space
+	// Given c1, c2 and s as when converting a normal from object to world space
+	// When n ← normal_to_object(s, vector(0.2857, 0.4286, -0.8571))
+	// Then n = vector(√3/3, √3/3, √3/3)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(5, 0, 0))
+	c2 := csg.New(csg.Union, s, spheres.NewSphere())
+	c2.SetTransform(matrices.Scaling(1, 2, 3))
+	c1 := csg.New(csg.Union, c2, spheres.NewSphere())
+	c1.SetTransform(matrices.RotationY(math.Pi / 2))
+	val := math.Sqrt(3) / 3
+	n := shapes.NormalToObject(s, shapes.NormalToWorld(s, tuples.Vector(val, val, val), 0), 0)
+	assert.True(t, tuples.Vector(val, val, val).Equals(n))
+}
+
+func TestTransformAtUsesMotion(t *testing.T) {
+	// Scenario: A shape's transform at a time comes from its motion, when it has one
+	// Given s ← sphere() with transform scaling(2, 2, 2)
+	// Then transform_at(s, 0.5) = scaling(2, 2, 2)
+	// When s moves from translation(0, 0, 0) to translation(0, 2, 0)
+	// Then transform_at(s, 0.5) = translation(0, 1, 0)
+	// And world_to_object(s, point(0, 1, 0), 0.5) = point(0, 0, 0)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Scaling(2, 2, 2))
+	assert.True(t, matrices.Scaling(2, 2, 2).Equals(shapes.TransformAt(s, 0.5)))
+	s.SetMotion(matrices.NewMotion(matrices.Identity(4), matrices.Translation(0, 2, 0)))
+	assert.True(t, matrices.Translation(0, 1, 0).Equals(shapes.TransformAt(s, 0.5)))
+	assert.True(t, tuples.Point(0, 0, 0).Equals(shapes.WorldToObject(s, tuples.Point(0, 1, 0), 0.5)))
+}
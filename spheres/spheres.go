@@ -4,18 +4,25 @@ package spheres
 import (
 	"math"
 	"raytracer-vibe/intersections"
+	"raytracer-vibe/materials"
 	"raytracer-vibe/matrices"
 	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
 	"raytracer-vibe/tuples"
 )
 
 type Sphere struct {
 	Transform matrices.Matrix
+	// Motion, when set, moves the sphere over time in place of Transform.
+	Motion   *matrices.Motion
+	Material materials.Material
+	parent   shapes.Shape
 }
 
 func NewSphere() *Sphere {
 	return &Sphere{
 		Transform: matrices.Identity(4),
+		Material:  materials.NewMaterial(),
 	}
 }
 
@@ -23,8 +30,36 @@ func (s *Sphere) SetTransform(m matrices.Matrix) {
 	s.Transform = m
 }
 
+func (s *Sphere) GetTransform() matrices.Matrix {
+	return s.Transform
+}
+
+func (s *Sphere) GetMotion() *matrices.Motion {
+	return s.Motion
+}
+
+func (s *Sphere) SetMotion(m *matrices.Motion) {
+	s.Motion = m
+}
+
+func (s *Sphere) GetMaterial() materials.Material {
+	return s.Material
+}
+
+func (s *Sphere) SetMaterial(m materials.Material) {
+	s.Material = m
+}
+
+func (s *Sphere) Parent() shapes.Shape {
+	return s.parent
+}
+
+func (s *Sphere) SetParent(p shapes.Shape) {
+	s.parent = p
+}
+
 func (s *Sphere) Intersect(r rays.Ray) intersections.Intersections {
-	ray2 := r.Transform(s.Transform.Inverse())
+	ray2 := r.Transform(shapes.TransformAt(s, r.Time).Inverse())
 
 	sphereToRay := ray2.Origin.Subtract(tuples.Point(0, 0, 0))
 	a := ray2.Direction.Dot(ray2.Direction)
@@ -45,10 +80,23 @@ func (s *Sphere) Intersect(r rays.Ray) intersections.Intersections {
 	)
 }
 
-func (s *Sphere) NormalAt(worldPoint tuples.Tuple) tuples.Tuple {
-	objectPoint := s.Transform.Inverse().MultiplyTuple(worldPoint)
+func (s *Sphere) NormalAt(worldPoint tuples.Tuple, time float64) tuples.Tuple {
+	objectPoint := shapes.WorldToObject(s, worldPoint, time)
 	objectNormal := objectPoint.Subtract(tuples.Point(0, 0, 0))
-	worldNormal := s.Transform.Inverse().Transpose().MultiplyTuple(objectNormal)
-	worldNormal.W = 0
-	return tuples.Normalize(worldNormal)
+	return shapes.NormalToWorld(s, objectNormal, time)
+}
+
+// SampleSurface maps (u, v) onto the unit sphere, with u choosing the height
+// and v the angle around the y axis. Archimedes' hat-box theorem says equal
+// bands of height have equal areas, so the points are spread evenly.
+func (s *Sphere) SampleSurface(u, v float64) (tuples.Tuple, tuples.Tuple) {
+	y := 1 - 2*u
+	r := math.Sqrt(max(0, 1-y*y))
+	phi := 2 * math.Pi * v
+	point := tuples.Point(r*math.Cos(phi), y, r*math.Sin(phi))
+	return point, point.Subtract(tuples.Point(0, 0, 0))
+}
+
+func (s *Sphere) SurfaceArea() float64 {
+	return 4 * math.Pi
 }
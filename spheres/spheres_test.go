@@ -2,6 +2,8 @@ package spheres_test
 
 import (
 	"math"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/materials"
 	"raytracer-vibe/matrices"
 	"raytracer-vibe/rays"
 	"raytracer-vibe/spheres"
@@ -103,24 +105,63 @@ func TestIntersectTranslatedSphereWithRay(t *testing.T) {
 	assert.Empty(t, xs)
 }
 
+func TestIntersectMovingSphere(t *testing.T) {
+	// Scenario Outline: A moving sphere is intersected where it is at the ray's time
+	// Given s ← sphere() moving from translation(0, 0, 0) to translation(4, 0, 0)
+	// And r ← ray(point(<x>, 0, -5), vector(0, 0, 1)) cast at <time>
+	// When xs ← intersect(s, r)
+	// Then xs.count = <count>
+	s := spheres.NewSphere()
+	s.SetMotion(matrices.NewMotion(matrices.Identity(4), matrices.Translation(4, 0, 0)))
+	tests := []struct {
+		x, time float64
+		count   int
+	}{
+		{0, 0, 2},
+		{0, 1, 0},
+		{4, 1, 2},
+		{2, 0.5, 2},
+		{2, 0, 0},
+	}
+	for _, tt := range tests {
+		r := rays.New(tuples.Point(tt.x, 0, -5), tuples.Vector(0, 0, 1))
+		r.Time = tt.time
+		assert.Len(t, s.Intersect(r), tt.count, "x %v at time %v", tt.x, tt.time)
+	}
+}
+
+func TestNormalOnMovingSphere(t *testing.T) {
+	// Scenario: The normal of a moving sphere depends on the time
+	// Given s ← sphere() turning from identity to rotation_z(π/2) and growing to twice its size
+	// Then normal_at(s, point(0, 1, 0), 0) = vector(0, 1, 0)
+	// And halfway through, when s has turned by π/4, normal_at(s, point(-√2/2, √2/2, 0), 0.5) = vector(-√2/2, √2/2, 0)
+	s := spheres.NewSphere()
+	s.SetMotion(matrices.NewMotion(matrices.Identity(4),
+		matrices.RotationZ(math.Pi/2).Multiply(matrices.Scaling(2, 1, 1))))
+	assert.True(t, tuples.Vector(0, 1, 0).Equals(s.NormalAt(tuples.Point(0, 1, 0), 0)))
+	val := math.Sqrt2 / 2
+	n := s.NormalAt(tuples.Point(-val, val, 0), 0.5)
+	assert.True(t, tuples.Vector(-val, val, 0).Equals(n))
+}
+
 func TestNormalOnSphereAtPointOnXAxis(t *testing.T) {
 	// Scenario: The normal on a sphere at a point on the x axis
 	s := spheres.NewSphere()
-	n := s.NormalAt(tuples.Point(1, 0, 0))
+	n := s.NormalAt(tuples.Point(1, 0, 0), 0)
 	assert.True(t, n.Equals(tuples.Vector(1, 0, 0)))
 }
 
 func TestNormalOnSphereAtPointOnYAxis(t *testing.T) {
 	// Scenario: The normal on a sphere at a point on the y axis
 	s := spheres.NewSphere()
-	n := s.NormalAt(tuples.Point(0, 1, 0))
+	n := s.NormalAt(tuples.Point(0, 1, 0), 0)
 	assert.True(t, n.Equals(tuples.Vector(0, 1, 0)))
 }
 
 func TestNormalOnSphereAtPointOnZAxis(t *testing.T) {
 	// Scenario: The normal on a sphere at a point on the z axis
 	s := spheres.NewSphere()
-	n := s.NormalAt(tuples.Point(0, 0, 1))
+	n := s.NormalAt(tuples.Point(0, 0, 1), 0)
 	assert.True(t, n.Equals(tuples.Vector(0, 0, 1)))
 }
 
@@ -128,7 +169,7 @@ func TestNormalOnSphereAtNonaxialPoint(t *testing.T) {
 	// Scenario: The normal on a sphere at a nonaxial point
 	s := spheres.NewSphere()
 	val := math.Sqrt(3) / 3
-	n := s.NormalAt(tuples.Point(val, val, val))
+	n := s.NormalAt(tuples.Point(val, val, val), 0)
 	assert.True(t, n.Equals(tuples.Vector(val, val, val)))
 }
 
@@ -136,7 +177,7 @@ func TestNormalIsNormalizedVector(t *testing.T) {
 	// Scenario: The normal is a normalized vector
 	s := spheres.NewSphere()
 	val := math.Sqrt(3) / 3
-	n := s.NormalAt(tuples.Point(val, val, val))
+	n := s.NormalAt(tuples.Point(val, val, val), 0)
 	assert.True(t, n.Equals(tuples.Normalize(n)))
 }
 
@@ -144,7 +185,7 @@ func TestComputingNormalOnTranslatedSphere(t *testing.T) {
 	// Scenario: Computing the normal on a translated sphere
 	s := spheres.NewSphere()
 	s.SetTransform(matrices.Translation(0, 1, 0))
-	n := s.NormalAt(tuples.Point(0, 1.70711, -0.70711))
+	n := s.NormalAt(tuples.Point(0, 1.70711, -0.70711), 0)
 	assert.True(t, n.Equals(tuples.Vector(0, 0.70711, -0.70711)))
 }
 
@@ -154,6 +195,64 @@ func TestComputingNormalOnTransformedSphere(t *testing.T) {
 	m := matrices.Scaling(1, 0.5, 1).Multiply(matrices.RotationZ(math.Pi / 5))
 	s.SetTransform(m)
 	val := math.Sqrt(2) / 2
-	n := s.NormalAt(tuples.Point(0, val, -val))
+	n := s.NormalAt(tuples.Point(0, val, -val), 0)
 	assert.True(t, n.Equals(tuples.Vector(0, 0.97014, -0.24254)))
 }
+
+func TestComputingNormalOnChildSphere(t *testing.T) {
+	// Scenario: Finding the normal on a child object
+	// Given c1 ← csg("union", c2, sphere())
+	// And set_transform(c1, rotation_y(π/2))
+	// And c2 ← csg("union", s, sphere())
+	// And set_transform(c2, scaling(1, 2, 3))
+	// And set_transform(s, translation(5, 0, 0))
+	// When n ← normal_at(s, point(1.7321, 1.1547, -5.5774))
+	// Then n = vector(0.2857, 0.4286, -0.8571)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(5, 0, 0))
+	c2 := csg.New(csg.Union, s, spheres.NewSphere())
+	c2.SetTransform(matrices.Scaling(1, 2, 3))
+	c1 := csg.New(csg.Union, c2, spheres.NewSphere())
+	c1.SetTransform(matrices.RotationY(math.Pi / 2))
+	n := s.NormalAt(tuples.Point(1.7321, 1.1547, -5.5774), 0)
+	expected := tuples.Vector(0.2857, 0.4286, -0.8571)
+	assert.InDelta(t, expected.X, n.X, 0.0001)
+	assert.InDelta(t, expected.Y, n.Y, 0.0001)
+	assert.InDelta(t, expected.Z, n.Z, 0.0001)
+}
+
+func TestSphereHasDefaultMaterial(t *testing.T) {
+	// Scenario: A sphere has a default material
+	s := spheres.NewSphere()
+	assert.Equal(t, materials.NewMaterial(), s.Material)
+}
+
+func TestSphereMayBeAssignedMaterial(t *testing.T) {
+	// Scenario: A sphere may be assigned a material
+	s := spheres.NewSphere()
+	m := materials.NewMaterial()
+	m.Ambient = 1
+	s.SetMaterial(m)
+	assert.Equal(t, m, s.Material)
+}
+
+func TestSamplingSphereSurface(t *testing.T) {
+	// Scenario: Points sampled on a sphere lie on it, spread evenly
+	// Given s ← sphere()
+	// When 400 points are sampled from a regular grid over the unit square
+	// Then each lies on the unit sphere, with its normal pointing out from the center
+	// And they average to the center
+	// And the area of s is 4π
+	s := spheres.NewSphere()
+	sum := tuples.Vector(0, 0, 0)
+	for i := range 20 {
+		for j := range 20 {
+			p, n := s.SampleSurface((float64(i)+0.5)/20, (float64(j)+0.5)/20)
+			assert.InDelta(t, 1, tuples.Magnitude(p.Subtract(tuples.Point(0, 0, 0))), 1e-9)
+			assert.True(t, p.Subtract(tuples.Point(0, 0, 0)).Equals(n))
+			sum = sum.Add(n)
+		}
+	}
+	assert.InDelta(t, 0, tuples.Magnitude(sum)/400, 1e-9)
+	assert.InDelta(t, 4*math.Pi, s.SurfaceArea(), 1e-9)
+}
@@ -26,6 +26,29 @@ func (c Color) Blue() float64 {
 	return c.Z
 }
 
+func (c Color) Add(c2 Color) Color {
+	return Color{c.Tuple.Add(c2.Tuple)}
+}
+
+func (c Color) Subtract(c2 Color) Color {
+	return Color{c.Tuple.Subtract(c2.Tuple)}
+}
+
+func (c Color) Multiply(scalar float64) Color {
+	return Color{c.Tuple.Multiply(scalar)}
+}
+
+// Hadamard multiplies two colors component by component.
+func (c Color) Hadamard(c2 Color) Color {
+	return NewColor(c.X*c2.X, c.Y*c2.Y, c.Z*c2.Z)
+}
+
+// Difference returns the largest absolute difference between the channels of
+// two colors.
+func (c Color) Difference(c2 Color) float64 {
+	return max(math.Abs(c.X-c2.X), math.Abs(c.Y-c2.Y), math.Abs(c.Z-c2.Z))
+}
+
 func (t1 Tuple) Add(t2 Tuple) Tuple {
 	return Tuple{
 		X: t1.X + t2.X,
@@ -121,6 +144,12 @@ func Cross(t1, t2 Tuple) Tuple {
 	)
 }
 
+// Reflect reflects the vector in around the normal.
+func Reflect(in, normal Tuple) Tuple {
+	const two = 2
+	return in.Subtract(normal.Multiply(two * in.Dot(normal)))
+}
+
 func New(x, y, z, w float64) Tuple {
 	return Tuple{X: x, Y: y, Z: z, W: w}
 }
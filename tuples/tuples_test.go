@@ -1,6 +1,7 @@
 package tuples_test
 
 import (
+	"math"
 	"raytracer-vibe/tuples"
 	"testing"
 
@@ -128,3 +129,54 @@ func TestColor(t *testing.T) {
 	assert.True(t, tuples.FloatEqual(0.4, c.Green()))
 	assert.True(t, tuples.FloatEqual(1.7, c.Blue()))
 }
+
+func TestAddingColors(t *testing.T) {
+	// Scenario: Adding colors
+	c1 := tuples.NewColor(0.9, 0.6, 0.75)
+	c2 := tuples.NewColor(0.7, 0.1, 0.25)
+	assert.True(t, tuples.NewColor(1.6, 0.7, 1.0).Equals(c1.Add(c2).Tuple))
+}
+
+func TestSubtractingColors(t *testing.T) {
+	// Scenario: Subtracting colors
+	c1 := tuples.NewColor(0.9, 0.6, 0.75)
+	c2 := tuples.NewColor(0.7, 0.1, 0.25)
+	assert.True(t, tuples.NewColor(0.2, 0.5, 0.5).Equals(c1.Subtract(c2).Tuple))
+}
+
+func TestMultiplyingColorByScalar(t *testing.T) {
+	// Scenario: Multiplying a color by a scalar
+	c := tuples.NewColor(0.2, 0.3, 0.4)
+	assert.True(t, tuples.NewColor(0.4, 0.6, 0.8).Equals(c.Multiply(2).Tuple))
+}
+
+func TestMultiplyingColors(t *testing.T) {
+	// Scenario: Multiplying colors
+	c1 := tuples.NewColor(1, 0.2, 0.4)
+	c2 := tuples.NewColor(0.9, 1, 0.1)
+	assert.True(t, tuples.NewColor(0.9, 0.2, 0.04).Equals(c1.Hadamard(c2).Tuple))
+}
+
+func TestDifferenceBetweenColors(t *testing.T) {
+	// Scenario: The difference between colors is the largest difference in any channel
+	c1 := tuples.NewColor(1, 0.2, 0.4)
+	c2 := tuples.NewColor(0.9, 1, 0.1)
+	assert.InDelta(t, 0.8, c1.Difference(c2), 0.00001)
+	assert.InDelta(t, 0.8, c2.Difference(c1), 0.00001)
+	assert.InDelta(t, 0.0, c1.Difference(c1), 0.00001)
+}
+
+func TestReflectingVectorApproachingAt45Degrees(t *testing.T) {
+	// Scenario: Reflecting a vector approaching at 45°
+	v := tuples.Vector(1, -1, 0)
+	n := tuples.Vector(0, 1, 0)
+	assert.True(t, tuples.Vector(1, 1, 0).Equals(tuples.Reflect(v, n)))
+}
+
+func TestReflectingVectorOffSlantedSurface(t *testing.T) {
+	// Scenario: Reflecting a vector off a slanted surface
+	v := tuples.Vector(0, -1, 0)
+	val := math.Sqrt(2) / 2
+	n := tuples.Vector(val, val, 0)
+	assert.True(t, tuples.Vector(1, 0, 0).Equals(tuples.Reflect(v, n)))
+}
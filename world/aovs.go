@@ -0,0 +1,191 @@
+package world
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/tuples"
+	"slices"
+)
+
+// AOV is an arbitrary output variable: something about what a ray first sees,
+// rendered into an image of its own alongside the beauty image for
+// compositing and debugging. Media are seen through, and rays that hit
+// nothing are black in every AOV but the albedo.
+type AOV int
+
+const (
+	// DepthAOV is the distance to the surface hit, in every channel.
+	DepthAOV AOV = iota
+	// NormalAOV is the shading normal of the surface hit in world space,
+	// facing the ray, with x, y and z in red, green and blue.
+	NormalAOV
+	// ObjectIDAOV gives each top-level object a color of its own, so that
+	// objects can be masked out of the image by color.
+	ObjectIDAOV
+	// MaterialIDAOV gives each named material a color of its own. Materials
+	// without a name, given inline in a scene, are black.
+	MaterialIDAOV
+	// AlbedoAOV is the color of the surface hit, or of the background.
+	AlbedoAOV
+	// ShadowAOV is the fraction of the lights' light that objects block from
+	// the surface hit, in every channel: 0 where every light reaches it and 1
+	// where none does.
+	ShadowAOV
+	// LightsAOV is the light that each light alone casts on the surface hit
+	// and that it sends towards the eye, shadows included, in an image per
+	// light.
+	LightsAOV
+)
+
+// AllAOVs returns every AOV, in order.
+func AllAOVs() []AOV {
+	return []AOV{DepthAOV, NormalAOV, ObjectIDAOV, MaterialIDAOV, AlbedoAOV, ShadowAOV, LightsAOV}
+}
+
+// String returns the name of the AOV.
+func (a AOV) String() string {
+	switch a {
+	case DepthAOV:
+		return "depth"
+	case NormalAOV:
+		return "normal"
+	case ObjectIDAOV:
+		return "object-id"
+	case MaterialIDAOV:
+		return "material-id"
+	case AlbedoAOV:
+		return "albedo"
+	case ShadowAOV:
+		return "shadow"
+	case LightsAOV:
+		return "lights"
+	}
+	return fmt.Sprintf("AOV(%d)", int(a))
+}
+
+// AOVNames returns the names of the images the AOVs render into, in the order
+// AOVsAt returns their colors: the name of each AOV, with LightsAOV giving
+// light-1, light-2 and so on for each of the world's lights.
+func (w *World) AOVNames(aovs []AOV) []string {
+	var names []string
+	for _, aov := range aovs {
+		if aov != LightsAOV {
+			names = append(names, aov.String())
+			continue
+		}
+		for i := range w.Lights {
+			names = append(names, fmt.Sprintf("light-%d", i+1))
+		}
+	}
+	return names
+}
+
+// AOVsAt returns the colors of the AOVs for the first surface the ray hits,
+// in the order of AOVNames.
+func (w *World) AOVsAt(r rays.Ray, aovs []AOV) []tuples.Color {
+	black := tuples.NewColor(0, 0, 0)
+	hit, found := w.surfaceHit(r)
+	if !found {
+		return w.missAOVs(r, aovs)
+	}
+	comps := PrepareComputations(hit, r)
+	material := comps.Material()
+	colors := make([]tuples.Color, 0, len(aovs))
+	for _, aov := range aovs {
+		switch aov {
+		case DepthAOV:
+			colors = append(colors, gray(hit.T*tuples.Magnitude(r.Direction)))
+		case NormalAOV:
+			colors = append(colors, tuples.NewColor(comps.NormalV.X, comps.NormalV.Y, comps.NormalV.Z))
+		case ObjectIDAOV:
+			colors = append(colors, idColor(fmt.Sprintf("object %d", w.objectIndex(comps.Object))))
+		case MaterialIDAOV:
+			if material.Name == "" {
+				colors = append(colors, black)
+			} else {
+				colors = append(colors, idColor("material "+material.Name))
+			}
+		case AlbedoAOV:
+			colors = append(colors, material.Color)
+		case ShadowAOV:
+			colors = append(colors, gray(w.shadowAt(comps)))
+		case LightsAOV:
+			for _, light := range w.Lights {
+				colors = append(colors, w.lightAlone(light, comps, material))
+			}
+		}
+	}
+	return colors
+}
+
+// missAOVs returns the colors of the AOVs for a ray that hits nothing.
+func (w *World) missAOVs(r rays.Ray, aovs []AOV) []tuples.Color {
+	black := tuples.NewColor(0, 0, 0)
+	colors := make([]tuples.Color, 0, len(aovs))
+	for _, aov := range aovs {
+		switch aov {
+		case DepthAOV, NormalAOV, ObjectIDAOV, MaterialIDAOV, ShadowAOV:
+			colors = append(colors, black)
+		case AlbedoAOV:
+			if w.Background == nil {
+				colors = append(colors, black)
+			} else {
+				colors = append(colors, w.Background.ColorAt(r.Direction))
+			}
+		case LightsAOV:
+			for range w.Lights {
+				colors = append(colors, black)
+			}
+		}
+	}
+	return colors
+}
+
+// objectIndex returns the index in w.Objects of the top-level object that
+// object is, or is part of.
+func (w *World) objectIndex(object shapes.Shape) int {
+	for object.Parent() != nil {
+		object = object.Parent()
+	}
+	return slices.Index(w.Objects, object)
+}
+
+// shadowAt returns the fraction of the lights' light that objects block from
+// reaching the hit.
+func (w *World) shadowAt(comps Computations) float64 {
+	if len(w.Lights) == 0 {
+		return 0
+	}
+	lit := 0.0
+	for _, light := range w.Lights {
+		lit += w.IntensityAt(light, comps.OverPoint, comps.Time)
+	}
+	return 1 - lit/float64(len(w.Lights))
+}
+
+// lightAlone returns the light that light casts on the hit and that the
+// material sends towards the eye, shaded as ShadeHit shades it but without
+// emission or reflections.
+func (w *World) lightAlone(light lights.Light, comps Computations, material materials.Material) tuples.Color {
+	if material.BSDF != nil {
+		return w.lightFrom(light, comps, material.BSDF, comps.Surface(material))
+	}
+	intensity := w.IntensityAt(light, comps.OverPoint, comps.Time)
+	return material.Lighting(light, comps.OverPoint, comps.EyeV, comps.NormalV, intensity)
+}
+
+// idColor returns a color hashed from key, the same from render to render and
+// unlikely to be shared with another key.
+func idColor(key string) tuples.Color {
+	const maximum = 255
+	sum := sha256.Sum256([]byte(key))
+	return tuples.NewColor(float64(sum[0])/maximum, float64(sum[1])/maximum, float64(sum[2])/maximum)
+}
+
+func gray(v float64) tuples.Color {
+	return tuples.NewColor(v, v, v)
+}
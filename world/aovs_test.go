@@ -0,0 +1,125 @@
+package world_test
+
+import (
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAOVNames(t *testing.T) {
+	// Scenario: The lights AOV renders an image per light
+	// Given w ← default_world() with a second light
+	// When names ← aov_names(w, [depth, lights, shadow])
+	// Then names = ["depth", "light-1", "light-2", "shadow"]
+	w := world.Default()
+	w.Lights = append(w.Lights, lights.NewPointLight(tuples.Point(10, 10, -10), tuples.NewColor(1, 1, 1)))
+	names := w.AOVNames([]world.AOV{world.DepthAOV, world.LightsAOV, world.ShadowAOV})
+	assert.Equal(t, []string{"depth", "light-1", "light-2", "shadow"}, names)
+}
+
+func TestAOVsOfHit(t *testing.T) {
+	// Scenario: The AOVs of the surface a ray hits
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When aovs ← aovs_at(w, r, all_aovs())
+	// Then the depth is 4 and the normal is vector(0, 0, -1)
+	// And the object ID is a color, but the unnamed material's ID is black
+	// And the albedo is color(0.8, 1.0, 0.6)
+	// And the surface is in no shadow
+	// And the light's contribution is the color shade_hit() gives
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	aovs := w.AOVsAt(r, world.AllAOVs())
+	require.Len(t, aovs, 7)
+	assert.True(t, tuples.NewColor(4, 4, 4).Equals(aovs[0].Tuple))
+	assert.True(t, tuples.NewColor(0, 0, -1).Equals(aovs[1].Tuple))
+	assert.False(t, tuples.NewColor(0, 0, 0).Equals(aovs[2].Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(aovs[3].Tuple))
+	assert.True(t, tuples.NewColor(0.8, 1.0, 0.6).Equals(aovs[4].Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(aovs[5].Tuple))
+	assert.True(t, tuples.NewColor(0.38066, 0.47583, 0.2855).Equals(aovs[6].Tuple))
+}
+
+func TestAOVsInShadow(t *testing.T) {
+	// Scenario: The AOVs of a surface in shadow
+	// Given w ← world() with point_light(point(0, 0, -10), color(1, 1, 1))
+	// And s1 ← sphere()
+	// And s2 ← sphere() with transform translation(0, 0, 10)
+	// When aovs ← aovs_at(w, ray(point(0, 0, 5), vector(0, 0, 1)), [shadow, lights])
+	// Then the shadow is 1
+	// And the light only contributes its ambient color(0.1, 0.1, 0.1)
+	w := world.New()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	s2 := spheres.NewSphere()
+	s2.SetTransform(matrices.Translation(0, 0, 10))
+	w.Objects = append(w.Objects, spheres.NewSphere(), s2)
+	r := rays.New(tuples.Point(0, 0, 5), tuples.Vector(0, 0, 1))
+	aovs := w.AOVsAt(r, []world.AOV{world.ShadowAOV, world.LightsAOV})
+	require.Len(t, aovs, 2)
+	assert.True(t, tuples.NewColor(1, 1, 1).Equals(aovs[0].Tuple))
+	assert.True(t, tuples.NewColor(0.1, 0.1, 0.1).Equals(aovs[1].Tuple))
+}
+
+func TestAOVIDs(t *testing.T) {
+	// Scenario: Object and material IDs
+	// Given a sphere at x = -3 and a CSG union of two spheres at x = 3
+	// And the first sphere and the CSG's left operand share a material named "red"
+	// When the AOVs are found for rays hitting the sphere and each operand
+	// Then the CSG's operands share an object ID that the sphere does not
+	// And the sphere and the CSG's left operand share a material ID
+	// And the CSG's right operand, with an unnamed material, has a black material ID
+	red := materials.NewMaterial()
+	red.Name = "red"
+	red.Color = tuples.NewColor(1, 0, 0)
+	sphere := spheres.NewSphere()
+	sphere.SetTransform(matrices.Translation(-3, 0, 0))
+	sphere.SetMaterial(red)
+	left := spheres.NewSphere()
+	left.SetMaterial(red)
+	right := spheres.NewSphere()
+	right.SetTransform(matrices.Translation(0, 2, 0))
+	union := csg.New(csg.Union, left, right)
+	union.SetTransform(matrices.Translation(3, 0, 0))
+	w := world.New()
+	w.Objects = append(w.Objects, sphere, union)
+
+	ids := func(x, y float64) []tuples.Color {
+		r := rays.New(tuples.Point(x, y, -5), tuples.Vector(0, 0, 1))
+		return w.AOVsAt(r, []world.AOV{world.ObjectIDAOV, world.MaterialIDAOV})
+	}
+	ofSphere, ofLeft, ofRight := ids(-3, 0), ids(3, 0), ids(3, 2)
+	assert.True(t, ofLeft[0].Equals(ofRight[0].Tuple))
+	assert.False(t, ofSphere[0].Equals(ofLeft[0].Tuple))
+	assert.True(t, ofSphere[1].Equals(ofLeft[1].Tuple))
+	assert.False(t, tuples.NewColor(0, 0, 0).Equals(ofSphere[1].Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(ofRight[1].Tuple))
+}
+
+func TestAOVsOfMiss(t *testing.T) {
+	// Scenario: A ray that hits nothing is black in every AOV but the albedo
+	// Given w ← default_world() with a solid background of color(0.2, 0.4, 0.6)
+	// When aovs ← aovs_at(w, ray(point(0, 0, -5), vector(0, 1, 0)), all_aovs())
+	// Then the albedo is color(0.2, 0.4, 0.6)
+	// And every other AOV is black
+	w := world.Default()
+	w.Background = backgrounds.NewSolid(tuples.NewColor(0.2, 0.4, 0.6))
+	aovs := w.AOVsAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 1, 0)), world.AllAOVs())
+	require.Len(t, aovs, 7)
+	for i, c := range aovs {
+		expected := tuples.NewColor(0, 0, 0)
+		if i == 4 {
+			expected = tuples.NewColor(0.2, 0.4, 0.6)
+		}
+		assert.True(t, expected.Equals(c.Tuple), "aov %d", i)
+	}
+}
@@ -0,0 +1,98 @@
+package world
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/tuples"
+)
+
+// emitters returns the glowing shapes that the path tracer samples for
+// direct lighting.
+func (w *World) emitters() []shapes.SurfaceSampler {
+	var emitters []shapes.SurfaceSampler
+	for _, o := range w.Objects {
+		if emitter, ok := asEmitter(o); ok {
+			emitters = append(emitters, emitter)
+		}
+	}
+	return emitters
+}
+
+// asEmitter returns s if it glows and its surface can be sampled. Glowing
+// shapes in CSG shapes, parts of whose surfaces may have been cut away,
+// still glow but only light what paths that hit them by chance. Shapes that
+// bound a medium are never seen, so never glow.
+func asEmitter(s shapes.Shape) (shapes.SurfaceSampler, bool) {
+	emitter, ok := s.(shapes.SurfaceSampler)
+	return emitter, ok && s.Parent() == nil && !isVolume(s) && !isBlack(s.GetMaterial().Emitted())
+}
+
+// emittedLight returns the light of a random point on a random glowing shape
+// that bsdf scatters towards the eye, weighted against the chance of the
+// path hitting the point by itself.
+func (w *World) emittedLight(
+	comps Computations, emitters []shapes.SurfaceSampler, bsdf bsdfs.BSDF, surface bsdfs.Surface, rng *rand.Rand,
+) tuples.Color {
+	if len(emitters) == 0 {
+		return tuples.NewColor(0, 0, 0)
+	}
+	emitter := emitters[rng.IntN(len(emitters))]
+	objectPoint, objectNormal := emitter.SampleSurface(rng.Float64(), rng.Float64())
+	point := shapes.ObjectToWorld(emitter, objectPoint, comps.Time)
+	origin := comps.RayTowards(point.Subtract(comps.Point)).Origin
+	toLight := point.Subtract(origin)
+	distance := tuples.Magnitude(toLight)
+	wi := toLight.Multiply(1 / distance)
+	scattered := bsdf.Evaluate(surface, wi)
+	cosine := math.Abs(shapes.NormalToWorld(emitter, objectNormal, comps.Time).Dot(wi))
+	if isBlack(scattered) || cosine == 0 {
+		return tuples.NewColor(0, 0, 0)
+	}
+	visible := w.visibility(origin, lights.Sample{Direction: wi, Distance: distance - epsilon}, comps.Time)
+	if visible == 0 {
+		return tuples.NewColor(0, 0, 0)
+	}
+	density := areaDensity(emitter, objectPoint, objectNormal, comps.Time)
+	pdf := density * distance * distance / cosine / float64(len(emitters))
+	weight := powerHeuristic(pdf, bsdf.PDF(surface, wi))
+	return scattered.Hadamard(emitter.GetMaterial().Emitted()).Multiply(visible * weight / pdf)
+}
+
+// emitterPDF returns the probability density, per unit of solid angle, of
+// emittedLight choosing the point hit on emitter, seen from point from, out
+// of count glowing shapes.
+func emitterPDF(emitter shapes.SurfaceSampler, hit Computations, from tuples.Tuple, count int) float64 {
+	normal := emitter.NormalAt(hit.Point, hit.Time)
+	objectPoint := shapes.WorldToObject(emitter, hit.Point, hit.Time)
+	objectNormal := shapes.NormalToObject(emitter, normal, hit.Time)
+	toLight := hit.Point.Subtract(from)
+	distance2 := toLight.Dot(toLight)
+	cosine := math.Abs(normal.Dot(tuples.Normalize(toLight)))
+	if cosine == 0 {
+		return 0
+	}
+	density := areaDensity(emitter, objectPoint, objectNormal, hit.Time)
+	return density * distance2 / cosine / float64(count)
+}
+
+// areaDensity returns the probability density, per unit of world space area,
+// of sampling the point on emitter at objectPoint, with objectNormal. Points
+// are spread evenly in object space, so the density falls wherever the
+// emitter's transform stretches its surface.
+func areaDensity(emitter shapes.SurfaceSampler, objectPoint, objectNormal tuples.Tuple, time float64) float64 {
+	frame := bsdfs.NewFrame(tuples.Normalize(objectNormal))
+	origin := shapes.ObjectToWorld(emitter, objectPoint, time)
+	u := shapes.ObjectToWorld(emitter, objectPoint.Add(frame.U), time).Subtract(origin)
+	v := shapes.ObjectToWorld(emitter, objectPoint.Add(frame.V), time).Subtract(origin)
+	return 1 / (emitter.SurfaceArea() * tuples.Magnitude(tuples.Cross(u, v)))
+}
+
+// powerHeuristic returns the weight of an estimate made by a technique that
+// chose its direction with density pdf, against another technique that would
+// have chosen it with density other.
+func powerHeuristic(pdf, other float64) float64 {
+	return pdf * pdf / (pdf*pdf + other*other)
+}
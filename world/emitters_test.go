@@ -0,0 +1,79 @@
+package world_test
+
+import (
+	"math"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// glowingSphere returns a black sphere giving off color(e, e, e), placed by
+// transform.
+func glowingSphere(e float64, transform matrices.Matrix) *spheres.Sphere {
+	s := spheres.NewSphere()
+	s.SetTransform(transform)
+	s.Material.Color = tuples.NewColor(0, 0, 0)
+	s.Material.Emission = tuples.NewColor(e, e, e)
+	return s
+}
+
+func TestGlowingSphereLightsFloor(t *testing.T) {
+	// Scenario: A glowing sphere lights the floor beneath it like a lamp
+	// Given w ← world() with a flat floor of color(0.5, 0.5, 0.5) and diffuse 1
+	// And a sphere of radius 1 giving off color(16, 16, 16), centered 4 units above the floor
+	// When a ray hitting the floor beneath the sphere is path traced
+	// Then the paths average color(0.5, 0.5, 0.5), the floor's color times 16 times the sphere's (1/4)² of the sky
+	f := floor()
+	f.Material.Color = tuples.NewColor(0.5, 0.5, 0.5)
+	f.Material.Diffuse = 1
+	w := world.New()
+	w.Objects = append(w.Objects, f, glowingSphere(16, matrices.Translation(0, 4, 0)))
+	r := rays.New(tuples.Point(0, 1, -1), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	assert.InDelta(t, 0.5, averagePath(w, r, 5000).Red(), 0.02)
+}
+
+func TestSampledGlowMatchesHitGlow(t *testing.T) {
+	// Scenario: Sampling a stretched glowing shape gives the light of paths hitting it
+	// Given a flat white floor under a glowing ellipsoid
+	// And the same floor under the same ellipsoid inside a CSG union, which is never sampled
+	// When rays hitting the floor beneath it are path traced in both worlds
+	// Then the paths average the same color
+	// And those that sample the ellipsoid vary less
+	stretched := matrices.Translation(1, 2, 0).
+		Multiply(matrices.RotationZ(0.5)).
+		Multiply(matrices.Scaling(1.5, 0.2, 0.4))
+	f := floor()
+	f.Material.Diffuse = 1
+	sampled := world.New()
+	sampled.Objects = append(sampled.Objects, f, glowingSphere(2, stretched))
+	union := csg.New(csg.Union, glowingSphere(2, stretched), glowingSphere(0, matrices.Translation(0, -50, 0)))
+	unsampled := world.New()
+	unsampled.Objects = append(unsampled.Objects, f, union)
+
+	r := rays.New(tuples.Point(0, 1, -1), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	mean, spread := pathStatistics(sampled, r, 2000)
+	hitMean, hitSpread := pathStatistics(unsampled, r, 40000)
+	assert.InDelta(t, hitMean, mean, 0.01)
+	assert.Less(t, spread*1.5, hitSpread)
+}
+
+// pathStatistics returns the mean and standard deviation of the red of n
+// paths traced along r.
+func pathStatistics(w *world.World, r rays.Ray, n int) (float64, float64) {
+	p := world.NewPathTracer()
+	sum, sum2 := 0.0, 0.0
+	for i := range n {
+		red := p.ColorAt(w, r, sampling.PathRand(0, 0, i)).Red()
+		sum += red
+		sum2 += red * red
+	}
+	mean := sum / float64(n)
+	return mean, math.Sqrt(sum2/float64(n) - mean*mean)
+}
@@ -0,0 +1,39 @@
+package world
+
+import (
+	"raytracer-vibe/rays"
+	"raytracer-vibe/tuples"
+)
+
+// Features describe what a ray first sees, for guiding a denoiser: they are
+// free of the noise of path tracing, so edges in them are real edges.
+type Features struct {
+	// Albedo is the color of the surface hit, or of the background for
+	// rays that hit nothing.
+	Albedo tuples.Color
+	// Normal is the shading normal of the surface hit, facing the ray, or
+	// the zero vector for rays that hit nothing.
+	Normal tuples.Tuple
+	// Depth is the distance to the surface hit, or 0 for rays that hit
+	// nothing.
+	Depth float64
+}
+
+// FeaturesAt returns the features of the first surface the ray hits. Media
+// are seen through.
+func (w *World) FeaturesAt(r rays.Ray) Features {
+	hit, found := w.surfaceHit(r)
+	if !found {
+		features := Features{Albedo: tuples.NewColor(0, 0, 0), Normal: tuples.Vector(0, 0, 0)}
+		if w.Background != nil {
+			features.Albedo = w.Background.ColorAt(r.Direction)
+		}
+		return features
+	}
+	comps := PrepareComputations(hit, r)
+	return Features{
+		Albedo: comps.Material().Color,
+		Normal: comps.NormalV,
+		Depth:  hit.T * tuples.Magnitude(r.Direction),
+	}
+}
@@ -0,0 +1,40 @@
+package world_test
+
+import (
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeaturesOfHit(t *testing.T) {
+	// Scenario: The features of the surface a ray hits
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 2))
+	// When f ← features_at(w, r)
+	// Then f.albedo = color(0.8, 1.0, 0.6)
+	// And f.normal = vector(0, 0, -1)
+	// And f.depth = 4, measured in units rather than along the unnormalized direction
+	w := world.Default()
+	f := w.FeaturesAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 2)))
+	assert.True(t, tuples.NewColor(0.8, 1.0, 0.6).Equals(f.Albedo.Tuple))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(f.Normal))
+	assert.InDelta(t, 4, f.Depth, 1e-9)
+}
+
+func TestFeaturesOfMiss(t *testing.T) {
+	// Scenario: A ray that hits nothing sees only the background
+	// Given w ← default_world() with a solid background of color(0.2, 0.4, 0.6)
+	// When f ← features_at(w, ray(point(0, 0, -5), vector(0, 1, 0)))
+	// Then f.albedo = color(0.2, 0.4, 0.6)
+	// And f.normal = vector(0, 0, 0) and f.depth = 0
+	w := world.Default()
+	w.Background = backgrounds.NewSolid(tuples.NewColor(0.2, 0.4, 0.6))
+	f := w.FeaturesAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 1, 0)))
+	assert.True(t, tuples.NewColor(0.2, 0.4, 0.6).Equals(f.Albedo.Tuple))
+	assert.True(t, tuples.Vector(0, 0, 0).Equals(f.Normal))
+	assert.Zero(t, f.Depth)
+}
@@ -0,0 +1,177 @@
+package world
+
+import (
+	"math"
+	"math/rand/v2"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/tuples"
+)
+
+const (
+	// DefaultPathDepth is the most bounces a path may take.
+	DefaultPathDepth = 16
+	// DefaultRouletteDepth is the number of bounces a path always takes
+	// before Russian roulette may end it.
+	DefaultRouletteDepth = 3
+	// maxSurvival caps the chance of a path surviving Russian roulette, so
+	// that paths between white mirrors still end.
+	maxSurvival = 0.95
+)
+
+// Integrator computes the color seen along a ray cast from the camera,
+// drawing any random numbers it needs from rng.
+type Integrator interface {
+	ColorAt(w *World, r rays.Ray, rng *rand.Rand) tuples.Color
+}
+
+// Whitted shades surfaces with the Phong model, following mirror
+// reflections but no other light bounced between surfaces. It draws the
+// world's fog, and media dim what is seen through them and the shadows they
+// cast, but scatter no light.
+type Whitted struct{}
+
+func (Whitted) ColorAt(w *World, r rays.Ray, _ *rand.Rand) tuples.Color {
+	return w.ColorAt(r)
+}
+
+// PathTracer follows each ray as it bounces around the scene, so that
+// surfaces are lit by each other as well as by the lights, as in color
+// bleeding. Each bounce picks one direction at random, so a pixel needs many
+// samples for the noise to average out.
+//
+// Surfaces with a BSDF scatter light as it says. Others mirror a Reflective
+// fraction of the light and scatter the rest diffusely, tinted by their color
+// and diffuse; ambient and specular are ignored. Lights are as bright as in
+// the Phong model: a white surface facing a light head on is lit as with a
+// diffuse of 1.
+//
+// Paths through a medium may be absorbed or scattered along the way, so that
+// light beams show in smoke. The world's fog is ignored.
+type PathTracer struct {
+	// MaxDepth is the most bounces a path may take.
+	MaxDepth int
+	// RouletteDepth is the number of bounces after which paths are ended at
+	// random, more likely the less light they can still carry, with the
+	// light of those that survive scaled up to make up for the others.
+	RouletteDepth int
+}
+
+func NewPathTracer() PathTracer {
+	return PathTracer{MaxDepth: DefaultPathDepth, RouletteDepth: DefaultRouletteDepth}
+}
+
+// ColorAt returns the light arriving along the ray, estimated from a single
+// random path. Rays that miss see the background, which bounced rays only
+// see if it shows in reflections.
+//
+// At each surface, the light of a random point on a random glowing shape is
+// added as well as that of the lights. Paths that go on to hit a glowing
+// shape count its glow too, so both estimates are weighted by multiple
+// importance sampling: each counts most for the directions it is likeliest
+// to choose.
+func (p PathTracer) ColorAt(w *World, r rays.Ray, rng *rand.Rand) tuples.Color {
+	color := tuples.NewColor(0, 0, 0)
+	throughput := tuples.NewColor(1, 1, 1)
+	emitters := w.emitters()
+	bounce := bsdfs.Sample{}
+	for depth := 0; depth <= p.MaxDepth; depth++ {
+		hit, found := w.surfaceHit(r)
+		limit := math.Inf(1)
+		if found {
+			limit = hit.T
+		}
+		var comps Computations
+		var direct tuples.Color
+		var ok bool
+		if t, medium, stopped := w.sampleMedium(r, limit, rng); stopped {
+			comps = mediumComputations(r, t)
+			direct, bounce, ok = w.scatterMedium(comps, medium, emitters, rng)
+		} else {
+			if !found {
+				if w.Background != nil && (depth == 0 || w.ReflectBackground) {
+					color = color.Add(throughput.Hadamard(w.Background.ColorAt(r.Direction)))
+				}
+				break
+			}
+			comps = PrepareComputations(hit, r)
+			material := comps.Material()
+			emission := material.Emitted()
+			if emitter, glows := asEmitter(comps.Object); glows && bounce.PDF > 0 {
+				emission = emission.Multiply(powerHeuristic(bounce.PDF, emitterPDF(emitter, comps, r.Origin, len(emitters))))
+			}
+			color = color.Add(throughput.Hadamard(emission))
+			direct, bounce, ok = w.scatter(comps, material, emitters, rng)
+		}
+		color = color.Add(throughput.Hadamard(direct))
+		if !ok {
+			break
+		}
+		throughput = throughput.Hadamard(bounce.Weight)
+		r = comps.RayTowards(bounce.Direction)
+		if depth >= p.RouletteDepth {
+			survival := min(max(throughput.Red(), throughput.Green(), throughput.Blue()), maxSurvival)
+			if rng.Float64() >= survival {
+				break
+			}
+			throughput = throughput.Multiply(1 / survival)
+		}
+	}
+	return color
+}
+
+// scatter returns the light the surface scatters towards the eye straight
+// from the lights and glowing shapes, and a random direction in which to
+// continue the path, weighted by the fraction of the light from there that
+// reaches the eye. It reports false when the path ends at the surface.
+func (w *World) scatter(
+	comps Computations, material materials.Material, emitters []shapes.SurfaceSampler, rng *rand.Rand,
+) (tuples.Color, bsdfs.Sample, bool) {
+	bsdf, surface := material.BSDF, comps.Surface(material)
+	if bsdf == nil {
+		if rng.Float64() < material.Reflective {
+			return tuples.NewColor(0, 0, 0), bsdfs.Sample{Direction: comps.ReflectV, Weight: tuples.NewColor(1, 1, 1)}, true
+		}
+		// The light that is not mirrored is scattered diffusely.
+		bsdf = bsdfs.Lambertian{}
+		surface.Color = material.Color.Multiply(material.Diffuse)
+	}
+	direct := w.directLight(comps, bsdf, surface).Add(w.emittedLight(comps, emitters, bsdf, surface, rng))
+	bounce, ok := bsdf.Sample(surface, rng)
+	return direct, bounce, ok
+}
+
+// directLight returns the light from every light in the world that bsdf
+// scatters towards the eye. Lights are scaled by π, so that a white
+// Lambertian surface facing a light head on is as bright as with the Phong
+// model and a diffuse of 1.
+func (w *World) directLight(comps Computations, bsdf bsdfs.BSDF, surface bsdfs.Surface) tuples.Color {
+	total := tuples.NewColor(0, 0, 0)
+	for _, light := range w.Lights {
+		total = total.Add(w.lightFrom(light, comps, bsdf, surface))
+	}
+	return total
+}
+
+// lightFrom returns the light from a single light that bsdf scatters towards
+// the eye, scaled by π as in directLight.
+func (w *World) lightFrom(light lights.Light, comps Computations, bsdf bsdfs.BSDF, surface bsdfs.Surface) tuples.Color {
+	samples := light.Samples(comps.OverPoint)
+	sum := tuples.NewColor(0, 0, 0)
+	for _, s := range samples {
+		scattered := bsdf.Evaluate(surface, s.Direction)
+		if isBlack(scattered) {
+			continue
+		}
+		visible := w.visibility(comps.RayTowards(s.Direction).Origin, s, comps.Time)
+		sum = sum.Add(s.Intensity.Hadamard(scattered).Multiply(visible))
+	}
+	return sum.Multiply(math.Pi / float64(len(samples)))
+}
+
+func isBlack(c tuples.Color) bool {
+	return c.Red() == 0 && c.Green() == 0 && c.Blue() == 0
+}
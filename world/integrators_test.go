@@ -0,0 +1,186 @@
+package world_test
+
+import (
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/sampling"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// floor returns a sphere so large that its top, at y = 0, is all but flat.
+func floor() *spheres.Sphere {
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(0, -1000, 0).Multiply(matrices.Scaling(1000, 1000, 1000)))
+	return s
+}
+
+// averagePath returns the average color of n paths traced along r.
+func averagePath(w *world.World, r rays.Ray, n int) tuples.Color {
+	p := world.NewPathTracer()
+	sum := tuples.NewColor(0, 0, 0)
+	for i := range n {
+		sum = sum.Add(p.ColorAt(w, r, sampling.PathRand(0, 0, i)))
+	}
+	return sum.Multiply(1 / float64(n))
+}
+
+func TestWhittedIntegrator(t *testing.T) {
+	// Scenario: The Whitted integrator shades as the world does
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// Then whitted().color_at(w, r) = color_at(w, r)
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	c := world.Whitted{}.ColorAt(w, r, sampling.PathRand(0, 0, 0))
+	assert.True(t, w.ColorAt(r).Equals(c.Tuple))
+}
+
+func TestPathTracerSeesEmission(t *testing.T) {
+	// Scenario: A path that hits a black glowing surface returns its glow
+	// Given w ← world() with a black sphere giving off color(1, 0.5, 0)
+	// When r ← ray(point(0, 0, -5), vector(0, 0, 1)) is path traced
+	// Then the color is color(1, 0.5, 0)
+	s := spheres.NewSphere()
+	s.Material.Color = tuples.NewColor(0, 0, 0)
+	s.Material.Emission = tuples.NewColor(1, 0.5, 0)
+	w := world.New()
+	w.Objects = append(w.Objects, s)
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	c := world.NewPathTracer().ColorAt(w, r, sampling.PathRand(0, 0, 0))
+	assert.True(t, tuples.NewColor(1, 0.5, 0).Equals(c.Tuple))
+}
+
+func TestPathTracerDirectLight(t *testing.T) {
+	// Scenario: Lights shine on diffuse surfaces as in the Phong model
+	// Given w ← world() with a flat floor of color(0.5, 0.5, 0.5) and diffuse 0.9
+	// And a white point light straight above the floor
+	// When a ray hitting the floor is path traced
+	// Then the color is color(0.45, 0.45, 0.45), with no noise since every bounce escapes
+	f := floor()
+	f.Material.Color = tuples.NewColor(0.5, 0.5, 0.5)
+	w := world.New()
+	w.Objects = append(w.Objects, f)
+	w.Lights = append(w.Lights, lights.NewPointLight(tuples.Point(0, 10, 0), tuples.NewColor(1, 1, 1)))
+	r := rays.New(tuples.Point(0, 1, -1), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	for i := range 10 {
+		c := world.NewPathTracer().ColorAt(w, r, sampling.PathRand(0, 0, i))
+		assert.True(t, tuples.NewColor(0.45, 0.45, 0.45).Equals(c.Tuple), "path %d: %v", i, c)
+	}
+}
+
+func TestPathTracerInFurnace(t *testing.T) {
+	// Scenario: Light bounces around inside a glowing sphere
+	// Given w ← world() with a sphere giving off color(0.5, 0.5, 0.5) and reflecting half the light it receives
+	// When rays from its center are path traced
+	// Then they average color(1, 1, 1): the glow plus half of everything the sphere receives
+	s := spheres.NewSphere()
+	s.Material.Color = tuples.NewColor(0.5, 0.5, 0.5)
+	s.Material.Diffuse = 1
+	s.Material.Emission = tuples.NewColor(0.5, 0.5, 0.5)
+	w := world.New()
+	w.Objects = append(w.Objects, s)
+	r := rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 0, 1))
+	c := averagePath(w, r, 4000)
+	assert.InDelta(t, 1, c.Red(), 0.03)
+}
+
+func TestPathTracerFollowsMirrors(t *testing.T) {
+	// Scenario: A mirror shows what it faces
+	// Given w ← world() with a mirror floor and a sphere giving off color(0, 1, 0) above it
+	// When a ray hitting the floor and reflecting towards the sphere is path traced
+	// Then the color is color(0, 1, 0)
+	f := floor()
+	f.Material.Reflective = 1
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(0, 3, 3))
+	s.Material.Color = tuples.NewColor(0, 0, 0)
+	s.Material.Emission = tuples.NewColor(0, 1, 0)
+	w := world.New()
+	w.Objects = append(w.Objects, f, s)
+	r := rays.New(tuples.Point(0, 3, -3), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	c := world.NewPathTracer().ColorAt(w, r, sampling.PathRand(0, 0, 0))
+	assert.True(t, tuples.NewColor(0, 1, 0).Equals(c.Tuple))
+}
+
+func TestPathTracerSkyLight(t *testing.T) {
+	// Scenario: A background that shows in reflections lights the scene
+	// Given w ← world() with a white floor under a solid background of color(0.5, 0.5, 1)
+	// When a ray that hits the floor is path traced
+	// Then it is black while the background only shows behind objects
+	// And lit by the background when it shows in reflections too
+	f := floor()
+	f.Material.Diffuse = 1
+	w := world.New()
+	w.Objects = append(w.Objects, f)
+	w.Background = backgrounds.NewSolid(tuples.NewColor(0.5, 0.5, 1))
+	r := rays.New(tuples.Point(0, 1, -1), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(averagePath(w, r, 10).Tuple))
+	w.ReflectBackground = true
+	assert.True(t, tuples.NewColor(0.5, 0.5, 1).Equals(averagePath(w, r, 10).Tuple))
+}
+
+func TestPathTracerBleedsColor(t *testing.T) {
+	// Scenario: Light bounced off a colored wall tints the floor beside it
+	// Given w ← world() with a white floor, a red wall and a light above them
+	// When a ray hitting the floor next to the wall is path traced and traced by Whitted
+	// Then the Whitted color is gray
+	// And the path traced color is redder than it is green
+	f := floor()
+	wall := spheres.NewSphere()
+	wall.SetTransform(matrices.Translation(1001, 0, 0).Multiply(matrices.Scaling(1000, 1000, 1000)))
+	wall.Material.Color = tuples.NewColor(1, 0, 0)
+	w := world.New()
+	w.Objects = append(w.Objects, f, wall)
+	w.Lights = append(w.Lights, lights.NewPointLight(tuples.Point(0, 5, 0), tuples.NewColor(1, 1, 1)))
+	r := rays.New(tuples.Point(0.5, 1, -1), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	whitted := w.ColorAt(r)
+	assert.InDelta(t, whitted.Red(), whitted.Green(), 1e-9)
+	path := averagePath(w, r, 400)
+	assert.Greater(t, path.Red(), path.Green()*1.2)
+}
+
+func TestPathTracerLambertianDirectLight(t *testing.T) {
+	// Scenario: Lights shine on Lambertian surfaces as on Phong ones with a diffuse of 1
+	// Given w ← world() with a flat Lambertian floor of color(0.5, 0.5, 0.5)
+	// And a white point light straight above the floor
+	// When a ray hitting the floor is path traced
+	// Then the color is color(0.5, 0.5, 0.5)
+	f := floor()
+	f.Material.Color = tuples.NewColor(0.5, 0.5, 0.5)
+	f.Material.BSDF = bsdfs.Lambertian{}
+	w := world.New()
+	w.Objects = append(w.Objects, f)
+	w.Lights = append(w.Lights, lights.NewPointLight(tuples.Point(0, 10, 0), tuples.NewColor(1, 1, 1)))
+	r := rays.New(tuples.Point(0, 1, -1), tuples.Normalize(tuples.Vector(0, -1, 1)))
+	for i := range 10 {
+		c := world.NewPathTracer().ColorAt(w, r, sampling.PathRand(0, 0, i))
+		assert.True(t, tuples.NewColor(0.5, 0.5, 0.5).Equals(c.Tuple), "path %d: %v", i, c)
+	}
+}
+
+func TestPathTracerThroughGlass(t *testing.T) {
+	// Scenario Outline: Glass neither adds nor removes light
+	// Given w ← world() with a ball of <roughness> glass inside a large sphere giving off color(1, 1, 1)
+	// When rays at the ball are path traced
+	// Then they average color(1, 1, 1)
+	for _, roughness := range []float64{0, 0.3} {
+		ball := spheres.NewSphere()
+		ball.Material.BSDF = bsdfs.NewDielectric(roughness, 1.5)
+		glow := spheres.NewSphere()
+		glow.SetTransform(matrices.Scaling(10, 10, 10))
+		glow.Material.Color = tuples.NewColor(0, 0, 0)
+		glow.Material.Emission = tuples.NewColor(1, 1, 1)
+		w := world.New()
+		w.Objects = append(w.Objects, ball, glow)
+		r := rays.New(tuples.Point(0.3, 0.2, -5), tuples.Vector(0, 0, 1))
+		assert.InDelta(t, 1, averagePath(w, r, 2000).Red(), 0.03, "roughness %v", roughness)
+	}
+}
@@ -0,0 +1,128 @@
+package world
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/media"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/tuples"
+	"slices"
+)
+
+// span is the stretch of a ray from t = start to t = end inside a medium.
+type span struct {
+	start, end float64
+	medium     *media.Homogeneous
+}
+
+// isVolume reports whether s only bounds a medium, and so is not seen itself.
+func isVolume(s shapes.Shape) bool {
+	return s.GetMaterial().Medium != nil
+}
+
+// spans returns the stretches of r, up to t = limit, that pass through the
+// media filling the world's top-level shapes, in order along the ray. The
+// shapes must be closed and must not overlap. Media in CSG shapes are ignored.
+func (w *World) spans(r rays.Ray, limit float64) []span {
+	var spans []span
+	for _, o := range w.Objects {
+		if !isVolume(o) {
+			continue
+		}
+		// A ray enters and leaves a closed shape in turn, so its
+		// intersections pair up around the stretches inside.
+		xs := o.Intersect(r)
+		for i := 0; i+1 < len(xs); i += 2 {
+			start, end := max(xs[i].T, 0), min(xs[i+1].T, limit)
+			if start < end {
+				spans = append(spans, span{start: start, end: end, medium: o.GetMaterial().Medium})
+			}
+		}
+	}
+	slices.SortFunc(spans, func(a, b span) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	return spans
+}
+
+// transmittance returns the fraction of light passing along r up to t =
+// limit that gets through the media on the way.
+func (w *World) transmittance(r rays.Ray, limit float64) float64 {
+	length := tuples.Magnitude(r.Direction)
+	fraction := 1.0
+	for _, s := range w.spans(r, limit) {
+		fraction *= s.medium.Transmittance((s.end - s.start) * length)
+	}
+	return fraction
+}
+
+// sampleMedium returns where along r, before t = limit, light is stopped by
+// a medium, chosen at random in proportion to the chance of it stopping
+// there, and the medium that stops it. It reports false when the light gets
+// through every medium, which happens as often as transmittance says.
+func (w *World) sampleMedium(r rays.Ray, limit float64, rng *rand.Rand) (float64, *media.Homogeneous, bool) {
+	length := tuples.Magnitude(r.Direction)
+	for _, s := range w.spans(r, limit) {
+		t := s.start + s.medium.SampleDistance(rng)/length
+		if t < s.end {
+			return t, s.medium, true
+		}
+	}
+	return 0, nil, false
+}
+
+// surfaceHit returns the first surface the ray hits that is not just the
+// bound of a medium.
+func (w *World) surfaceHit(r rays.Ray) (intersections.Intersection, bool) {
+	for _, x := range w.Intersect(r) {
+		if shape, ok := x.Object.(shapes.Shape); x.T < 0 || (ok && isVolume(shape)) {
+			continue
+		}
+		return x, true
+	}
+	return intersections.Intersection{}, false
+}
+
+// visibility returns the fraction of the light sample s that reaches point,
+// with the objects where they are at time: none if an object blocks it, and
+// otherwise what gets through the media on the way.
+func (w *World) visibility(point tuples.Tuple, s lights.Sample, time float64) float64 {
+	r := rays.New(point, s.Direction)
+	r.Time = time
+	if hit, found := w.surfaceHit(r); found && hit.T < s.Distance {
+		return 0
+	}
+	return w.transmittance(r, s.Distance)
+}
+
+// mediumComputations returns the state of the point at t along r, inside a
+// medium, where it scatters light as a surface facing the eye would.
+func mediumComputations(r rays.Ray, t float64) Computations {
+	point := r.Position(t)
+	eye := tuples.Normalize(tuples.Negate(r.Direction))
+	return Computations{
+		T:          t,
+		Point:      point,
+		OverPoint:  point,
+		UnderPoint: point,
+		EyeV:       eye,
+		NormalV:    eye,
+		Time:       r.Time,
+	}
+}
+
+// scatterMedium returns, as scatter does for surfaces, the light that
+// medium scatters towards the eye straight from the lights and glowing
+// shapes, and a random direction in which to continue the path.
+func (w *World) scatterMedium(
+	comps Computations, medium *media.Homogeneous, emitters []shapes.SurfaceSampler, rng *rand.Rand,
+) (tuples.Color, bsdfs.Sample, bool) {
+	surface := bsdfs.Surface{Normal: comps.NormalV, Out: comps.EyeV, Color: medium.Albedo(), Entering: true}
+	direct := w.directLight(comps, medium.Phase, surface).Add(w.emittedLight(comps, emitters, medium.Phase, surface, rng))
+	bounce, ok := medium.Phase.Sample(surface, rng)
+	return direct, bounce, ok
+}
@@ -0,0 +1,105 @@
+package world_test
+
+import (
+	"math"
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/media"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// volume returns a sphere of the given radius, at the origin, filled with a
+// medium that absorbs and scatters light as given.
+func volume(radius, absorption, scattering float64) *spheres.Sphere {
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Scaling(radius, radius, radius))
+	medium := media.NewHomogeneous(absorption, scattering)
+	s.Material.Medium = &medium
+	return s
+}
+
+func TestFogFadesDistantObjects(t *testing.T) {
+	// Scenario: Fog fades objects and the background into its color
+	// Given w ← default_world() with fog of color(0.5, 0.5, 0.5) and density 0.1
+	// When a ray from point(0, 0, -5) hits the outer sphere 4 units away
+	// Then the color is the sphere's faded e^-0.4 of the way to the fog
+	// And a ray that misses sees only the fog
+	w := world.Default()
+	unfogged := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	fog := media.NewFog(tuples.NewColor(0.5, 0.5, 0.5), 0.1)
+	w.Fog = &fog
+	seen := math.Exp(-0.4)
+	expected := unfogged.Multiply(seen).Add(tuples.NewColor(0.5, 0.5, 0.5).Multiply(1 - seen))
+	assert.True(t, expected.Equals(w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))).Tuple))
+	missed := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 1, 0)))
+	assert.True(t, tuples.NewColor(0.5, 0.5, 0.5).Equals(missed.Tuple))
+}
+
+func TestWhittedSeesThroughMedia(t *testing.T) {
+	// Scenario: The Whitted integrator dims what is seen through a medium
+	// Given w ← world() with a white background
+	// And a sphere of radius 1 filled with a medium absorbing 0.5 and scattering 0.5 per unit
+	// When a ray passes through the middle of the sphere
+	// Then the sphere itself is not seen
+	// And the background is dimmed by e^-2
+	w := world.New()
+	w.Background = backgrounds.NewSolid(tuples.NewColor(1, 1, 1))
+	w.Objects = append(w.Objects, volume(1, 0.5, 0.5))
+	c := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	assert.InDelta(t, math.Exp(-2), c.Red(), 1e-9)
+	assert.InDelta(t, math.Exp(-2), c.Blue(), 1e-9)
+}
+
+func TestMediaDimShadows(t *testing.T) {
+	// Scenario: Light passing through a medium is dimmed but not blocked
+	// Given w ← world() with a sphere of radius 1 absorbing 1 per unit of distance
+	// And a point light at point(0, 0, -5)
+	// Then nothing shadows point(0, 0, 5) from the light
+	// And its intensity is e^-2
+	w := world.New()
+	w.Objects = append(w.Objects, volume(1, 1, 0))
+	light := lights.NewPointLight(tuples.Point(0, 0, -5), tuples.NewColor(1, 1, 1))
+	w.Lights = append(w.Lights, light)
+	point := tuples.Point(0, 0, 5)
+	assert.False(t, w.IsShadowed(point, light.Samples(point)[0], 0))
+	assert.InDelta(t, math.Exp(-2), w.IntensityAt(light, point, 0), 1e-9)
+}
+
+func TestPathTracerThroughAbsorbingMedium(t *testing.T) {
+	// Scenario: Paths through an absorbing medium are cut short
+	// Given w ← world() with a white background
+	// And a sphere of radius 1 absorbing 0.5 per unit of distance
+	// When rays through the middle of the sphere are path traced
+	// Then they average e^-1, the fraction that gets through
+	w := world.New()
+	w.Background = backgrounds.NewSolid(tuples.NewColor(1, 1, 1))
+	w.Objects = append(w.Objects, volume(1, 0.5, 0))
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	assert.InDelta(t, math.Exp(-1), averagePath(w, r, 4000).Red(), 0.02)
+}
+
+func TestPathTracerScattersInMedium(t *testing.T) {
+	// Scenario: A medium that only scatters keeps all the light of a uniform glow
+	// Given w ← world() inside a black sphere of radius 10 giving off color(1, 1, 1)
+	// And a sphere of radius 2 filled with a medium scattering 1 per unit of distance
+	// And the medium scatters forwards, with g = 0.5, and is tinted color(0.5, 1, 1)
+	// When rays from its center are path traced
+	// Then their green averages 1: scattering moves light around but loses none of it
+	// And their red is far less, since most of it is scattered at least once
+	fog := volume(2, 0, 1)
+	fog.Material.Medium.Phase = media.NewHenyeyGreenstein(0.5)
+	fog.Material.Medium.Color = tuples.NewColor(0.5, 1, 1)
+	w := world.New()
+	w.Objects = append(w.Objects, glowingSphere(1, matrices.Scaling(10, 10, 10)), fog)
+	r := rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 0, 1))
+	c := averagePath(w, r, 2000)
+	assert.InDelta(t, 1, c.Green(), 0.03)
+	assert.Less(t, c.Red(), 0.7)
+}
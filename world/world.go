@@ -0,0 +1,284 @@
+package world
+
+import (
+	"math"
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/materials"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/media"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+)
+
+const (
+	// epsilon is the distance a hit point is nudged along its normal to keep
+	// it from shadowing itself.
+	epsilon = 0.00001
+	// MaxDepth is the number of times a ray may be reflected before it is
+	// given up as black.
+	MaxDepth = 5
+)
+
+// World is a collection of shapes and the lights illuminating them.
+type World struct {
+	Objects []shapes.Shape
+	Lights  []lights.Light
+	// Background colors the rays that hit nothing. Nil is black.
+	Background backgrounds.Background
+	// ReflectBackground shows the background in reflections, rather than
+	// only behind the objects.
+	ReflectBackground bool
+	// Fog, when set, fades distant objects and the background into its
+	// color. Only the Whitted integrator draws it; the path tracer can fill
+	// the scene with a medium instead.
+	Fog *media.Fog
+}
+
+func New() *World {
+	return &World{}
+}
+
+// Default returns the two concentric spheres lit by a single white light used
+// throughout the tests.
+func Default() *World { // nolint: mnd  // the default world is defined by fixed values
+	s1 := spheres.NewSphere()
+	m := materials.NewMaterial()
+	m.Color = tuples.NewColor(0.8, 1.0, 0.6)
+	m.Diffuse = 0.7
+	m.Specular = 0.2
+	s1.SetMaterial(m)
+
+	s2 := spheres.NewSphere()
+	s2.SetTransform(matrices.Scaling(0.5, 0.5, 0.5))
+
+	return &World{
+		Objects: []shapes.Shape{s1, s2},
+		Lights:  []lights.Light{lights.NewPointLight(tuples.Point(-10, 10, -10), tuples.NewColor(1, 1, 1))},
+	}
+}
+
+// Intersect intersects the ray with every object in the world.
+func (w *World) Intersect(r rays.Ray) intersections.Intersections {
+	var xs intersections.Intersections
+	for _, o := range w.Objects {
+		xs = append(xs, o.Intersect(r)...)
+	}
+	return intersections.NewIntersections(xs...)
+}
+
+// ShadeHit returns the color at the intersection described by comps: the
+// light the surface gives off, plus its color summed over every light in the
+// world, plus what the surface reflects. Surfaces with a BSDF also pass on
+// what they transmit.
+func (w *World) ShadeHit(comps Computations) tuples.Color {
+	return w.shadeHit(comps, MaxDepth)
+}
+
+func (w *World) shadeHit(comps Computations, remaining int) tuples.Color {
+	material := comps.Material()
+	if material.BSDF != nil {
+		return w.shadeBSDF(comps, material, remaining)
+	}
+	color := material.Emitted()
+	for _, light := range w.Lights {
+		intensity := w.IntensityAt(light, comps.OverPoint, comps.Time)
+		color = color.Add(material.Lighting(light, comps.OverPoint, comps.EyeV, comps.NormalV, intensity))
+	}
+	return color.Add(w.ReflectedColor(comps, remaining))
+}
+
+// shadeBSDF shades a surface with a BSDF, lit by the lights and by what it
+// reflects or transmits in the perfect mirror and refraction directions.
+// Rough surfaces are shown as sharply in those directions as smooth ones, and
+// with no ambient light, surfaces in shadow are black; the path tracer has
+// neither shortcoming.
+func (w *World) shadeBSDF(comps Computations, material materials.Material, remaining int) tuples.Color {
+	surface := comps.Surface(material)
+	color := material.Emitted().Add(w.directLight(comps, material.BSDF, surface))
+	if remaining < 1 {
+		return color
+	}
+	for _, s := range material.BSDF.Specular(surface) {
+		seen := w.colorAt(comps.RayTowards(s.Direction), remaining-1, w.ReflectBackground)
+		color = color.Add(seen.Hadamard(s.Weight))
+	}
+	return color
+}
+
+// ReflectedColor returns the light a reflective surface mirrors towards the
+// eye, following at most remaining reflections.
+func (w *World) ReflectedColor(comps Computations, remaining int) tuples.Color {
+	reflective := comps.Object.GetMaterial().Reflective
+	if remaining < 1 || reflective == 0 {
+		return tuples.NewColor(0, 0, 0)
+	}
+	r := rays.New(comps.OverPoint, comps.ReflectV)
+	r.Time = comps.Time
+	return w.colorAt(r, remaining-1, w.ReflectBackground).Multiply(reflective)
+}
+
+// ColorAt returns the color seen along the ray, or the background if it hits
+// nothing.
+func (w *World) ColorAt(r rays.Ray) tuples.Color {
+	return w.colorAt(r, MaxDepth, true)
+}
+
+// colorAt returns the color seen along the ray, following at most remaining
+// reflections. Rays that miss see the background if background is set, and
+// black otherwise. Media dim what is seen through them but scatter no light
+// of their own, and fog fades it.
+func (w *World) colorAt(r rays.Ray, remaining int, background bool) tuples.Color {
+	color := tuples.NewColor(0, 0, 0)
+	distance := math.Inf(1)
+	if hit, found := w.surfaceHit(r); found {
+		color = w.shadeHit(PrepareComputations(hit, r), remaining)
+		distance = hit.T
+	} else if background && w.Background != nil {
+		color = w.Background.ColorAt(r.Direction)
+	}
+	color = color.Multiply(w.transmittance(r, distance))
+	if w.Fog != nil {
+		color = w.Fog.Apply(color, distance*tuples.Magnitude(r.Direction))
+	}
+	return color
+}
+
+// IntensityAt returns the fraction of the light's samples that reach point
+// without being blocked by an object, where the objects are at time, dimmed
+// by any media they pass through.
+func (w *World) IntensityAt(light lights.Light, point tuples.Tuple, time float64) float64 {
+	samples := light.Samples(point)
+	lit := 0.0
+	for _, s := range samples {
+		lit += w.visibility(point, s, time)
+	}
+	return lit / float64(len(samples))
+}
+
+// IsShadowed reports whether an object, where it is at time, lies between
+// point and the light sample. Media cast no shadows of their own.
+func (w *World) IsShadowed(point tuples.Tuple, s lights.Sample, time float64) bool {
+	r := rays.New(point, s.Direction)
+	r.Time = time
+	hit, found := w.surfaceHit(r)
+	return found && hit.T < s.Distance
+}
+
+// Computations holds the precomputed state of an intersection needed for shading.
+type Computations struct {
+	T         float64
+	Object    shapes.Shape
+	Point     tuples.Tuple
+	OverPoint tuples.Tuple
+	// UnderPoint is just beneath the surface, where rays passing through it
+	// start.
+	UnderPoint tuples.Tuple
+	EyeV       tuples.Tuple
+	NormalV    tuples.Tuple
+	ReflectV   tuples.Tuple
+	Inside     bool
+	// Time is when the ray that hit the object was cast.
+	Time float64
+	// Footprint is the width of the ray's cone where it hits the object.
+	Footprint float64
+}
+
+// PrepareComputations computes the point, eye, normal and reflection vectors
+// of the intersection, flipping the normal when the hit is inside the object.
+func PrepareComputations(hit intersections.Intersection, r rays.Ray) Computations {
+	object, ok := hit.Object.(shapes.Shape)
+	if !ok {
+		panic("world: intersection object is not a shape")
+	}
+	comps := Computations{
+		T:      hit.T,
+		Object: object,
+		Point:  r.Position(hit.T),
+		EyeV:   tuples.Negate(r.Direction),
+		Time:   r.Time,
+	}
+	comps.Footprint = r.Spread * hit.T * tuples.Magnitude(r.Direction)
+	normal := object.NormalAt(comps.Point, r.Time)
+	comps.NormalV = bumpNormal(object, comps.Point, normal, r.Time)
+	if normal.Dot(comps.EyeV) < 0 {
+		comps.Inside = true
+		normal = tuples.Negate(normal)
+		comps.NormalV = tuples.Negate(comps.NormalV)
+	}
+	comps.ReflectV = tuples.Reflect(r.Direction, comps.NormalV)
+	// The point is moved off the true surface, not the bumped one, which
+	// may point into the object.
+	comps.OverPoint = comps.Point.Add(normal.Multiply(epsilon))
+	comps.UnderPoint = comps.Point.Subtract(normal.Multiply(epsilon))
+	return comps
+}
+
+// bumpNormal returns the shading normal at a point on object at time, with
+// world space normal, tilted by the bump of the object's material.
+func bumpNormal(object shapes.Shape, point, normal tuples.Tuple, time float64) tuples.Tuple {
+	bump := object.GetMaterial().Bump
+	if bump == nil {
+		return normal
+	}
+	objectNormal := bump.Perturb(
+		shapes.WorldToObject(object, point, time), shapes.NormalToObject(object, normal, time))
+	return shapes.NormalToWorld(object, objectNormal, time)
+}
+
+// Material returns the material of the object hit, with its color taken
+// from its pattern at the hit point, averaged over the ray's footprint.
+func (c Computations) Material() materials.Material {
+	material := c.Object.GetMaterial()
+	dx, dy := c.FootprintAxes()
+	material.Color = material.FilteredColorAt(
+		shapes.WorldToObject(c.Object, c.OverPoint, c.Time),
+		shapes.WorldToObject(c.Object, dx, c.Time),
+		shapes.WorldToObject(c.Object, dy, c.Time))
+	return material
+}
+
+// Surface returns the state a BSDF needs to shade the hit point with
+// material.
+func (c Computations) Surface(material materials.Material) bsdfs.Surface {
+	return bsdfs.Surface{Normal: c.NormalV, Out: c.EyeV, Color: material.Color, Entering: !c.Inside}
+}
+
+// RayTowards returns the ray leaving the hit point in direction, starting
+// above the surface or, if the direction passes through it, beneath.
+func (c Computations) RayTowards(direction tuples.Tuple) rays.Ray {
+	origin := c.OverPoint
+	if direction.Dot(c.OverPoint.Subtract(c.Point)) < 0 {
+		origin = c.UnderPoint
+	}
+	r := rays.New(origin, direction)
+	r.Time = c.Time
+	return r
+}
+
+// FootprintAxes returns two vectors from the hit point, along the surface,
+// spanning the area covered by the ray's cone: one across the ray and one
+// along it, stretched by how obliquely the ray meets the surface. Both are
+// zero for a ray with no spread.
+func (c Computations) FootprintAxes() (tuples.Tuple, tuples.Tuple) {
+	const minCosine = 0.01
+	if c.Footprint == 0 {
+		return tuples.Vector(0, 0, 0), tuples.Vector(0, 0, 0)
+	}
+	across := tuples.Cross(c.NormalV, c.EyeV)
+	if tuples.Magnitude(across) < epsilon {
+		// The ray hits head on, so any direction along the surface will do.
+		across = tuples.Cross(c.NormalV, tuples.Vector(1, 0, 0))
+		if tuples.Magnitude(across) < epsilon {
+			across = tuples.Cross(c.NormalV, tuples.Vector(0, 1, 0))
+		}
+	}
+	across = tuples.Normalize(across)
+	along := tuples.Cross(across, c.NormalV)
+	cosine := max(math.Abs(c.NormalV.Dot(c.EyeV)), minCosine)
+	return across.Multiply(c.Footprint), along.Multiply(c.Footprint / cosine)
+}
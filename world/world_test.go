@@ -0,0 +1,685 @@
+package world_test
+
+import (
+	"math"
+	"raytracer-vibe/backgrounds"
+	"raytracer-vibe/bsdfs"
+	"raytracer-vibe/csg"
+	"raytracer-vibe/intersections"
+	"raytracer-vibe/lights"
+	"raytracer-vibe/matrices"
+	"raytracer-vibe/patterns"
+	"raytracer-vibe/rays"
+	"raytracer-vibe/shapes"
+	"raytracer-vibe/spheres"
+	"raytracer-vibe/tuples"
+	"raytracer-vibe/world"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatingWorld(t *testing.T) {
+	// Scenario: Creating a world
+	// Given w ← world()
+	// Then w contains no objects
+	// And w has no light source
+	w := world.New()
+	assert.Empty(t, w.Objects)
+	assert.Empty(t, w.Lights)
+}
+
+func TestDefaultWorld(t *testing.T) {
+	// Scenario: The default world
+	// Then w.light = light
+	// And w contains s1
+	// And w contains s2
+	w := world.Default()
+	assert.Len(t, w.Lights, 1)
+	light, ok := w.Lights[0].(lights.PointLight)
+	require.True(t, ok)
+	assert.True(t, tuples.Point(-10, 10, -10).Equals(light.Position))
+	assert.Len(t, w.Objects, 2)
+	assert.True(t, tuples.NewColor(0.8, 1.0, 0.6).Equals(w.Objects[0].GetMaterial().Color.Tuple))
+	assert.True(t, w.Objects[1].GetTransform().Equals(matrices.Scaling(0.5, 0.5, 0.5)))
+}
+
+func TestIntersectWorldWithRay(t *testing.T) {
+	// Scenario: Intersect a world with a ray
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When xs ← intersect_world(w, r)
+	// Then xs.count = 4
+	// And xs[0].t = 4
+	// And xs[1].t = 4.5
+	// And xs[2].t = 5.5
+	// And xs[3].t = 6
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	xs := w.Intersect(r)
+	assert.Len(t, xs, 4)
+	assert.InEpsilon(t, 4.0, xs[0].T, 0.00001)
+	assert.InEpsilon(t, 4.5, xs[1].T, 0.00001)
+	assert.InEpsilon(t, 5.5, xs[2].T, 0.00001)
+	assert.InEpsilon(t, 6.0, xs[3].T, 0.00001)
+}
+
+func TestPrecomputingStateOfIntersection(t *testing.T) {
+	// Scenario: Precomputing the state of an intersection
+	// Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And shape ← sphere()
+	// And i ← intersection(4, shape)
+	// When comps ← prepare_computations(i, r)
+	// Then comps.t = i.t
+	// And comps.object = i.object
+	// And comps.point = point(0, 0, -1)
+	// And comps.eyev = vector(0, 0, -1)
+	// And comps.normalv = vector(0, 0, -1)
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	shape := spheres.NewSphere()
+	i := intersections.NewIntersection(4, shape)
+	comps := world.PrepareComputations(i, r)
+	assert.InEpsilon(t, i.T, comps.T, 0.00001)
+	assert.Equal(t, shape, comps.Object)
+	assert.True(t, tuples.Point(0, 0, -1).Equals(comps.Point))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(comps.EyeV))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(comps.NormalV))
+}
+
+func TestHitWhenIntersectionOccursOnOutside(t *testing.T) {
+	// Scenario: The hit, when an intersection occurs on the outside
+	// Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And shape ← sphere()
+	// And i ← intersection(4, shape)
+	// When comps ← prepare_computations(i, r)
+	// Then comps.inside = false
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, spheres.NewSphere()), r)
+	assert.False(t, comps.Inside)
+}
+
+func TestHitWhenIntersectionOccursOnInside(t *testing.T) {
+	// Scenario: The hit, when an intersection occurs on the inside
+	// Given r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	// And shape ← sphere()
+	// And i ← intersection(1, shape)
+	// When comps ← prepare_computations(i, r)
+	// Then comps.point = point(0, 0, 1)
+	// And comps.eyev = vector(0, 0, -1)
+	// And comps.inside = true
+	// # normal would have been (0, 0, 1), but is inverted!
+	// And comps.normalv = vector(0, 0, -1)
+	r := rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(1, spheres.NewSphere()), r)
+	assert.True(t, tuples.Point(0, 0, 1).Equals(comps.Point))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(comps.EyeV))
+	assert.True(t, comps.Inside)
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(comps.NormalV))
+}
+
+func TestHitShouldOffsetThePoint(t *testing.T) {
+	// Scenario: The hit should offset the point
+	// Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And shape ← sphere() with transform translation(0, 0, 1)
+	// And i ← intersection(5, shape)
+	// When comps ← prepare_computations(i, r)
+	// Then comps.over_point.z < -EPSILON/2
+	// And comps.point.z > comps.over_point.z
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	shape := spheres.NewSphere()
+	shape.SetTransform(matrices.Translation(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(5, shape), r)
+	assert.Less(t, comps.OverPoint.Z, -0.00001/2)
+	assert.Greater(t, comps.Point.Z, comps.OverPoint.Z)
+}
+
+func TestShadingIntersection(t *testing.T) {
+	// Scenario: Shading an intersection
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And shape ← the first object in w
+	// And i ← intersection(4, shape)
+	// When comps ← prepare_computations(i, r)
+	// And c ← shade_hit(w, comps)
+	// Then c = color(0.38066, 0.47583, 0.2855)
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, w.Objects[0]), r)
+	c := w.ShadeHit(comps)
+	assert.True(t, tuples.NewColor(0.38066, 0.47583, 0.2855).Equals(c.Tuple))
+}
+
+func TestShadingIntersectionFromInside(t *testing.T) {
+	// Scenario: Shading an intersection from the inside
+	// Given w ← default_world()
+	// And w.light ← point_light(point(0, 0.25, 0), color(1, 1, 1))
+	// And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	// And shape ← the second object in w
+	// And i ← intersection(0.5, shape)
+	// When comps ← prepare_computations(i, r)
+	// And c ← shade_hit(w, comps)
+	// Then c = color(0.90498, 0.90498, 0.90498)
+	w := world.Default()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0.25, 0), tuples.NewColor(1, 1, 1))}
+	r := rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(0.5, w.Objects[1]), r)
+	c := w.ShadeHit(comps)
+	assert.True(t, tuples.NewColor(0.90498, 0.90498, 0.90498).Equals(c.Tuple))
+}
+
+func TestShadeHitGivenIntersectionInShadow(t *testing.T) {
+	// Scenario: shade_hit() is given an intersection in shadow
+	// Given w ← world()
+	// And w.light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	// And s1 ← sphere()
+	// And s2 ← sphere() with transform translation(0, 0, 10)
+	// And r ← ray(point(0, 0, 5), vector(0, 0, 1))
+	// And i ← intersection(4, s2)
+	// When comps ← prepare_computations(i, r)
+	// And c ← shade_hit(w, comps)
+	// Then c = color(0.1, 0.1, 0.1)
+	w := world.New()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	s1 := spheres.NewSphere()
+	s2 := spheres.NewSphere()
+	s2.SetTransform(matrices.Translation(0, 0, 10))
+	w.Objects = append(w.Objects, s1, s2)
+	r := rays.New(tuples.Point(0, 0, 5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, s2), r)
+	c := w.ShadeHit(comps)
+	assert.True(t, tuples.NewColor(0.1, 0.1, 0.1).Equals(c.Tuple))
+}
+
+func TestColorWhenRayMisses(t *testing.T) {
+	// Scenario: The color when a ray misses
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 1, 0))
+	// When c ← color_at(w, r)
+	// Then c = color(0, 0, 0)
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 1, 0))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(w.ColorAt(r).Tuple))
+}
+
+func TestColorWhenRayHits(t *testing.T) {
+	// Scenario: The color when a ray hits
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When c ← color_at(w, r)
+	// Then c = color(0.38066, 0.47583, 0.2855)
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	assert.True(t, tuples.NewColor(0.38066, 0.47583, 0.2855).Equals(w.ColorAt(r).Tuple))
+}
+
+func TestColorWithIntersectionBehindRay(t *testing.T) {
+	// Scenario: The color with an intersection behind the ray
+	// Given w ← default_world()
+	// And outer ← the first object in w
+	// And outer.material.ambient ← 1
+	// And inner ← the second object in w
+	// And inner.material.ambient ← 1
+	// And r ← ray(point(0, 0, 0.75), vector(0, 0, -1))
+	// When c ← color_at(w, r)
+	// Then c = inner.material.color
+	w := world.Default()
+	for _, o := range w.Objects {
+		m := o.GetMaterial()
+		m.Ambient = 1
+		o.SetMaterial(m)
+	}
+	inner := w.Objects[1]
+	r := rays.New(tuples.Point(0, 0, 0.75), tuples.Vector(0, 0, -1))
+	assert.True(t, inner.GetMaterial().Color.Equals(w.ColorAt(r).Tuple))
+}
+
+func TestIsShadowed(t *testing.T) {
+	w := world.Default()
+	light := w.Lights[0]
+	tests := []struct {
+		name     string
+		point    tuples.Tuple
+		expected bool
+	}{
+		// Scenario: There is no shadow when nothing is collinear with point and light
+		{"nothing collinear", tuples.Point(0, 10, 0), false},
+		// Scenario: The shadow when an object is between the point and the light
+		{"object between point and light", tuples.Point(10, -10, 10), true},
+		// Scenario: There is no shadow when an object is behind the light
+		{"object behind light", tuples.Point(-20, 20, -20), false},
+		// Scenario: There is no shadow when an object is behind the point
+		{"object behind point", tuples.Point(-2, 2, -2), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, w.IsShadowed(tt.point, light.Samples(tt.point)[0], 0))
+		})
+	}
+}
+
+func TestShadowOfMovingObject(t *testing.T) {
+	// Scenario: A moving object only shadows a point while it is in the way
+	// Given w ← world with a light at point(0, 10, 0)
+	// And s ← sphere() moving from translation(0, 5, 0) to translation(5, 5, 0)
+	// Then intensity_at(light, point(0, 0, 0), w) = 0 at time 0
+	// And intensity_at(light, point(0, 0, 0), w) = 1 at time 1
+	w := world.New()
+	light := lights.NewPointLight(tuples.Point(0, 10, 0), tuples.NewColor(1, 1, 1))
+	w.Lights = []lights.Light{light}
+	s := spheres.NewSphere()
+	s.SetMotion(matrices.NewMotion(matrices.Translation(0, 5, 0), matrices.Translation(5, 5, 0)))
+	w.Objects = []shapes.Shape{s}
+	assert.InDelta(t, 0.0, w.IntensityAt(light, tuples.Point(0, 0, 0), 0), 0)
+	assert.InDelta(t, 1.0, w.IntensityAt(light, tuples.Point(0, 0, 0), 1), 0)
+}
+
+func TestPrepareComputationsForMovingObject(t *testing.T) {
+	// Scenario: The computations of a hit on a moving object use the ray's time
+	// Given s ← sphere() moving from translation(0, 0, 0) to translation(0, 0, 2)
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1)) cast at time 1
+	// When comps ← prepare_computations(intersect(s, r).hit, r)
+	// Then comps.time = 1
+	// And comps.point = point(0, 0, 1)
+	// And comps.normalv = vector(0, 0, -1)
+	s := spheres.NewSphere()
+	s.SetMotion(matrices.NewMotion(matrices.Identity(4), matrices.Translation(0, 0, 2)))
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	r.Time = 1
+	hit, found := s.Intersect(r).Hit()
+	require.True(t, found)
+	comps := world.PrepareComputations(hit, r)
+	assert.InDelta(t, 1.0, comps.Time, 0)
+	assert.True(t, tuples.Point(0, 0, 1).Equals(comps.Point))
+	assert.True(t, tuples.Vector(0, 0, -1).Equals(comps.NormalV))
+}
+
+func TestIntensityAtPointLight(t *testing.T) {
+	// Scenario Outline: Point lights evaluate the light intensity at a given point
+	// Given w ← default_world()
+	// And light ← w.lights[0]
+	// And pt ← <point>
+	// When intensity ← intensity_at(light, pt, w)
+	// Then intensity = <result>
+	w := world.Default()
+	light := w.Lights[0]
+	tests := []struct {
+		point    tuples.Tuple
+		expected float64
+	}{
+		{tuples.Point(0, 1.0001, 0), 1.0},
+		{tuples.Point(-1.0001, 0, 0), 1.0},
+		{tuples.Point(0, 0, -1.0001), 1.0},
+		{tuples.Point(0, 0, 1.0001), 0.0},
+		{tuples.Point(1.0001, 0, 0), 0.0},
+		{tuples.Point(0, -1.0001, 0), 0.0},
+		{tuples.Point(0, 0, 0), 0.0},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, w.IntensityAt(light, tt.point, 0), 0.00001, "point %v", tt.point)
+	}
+}
+
+func TestIntensityAtAreaLight(t *testing.T) {
+	// Scenario Outline: Area lights evaluate the light intensity at a given point
+	// Given w ← default_world()
+	// And corner ← point(-0.5, -0.5, -5)
+	// And light ← area_light(corner, vector(1, 0, 0), 2, vector(0, 1, 0), 2, color(1, 1, 1))
+	// And pt ← <point>
+	// When intensity ← intensity_at(light, pt, w)
+	// Then intensity = <result>
+	w := world.Default()
+	light := lights.NewAreaLight(tuples.Point(-0.5, -0.5, -5),
+		tuples.Vector(1, 0, 0), 2, tuples.Vector(0, 1, 0), 2, tuples.NewColor(1, 1, 1))
+	tests := []struct {
+		point    tuples.Tuple
+		expected float64
+	}{
+		{tuples.Point(0, 0, 2), 0.0},
+		{tuples.Point(1, -1, 2), 0.25},
+		{tuples.Point(1.5, 0, 2), 0.5},
+		{tuples.Point(1.25, 1.25, 3), 0.75},
+		{tuples.Point(0, 0, -2), 1.0},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, w.IntensityAt(light, tt.point, 0), 0.00001, "point %v", tt.point)
+	}
+}
+
+func TestDirectionalLightShadowsAtAnyDistance(t *testing.T) {
+	// Scenario: Objects block a directional light however far away they are
+	// Given w ← world()
+	// And w.light ← directional_light(vector(0, -1, 0), color(1, 1, 1))
+	// And w contains a sphere 1000 units above the origin
+	// Then is_shadowed(w, point(0, 0, 0), light) is true
+	// And is_shadowed(w, point(5, 0, 0), light) is false
+	w := world.New()
+	light := lights.NewDirectionalLight(tuples.Vector(0, -1, 0), tuples.NewColor(1, 1, 1))
+	w.Lights = []lights.Light{light}
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Translation(0, 1000, 0))
+	w.Objects = append(w.Objects, s)
+	for _, tt := range []struct {
+		point    tuples.Tuple
+		expected float64
+	}{
+		{tuples.Point(0, 0, 0), 0},
+		{tuples.Point(5, 0, 0), 1},
+	} {
+		assert.InDelta(t, tt.expected, w.IntensityAt(light, tt.point, 0), 0.00001, "point %v", tt.point)
+	}
+}
+
+func TestShadingOutsideSpotLightCone(t *testing.T) {
+	// Scenario: A spot light does not light points outside its cone
+	// Given w ← default_world() lit by a spot light at point(0, 0, -10)
+	// And the spot light points along vector(0, 1, 0), away from the spheres
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// Then color_at(w, r) = color(0, 0, 0)
+	// When the spot light points along vector(0, 0, 1), at the spheres
+	// Then color_at(w, r) is the color lit by a point light at point(0, 0, -10)
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	w.Lights = []lights.Light{lights.NewSpotLight(tuples.Point(0, 0, -10), tuples.Vector(0, 1, 0),
+		math.Pi/8, math.Pi/6, tuples.NewColor(1, 1, 1))}
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(w.ColorAt(r).Tuple))
+
+	w.Lights = []lights.Light{lights.NewSpotLight(tuples.Point(0, 0, -10), tuples.Vector(0, 0, 1),
+		math.Pi/8, math.Pi/6, tuples.NewColor(1, 1, 1))}
+	spot := w.ColorAt(r)
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	assert.True(t, w.ColorAt(r).Equals(spot.Tuple))
+}
+
+func TestShadingCSGUsesChildMaterial(t *testing.T) {
+	// Scenario: Shading a CSG hit uses the material of the child that was hit
+	// Given w ← world()
+	// And w.light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	// And left ← sphere() with material color(1, 0, 0)
+	// And right ← sphere() with transform translation(0, 0, 0.5)
+	// And w contains csg("difference", left, right)
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When c ← color_at(w, r)
+	// Then c = color(1.9, 0.9, 0.9)
+	w := world.New()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	left := spheres.NewSphere()
+	m := left.GetMaterial()
+	m.Color = tuples.NewColor(1, 0, 0)
+	left.SetMaterial(m)
+	right := spheres.NewSphere()
+	right.SetTransform(matrices.Translation(0, 0, 0.5))
+	w.Objects = append(w.Objects, csg.New(csg.Difference, left, right))
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	assert.True(t, tuples.NewColor(1.9, 0.9, 0.9).Equals(w.ColorAt(r).Tuple))
+}
+
+func TestShadingUsesPatternInObjectSpace(t *testing.T) {
+	// Scenario: Shading a hit uses the material's pattern at the object point
+	// Given w ← world()
+	// And w.light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	// And s ← sphere() with transform scaling(2, 2, 2)
+	// And s.material has ambient 1, diffuse 0 and specular 0
+	// And s.material.pattern ← texture_map(uv_checkers(2, 2, white, green), spherical_map)
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When c ← color_at(w, r)
+	// Then c = green, the color at point(0, 0, -1) in object space
+	w := world.New()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	green := tuples.NewColor(0, 1, 0)
+	s := spheres.NewSphere()
+	s.SetTransform(matrices.Scaling(2, 2, 2))
+	m := s.GetMaterial()
+	m.Ambient, m.Diffuse, m.Specular = 1, 0, 0
+	m.Pattern = patterns.NewTextureMap(
+		patterns.NewUVCheckers(2, 2, tuples.NewColor(1, 1, 1), green), patterns.SphericalMap)
+	s.SetMaterial(m)
+	w.Objects = append(w.Objects, s)
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	assert.True(t, green.Equals(w.ColorAt(r).Tuple))
+}
+
+func TestFootprintOfSpreadingRay(t *testing.T) {
+	// Scenario Outline: The footprint of a ray's cone on a surface
+	// Given shape ← sphere()
+	// And r ← ray(<origin>, <direction>) with spread 0.01
+	// And i ← intersection(<t>, shape)
+	// When comps ← prepare_computations(i, r)
+	// And dx, dy ← footprint_axes(comps)
+	// Then dx and dy are perpendicular to the normal
+	// And |dx| = comps.footprint = 0.01 × <t>
+	// And |dy| = comps.footprint / cos(angle between the ray and the normal)
+	tests := []struct {
+		name      string
+		origin    tuples.Tuple
+		direction tuples.Tuple
+		t         float64
+		cosine    float64
+	}{
+		{"head on", tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1), 4, 1},
+		{"oblique", tuples.Point(-5, 0.6, 0), tuples.Vector(1, 0, 0), 4.2, 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rays.New(tt.origin, tt.direction)
+			r.Spread = 0.01
+			comps := world.PrepareComputations(intersections.NewIntersection(tt.t, spheres.NewSphere()), r)
+			dx, dy := comps.FootprintAxes()
+			assert.InEpsilon(t, 0.01*tt.t, comps.Footprint, 0.00001)
+			assert.InDelta(t, 0, dx.Dot(comps.NormalV), 0.00001)
+			assert.InDelta(t, 0, dy.Dot(comps.NormalV), 0.00001)
+			assert.InEpsilon(t, comps.Footprint, tuples.Magnitude(dx), 0.00001)
+			assert.InEpsilon(t, comps.Footprint/tt.cosine, tuples.Magnitude(dy), 0.00001)
+		})
+	}
+}
+
+func TestFootprintOfThinRay(t *testing.T) {
+	// Scenario: A ray without spread has no footprint
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, spheres.NewSphere()), r)
+	dx, dy := comps.FootprintAxes()
+	assert.True(t, tuples.Vector(0, 0, 0).Equals(dx))
+	assert.True(t, tuples.Vector(0, 0, 0).Equals(dy))
+}
+
+// mirrorWorld returns a world holding a perfectly reflective black sphere at
+// the origin, lit from the front, in front of a red background.
+func mirrorWorld() *world.World {
+	w := world.New()
+	w.Lights = []lights.Light{lights.NewPointLight(tuples.Point(0, 0, -10), tuples.NewColor(1, 1, 1))}
+	w.Background = backgrounds.NewSolid(tuples.NewColor(1, 0, 0))
+	s := spheres.NewSphere()
+	m := s.GetMaterial()
+	m.Color = tuples.NewColor(0, 0, 0)
+	m.Specular = 0
+	m.Reflective = 1
+	s.SetMaterial(m)
+	w.Objects = append(w.Objects, s)
+	return w
+}
+
+func TestPrecomputingReflectionVector(t *testing.T) {
+	// Scenario: Precomputing the reflection vector
+	// Given shape ← sphere()
+	// And r ← ray(point(0, 0.6, -5), vector(0, 0, 1))
+	// And i ← intersection(4.2, shape)
+	// When comps ← prepare_computations(i, r)
+	// Then comps.reflectv = vector(0, 0.96, -0.28)
+	r := rays.New(tuples.Point(0, 0.6, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4.2, spheres.NewSphere()), r)
+	assert.True(t, tuples.Vector(0, 0.96, -0.28).Equals(comps.ReflectV))
+}
+
+func TestReflectedColorForNonreflectiveMaterial(t *testing.T) {
+	// Scenario: The reflected color for a nonreflective material
+	// Given w ← default_world()
+	// And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	// And shape ← the second object in w
+	// And shape.material.ambient ← 1
+	// And i ← intersection(1, shape)
+	// When comps ← prepare_computations(i, r)
+	// And color ← reflected_color(w, comps)
+	// Then color = color(0, 0, 0)
+	w := world.Default()
+	r := rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 0, 1))
+	shape := w.Objects[1]
+	m := shape.GetMaterial()
+	m.Ambient = 1
+	shape.SetMaterial(m)
+	comps := world.PrepareComputations(intersections.NewIntersection(1, shape), r)
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(w.ReflectedColor(comps, world.MaxDepth).Tuple))
+}
+
+func TestRayMissingEverythingSeesBackground(t *testing.T) {
+	// Scenario: A ray that misses every object sees the background
+	// Given w ← default_world() with a gradient background from black to white
+	// When r ← ray(point(0, 0, -5), vector(0, 1, 0))
+	// Then color_at(w, r) = white
+	// When r ← ray(point(0, 0, -5), vector(1, 0, 0))
+	// Then color_at(w, r) = color(0.5, 0.5, 0.5)
+	w := world.Default()
+	w.Background = backgrounds.NewGradient(tuples.NewColor(0, 0, 0), tuples.NewColor(1, 1, 1))
+	up := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 1, 0)))
+	assert.True(t, tuples.NewColor(1, 1, 1).Equals(up.Tuple))
+	sideways := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(1, 0, 0)))
+	assert.True(t, tuples.NewColor(0.5, 0.5, 0.5).Equals(sideways.Tuple))
+}
+
+func TestReflectingBackground(t *testing.T) {
+	// Scenario: Reflections only show the background when asked to
+	// Given w ← a world holding a mirror sphere in front of a red background
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1)), reflected straight back
+	// Then color_at(w, r) = color(0, 0, 0)
+	// When w.reflect_background ← true
+	// Then color_at(w, r) = color(1, 0, 0)
+	w := mirrorWorld()
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(w.ColorAt(r).Tuple))
+	w.ReflectBackground = true
+	assert.True(t, tuples.NewColor(1, 0, 0).Equals(w.ColorAt(r).Tuple))
+}
+
+func TestReflectedColorForReflectiveMaterial(t *testing.T) {
+	// Scenario: The reflected color for a reflective material
+	// Given w ← a world holding a mirror sphere
+	// And a sphere at point(0, 0, -3) with color(0, 1, 0) and ambient 1
+	// And r ← ray(point(0, 0, -2), vector(0, 0, 1))
+	// When color ← color_at(w, r)
+	// Then color = color(0, 1, 0), the green sphere seen in the mirror
+	w := mirrorWorld()
+	green := spheres.NewSphere()
+	green.SetTransform(matrices.Translation(0, 0, -3).Multiply(matrices.Scaling(0.5, 0.5, 0.5)))
+	m := green.GetMaterial()
+	m.Color = tuples.NewColor(0, 1, 0)
+	m.Ambient, m.Diffuse, m.Specular = 1, 0, 0
+	green.SetMaterial(m)
+	w.Objects = append(w.Objects, green)
+	r := rays.New(tuples.Point(0, 0, -2), tuples.Vector(0, 0, 1))
+	assert.True(t, tuples.NewColor(0, 1, 0).Equals(w.ColorAt(r).Tuple))
+}
+
+func TestColorAtWithMutuallyReflectiveSurfaces(t *testing.T) {
+	// Scenario: color_at() with mutually reflective surfaces
+	// Given w ← a world holding a mirror sphere
+	// And r ← ray(point(0, 0, 0), vector(0, 1, 0)), inside the sphere
+	// Then color_at(w, r) should terminate successfully
+	w := mirrorWorld()
+	r := rays.New(tuples.Point(0, 0, 0), tuples.Vector(0, 1, 0))
+	assert.NotPanics(t, func() { w.ColorAt(r) })
+}
+
+func TestReflectedColorAtMaximumDepth(t *testing.T) {
+	// Scenario: The reflected color at the maximum recursive depth
+	// Given w ← a world holding a mirror sphere, reflecting the red background
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And i ← intersection(4, the sphere)
+	// When comps ← prepare_computations(i, r)
+	// Then reflected_color(w, comps, 1) = color(1, 0, 0)
+	// And reflected_color(w, comps, 0) = color(0, 0, 0)
+	w := mirrorWorld()
+	w.ReflectBackground = true
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, w.Objects[0]), r)
+	assert.True(t, tuples.NewColor(1, 0, 0).Equals(w.ReflectedColor(comps, 1).Tuple))
+	assert.True(t, tuples.NewColor(0, 0, 0).Equals(w.ReflectedColor(comps, 0).Tuple))
+}
+
+// tilt is a bump that always turns the normal to a fixed direction.
+type tilt struct {
+	normal tuples.Tuple
+}
+
+func (b tilt) Perturb(_, _ tuples.Tuple) tuples.Tuple {
+	return b.normal
+}
+
+func TestBumpedNormalIsUsedForShadingOnly(t *testing.T) {
+	// Scenario: A bump changes the shading normal but not the over point
+	// Given shape ← sphere() with transform rotation_z(π/2)
+	// And shape.material.bump turns every normal to vector(1, 0, 0) in object space
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And i ← intersection(4, shape)
+	// When comps ← prepare_computations(i, r)
+	// Then comps.normalv = vector(0, 1, 0)
+	// And comps.over_point.z < -1
+	// And comps.inside = false
+	shape := spheres.NewSphere()
+	shape.SetTransform(matrices.RotationZ(math.Pi / 2))
+	m := shape.GetMaterial()
+	m.Bump = tilt{tuples.Vector(1, 0, 0)}
+	shape.SetMaterial(m)
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, shape), r)
+	assert.True(t, tuples.Vector(0, 1, 0).Equals(comps.NormalV))
+	assert.Less(t, comps.OverPoint.Z, -1.0)
+	assert.False(t, comps.Inside)
+}
+
+func TestUnderPointAndRaysThroughTheSurface(t *testing.T) {
+	// Scenario: Rays leaving through the surface start just beneath it
+	// Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// And shape ← sphere()
+	// When comps ← prepare_computations(intersection(4, shape), r)
+	// Then comps.under_point.z > -1 > comps.over_point.z
+	// And ray_towards(comps, vector(0, 0, 1)) starts at comps.under_point
+	// And ray_towards(comps, vector(0, 0, -1)) starts at comps.over_point
+	r := rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1))
+	comps := world.PrepareComputations(intersections.NewIntersection(4, spheres.NewSphere()), r)
+	assert.Greater(t, comps.UnderPoint.Z, -1.0)
+	assert.Less(t, comps.OverPoint.Z, -1.0)
+	assert.True(t, comps.UnderPoint.Equals(comps.RayTowards(tuples.Vector(0, 0, 1)).Origin))
+	assert.True(t, comps.OverPoint.Equals(comps.RayTowards(tuples.Vector(0, 0, -1)).Origin))
+}
+
+func TestShadingLambertianSurface(t *testing.T) {
+	// Scenario: A Lambertian surface is as bright as the cosine of the light
+	// Given w ← default_world()
+	// And the outer sphere's material has a Lambertian BSDF
+	// And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	// When c ← color_at(w, r)
+	// Then c is the color of the sphere times the cosine of the light, 9/√281
+	w := world.Default()
+	outer := w.Objects[0]
+	m := outer.GetMaterial()
+	m.BSDF = bsdfs.Lambertian{}
+	outer.SetMaterial(m)
+	c := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	assert.True(t, m.Color.Multiply(9/math.Sqrt(281)).Equals(c.Tuple), "%v", c)
+}
+
+func TestClearGlassConservesLight(t *testing.T) {
+	// Scenario: A clear glass ball in front of a white background neither adds nor removes light
+	// Given w ← world() with a white background that shows in reflections
+	// And a sphere of clear glass with an index of refraction of 1.5
+	// When c ← color_at(w, ray(point(0, 0, -5), vector(0, 0, 1)))
+	// Then c is nearly white: 4% reflected at the front and the rest passed through
+	w := world.New()
+	w.Background = backgrounds.NewSolid(tuples.NewColor(1, 1, 1))
+	w.ReflectBackground = true
+	glass := spheres.NewSphere()
+	glass.Material.BSDF = bsdfs.NewDielectric(0, 1.5)
+	w.Objects = append(w.Objects, glass)
+	c := w.ColorAt(rays.New(tuples.Point(0, 0, -5), tuples.Vector(0, 0, 1)))
+	assert.InDelta(t, 1, c.Red(), 0.001)
+}